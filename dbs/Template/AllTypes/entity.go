@@ -5,11 +5,21 @@ package AllTypes
 // ---------------------------------------------------------------
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
+	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+
+	"github.com/rah-0/margo-test/dbs/Template/Shared"
 )
 
 const (
@@ -60,64 +70,146 @@ const (
 )
 
 var (
-	Fields    = []string{FieldId, FieldTinySigned, FieldTinyUnsigned, FieldSmallSigned, FieldSmallUnsigned, FieldMediumSigned, FieldMediumUnsigned, FieldIntSigned, FieldIntUnsigned, FieldBigSigned, FieldBigUnsigned, FieldFloatField, FieldDoubleField, FieldRealField, FieldDecimalField, FieldDecField, FieldNumericField, FieldFixedField, FieldBit1, FieldBit8, FieldBit64, FieldBoolField, FieldBooleanField, FieldCharField, FieldVarcharField, FieldTextField, FieldTinytextField, FieldMediumtextField, FieldLongtextField, FieldEnumField, FieldSetField, FieldBinaryField, FieldVarbinaryField, FieldBlobField, FieldTinyblobField, FieldMediumblobField, FieldLongblobField, FieldDateField, FieldTimeField, FieldYearField, FieldDatetimeField, FieldTimestampField, FieldUuidField}
-	db        *sql.DB
-	stmtMu    sync.RWMutex
-	stmtCache = make(map[string]*sql.Stmt)
+	Fields              = []string{FieldId, FieldTinySigned, FieldTinyUnsigned, FieldSmallSigned, FieldSmallUnsigned, FieldMediumSigned, FieldMediumUnsigned, FieldIntSigned, FieldIntUnsigned, FieldBigSigned, FieldBigUnsigned, FieldFloatField, FieldDoubleField, FieldRealField, FieldDecimalField, FieldDecField, FieldNumericField, FieldFixedField, FieldBit1, FieldBit8, FieldBit64, FieldBoolField, FieldBooleanField, FieldCharField, FieldVarcharField, FieldTextField, FieldTinytextField, FieldMediumtextField, FieldLongtextField, FieldEnumField, FieldSetField, FieldBinaryField, FieldVarbinaryField, FieldBlobField, FieldTinyblobField, FieldMediumblobField, FieldLongblobField, FieldDateField, FieldTimeField, FieldYearField, FieldDatetimeField, FieldTimestampField, FieldUuidField}
+	PrimaryKey          = []string{FieldId}
+	UniqueKeys          = map[string][]string{"PRIMARY": {FieldId}, "uuid_field": {FieldUuidField}}
+	nonPrimaryKeyFields = []string{FieldTinySigned, FieldTinyUnsigned, FieldSmallSigned, FieldSmallUnsigned, FieldMediumSigned, FieldMediumUnsigned, FieldIntSigned, FieldIntUnsigned, FieldBigSigned, FieldBigUnsigned, FieldFloatField, FieldDoubleField, FieldRealField, FieldDecimalField, FieldDecField, FieldNumericField, FieldFixedField, FieldBit1, FieldBit8, FieldBit64, FieldBoolField, FieldBooleanField, FieldCharField, FieldVarcharField, FieldTextField, FieldTinytextField, FieldMediumtextField, FieldLongtextField, FieldEnumField, FieldSetField, FieldBinaryField, FieldVarbinaryField, FieldBlobField, FieldTinyblobField, FieldMediumblobField, FieldLongblobField, FieldDateField, FieldTimeField, FieldYearField, FieldDatetimeField, FieldTimestampField, FieldUuidField}
+	defaultClient       = &Client{primary: newConn(nil, false)}
+	// ReplicaCooldown is how long a replica with a broken connection is skipped.
+	ReplicaCooldown = 30 * time.Second
+	// MaxAllowedPacket should match the server's max_allowed_packet, DBInsertMany keeps each batch below it.
+	MaxAllowedPacket = 16 << 20
+	// ValidateOnWrite makes inserts, upserts and updates run ValidateFields on
+	// the written fields after the Before hooks and fail without sending SQL.
+	ValidateOnWrite = false
 )
 
 type Entity struct {
-	Id              string `json:",omitempty,omitzero"`
-	TinySigned      string `json:",omitempty,omitzero"`
-	TinyUnsigned    string `json:",omitempty,omitzero"`
-	SmallSigned     string `json:",omitempty,omitzero"`
-	SmallUnsigned   string `json:",omitempty,omitzero"`
-	MediumSigned    string `json:",omitempty,omitzero"`
-	MediumUnsigned  string `json:",omitempty,omitzero"`
-	IntSigned       string `json:",omitempty,omitzero"`
-	IntUnsigned     string `json:",omitempty,omitzero"`
-	BigSigned       string `json:",omitempty,omitzero"`
-	BigUnsigned     string `json:",omitempty,omitzero"`
-	FloatField      string `json:",omitempty,omitzero"`
-	DoubleField     string `json:",omitempty,omitzero"`
-	RealField       string `json:",omitempty,omitzero"`
-	DecimalField    string `json:",omitempty,omitzero"`
-	DecField        string `json:",omitempty,omitzero"`
-	NumericField    string `json:",omitempty,omitzero"`
-	FixedField      string `json:",omitempty,omitzero"`
-	Bit1            string `json:",omitempty,omitzero"`
-	Bit8            string `json:",omitempty,omitzero"`
-	Bit64           string `json:",omitempty,omitzero"`
-	BoolField       string `json:",omitempty,omitzero"`
-	BooleanField    string `json:",omitempty,omitzero"`
-	CharField       string `json:",omitempty,omitzero"`
-	VarcharField    string `json:",omitempty,omitzero"`
-	TextField       string `json:",omitempty,omitzero"`
-	TinytextField   string `json:",omitempty,omitzero"`
-	MediumtextField string `json:",omitempty,omitzero"`
-	LongtextField   string `json:",omitempty,omitzero"`
-	EnumField       string `json:",omitempty,omitzero"`
-	SetField        string `json:",omitempty,omitzero"`
-	BinaryField     string `json:",omitempty,omitzero"`
-	VarbinaryField  string `json:",omitempty,omitzero"`
-	BlobField       string `json:",omitempty,omitzero"`
-	TinyblobField   string `json:",omitempty,omitzero"`
-	MediumblobField string `json:",omitempty,omitzero"`
-	LongblobField   string `json:",omitempty,omitzero"`
-	DateField       string `json:",omitempty,omitzero"`
-	TimeField       string `json:",omitempty,omitzero"`
-	YearField       string `json:",omitempty,omitzero"`
-	DatetimeField   string `json:",omitempty,omitzero"`
-	TimestampField  string `json:",omitempty,omitzero"`
-	UuidField       string `json:",omitempty,omitzero"`
+	Id              int32     `json:",omitempty,omitzero"`
+	TinySigned      int8      `json:",omitempty,omitzero"`
+	TinyUnsigned    uint8     `json:",omitempty,omitzero"`
+	SmallSigned     int16     `json:",omitempty,omitzero"`
+	SmallUnsigned   uint16    `json:",omitempty,omitzero"`
+	MediumSigned    int32     `json:",omitempty,omitzero"`
+	MediumUnsigned  uint32    `json:",omitempty,omitzero"`
+	IntSigned       int32     `json:",omitempty,omitzero"`
+	IntUnsigned     uint32    `json:",omitempty,omitzero"`
+	BigSigned       int64     `json:",omitempty,omitzero"`
+	BigUnsigned     uint64    `json:",omitempty,omitzero"`
+	FloatField      float32   `json:",omitempty,omitzero"`
+	DoubleField     float64   `json:",omitempty,omitzero"`
+	RealField       float64   `json:",omitempty,omitzero"`
+	DecimalField    string    `json:",omitempty,omitzero"`
+	DecField        string    `json:",omitempty,omitzero"`
+	NumericField    string    `json:",omitempty,omitzero"`
+	FixedField      string    `json:",omitempty,omitzero"`
+	Bit1            bool      `json:",omitempty,omitzero"`
+	Bit8            uint64    `json:",omitempty,omitzero"`
+	Bit64           uint64    `json:",omitempty,omitzero"`
+	BoolField       bool      `json:",omitempty,omitzero"`
+	BooleanField    bool      `json:",omitempty,omitzero"`
+	CharField       string    `json:",omitempty,omitzero"`
+	VarcharField    string    `json:",omitempty,omitzero"`
+	TextField       string    `json:",omitempty,omitzero"`
+	TinytextField   string    `json:",omitempty,omitzero"`
+	MediumtextField string    `json:",omitempty,omitzero"`
+	LongtextField   string    `json:",omitempty,omitzero"`
+	EnumField       string    `json:",omitempty,omitzero"`
+	SetField        string    `json:",omitempty,omitzero"`
+	BinaryField     []byte    `json:",omitempty,omitzero"`
+	VarbinaryField  []byte    `json:",omitempty,omitzero"`
+	BlobField       []byte    `json:",omitempty,omitzero"`
+	TinyblobField   []byte    `json:",omitempty,omitzero"`
+	MediumblobField []byte    `json:",omitempty,omitzero"`
+	LongblobField   []byte    `json:",omitempty,omitzero"`
+	DateField       time.Time `json:",omitempty,omitzero"`
+	TimeField       string    `json:",omitempty,omitzero"`
+	YearField       uint16    `json:",omitempty,omitzero"`
+	DatetimeField   time.Time `json:",omitempty,omitzero"`
+	TimestampField  time.Time `json:",omitempty,omitzero"`
+	UuidField       string    `json:",omitempty,omitzero"`
+
+	// loaded is set on tracked entities, see ChangedFields.
+	loaded *entitySnapshot
+}
+
+// Null holds the value of a nullable column, keeping SQL NULL distinct from the zero value.
+type Null[T any] struct {
+	V     T
+	Valid bool
+}
+
+func NewNull[T any](v T) Null[T] {
+	return Null[T]{V: v, Valid: true}
+}
+
+func (n *Null[T]) Scan(value any) error {
+	return (*sql.Null[T])(n).Scan(value)
+}
+
+func (n Null[T]) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	if v, ok := any(n.V).(uint64); ok {
+		return v, nil
+	}
+	return driver.DefaultParameterConverter.ConvertValue(n.V)
+}
+
+func (n Null[T]) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(n.V)
+}
+
+func (n *Null[T]) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		*n = Null[T]{}
+		return nil
+	}
+	if err := json.Unmarshal(b, &n.V); err != nil {
+		return err
+	}
+	n.Valid = true
+	return nil
+}
+
+type InsertManyResult struct {
+	RowsAffected int64
+	Batches      int
+	BatchErrors  []*BatchError
+	Error        error
+}
+
+type BatchError struct {
+	Batch  int
+	Offset int
+	Count  int
+	Err    error
+}
+
+func (e *BatchError) Error() string {
+	return "batch " + strconv.Itoa(e.Batch) + " (entities " + strconv.Itoa(e.Offset) + "-" + strconv.Itoa(e.Offset+e.Count-1) + "): " + e.Err.Error()
+}
+
+func (e *BatchError) Unwrap() error {
+	return e.Err
 }
 
 type QueryParams struct {
-	Select []string
-	Where  []string
-	Insert []string
-	Update []string
-	Params []any
+	Select     []string
+	Where      []string
+	Insert     []string
+	Update     []string
+	Conditions []*Condition
+	OrderBy    []Order
+	Limit      int
+	Offset     int
+	BatchSize  int
+	Params     []any
+	Track      bool
 }
 
 func NewQueryParams() *QueryParams {
@@ -129,6 +221,12 @@ func (qp *QueryParams) WithSelect(fields ...string) *QueryParams {
 	return qp
 }
 
+// WithTracking makes reads snapshot the entities they return, see ChangedFields.
+func (qp *QueryParams) WithTracking() *QueryParams {
+	qp.Track = true
+	return qp
+}
+
 func (qp *QueryParams) WithWhere(fields ...string) *QueryParams {
 	qp.Where = fields
 	return qp
@@ -144,538 +242,1547 @@ func (qp *QueryParams) WithUpdate(fields ...string) *QueryParams {
 	return qp
 }
 
+func (qp *QueryParams) WithConditions(conditions ...*Condition) *QueryParams {
+	qp.Conditions = conditions
+	return qp
+}
+
+func (qp *QueryParams) WithOrderBy(orders ...Order) *QueryParams {
+	qp.OrderBy = orders
+	return qp
+}
+
+func (qp *QueryParams) WithLimit(limit int) *QueryParams {
+	qp.Limit = limit
+	return qp
+}
+
+func (qp *QueryParams) WithOffset(offset int) *QueryParams {
+	qp.Offset = offset
+	return qp
+}
+
+func (qp *QueryParams) WithBatchSize(size int) *QueryParams {
+	qp.BatchSize = size
+	return qp
+}
+
 func (qp *QueryParams) WithParams(params ...any) *QueryParams {
 	qp.Params = params
 	return qp
 }
 
+// UpsertOutcome reports what an upsert did to the row, derived from MariaDB's
+// affected-rows count: 1 for an insert, 2 for an update or replace and 0 for a
+// row that was left unchanged or ignored. Connections opened with
+// clientFoundRows=true report unchanged rows as updated.
+type UpsertOutcome int
+
+const (
+	UpsertUnknown UpsertOutcome = iota
+	UpsertUnchanged
+	UpsertInserted
+	UpsertUpdated
+)
+
+func (o UpsertOutcome) String() string {
+	switch o {
+	case UpsertUnchanged:
+		return "unchanged"
+	case UpsertInserted:
+		return "inserted"
+	case UpsertUpdated:
+		return "updated"
+	}
+	return "unknown"
+}
+
 type QueryResult struct {
-	Entities []*Entity
-	Entity   *Entity
-	Error    error
-	Result   sql.Result
-	Exists   bool
+	Entities   []*Entity
+	Entity     *Entity
+	Error      error
+	Result     sql.Result
+	Exists     bool
+	NextCursor string
+	Outcome    UpsertOutcome
 }
 
-func SetDB(x *sql.DB) error {
-	db = x
-	return nil
+// FieldError is a field whose value does not fit its column.
+type FieldError struct {
+	Field  string
+	Reason string
 }
 
-func (x *Entity) GetFieldValue(field string) any {
-	switch field {
-	case FieldId:
-		return x.Id
-	case FieldTinySigned:
-		return x.TinySigned
-	case FieldTinyUnsigned:
-		return x.TinyUnsigned
-	case FieldSmallSigned:
-		return x.SmallSigned
-	case FieldSmallUnsigned:
-		return x.SmallUnsigned
-	case FieldMediumSigned:
-		return x.MediumSigned
-	case FieldMediumUnsigned:
-		return x.MediumUnsigned
-	case FieldIntSigned:
-		return x.IntSigned
-	case FieldIntUnsigned:
-		return x.IntUnsigned
-	case FieldBigSigned:
-		return x.BigSigned
-	case FieldBigUnsigned:
-		return x.BigUnsigned
-	case FieldFloatField:
-		return x.FloatField
-	case FieldDoubleField:
-		return x.DoubleField
-	case FieldRealField:
-		return x.RealField
-	case FieldDecimalField:
-		return x.DecimalField
-	case FieldDecField:
-		return x.DecField
-	case FieldNumericField:
-		return x.NumericField
-	case FieldFixedField:
-		return x.FixedField
-	case FieldBit1:
-		return x.Bit1
-	case FieldBit8:
-		return x.Bit8
-	case FieldBit64:
-		return x.Bit64
-	case FieldBoolField:
-		return x.BoolField
-	case FieldBooleanField:
-		return x.BooleanField
-	case FieldCharField:
-		return x.CharField
-	case FieldVarcharField:
-		return x.VarcharField
-	case FieldTextField:
-		return x.TextField
-	case FieldTinytextField:
-		return x.TinytextField
-	case FieldMediumtextField:
-		return x.MediumtextField
-	case FieldLongtextField:
-		return x.LongtextField
-	case FieldEnumField:
-		return x.EnumField
-	case FieldSetField:
-		return x.SetField
-	case FieldBinaryField:
-		return x.BinaryField
-	case FieldVarbinaryField:
-		return x.VarbinaryField
-	case FieldBlobField:
-		return x.BlobField
-	case FieldTinyblobField:
-		return x.TinyblobField
-	case FieldMediumblobField:
-		return x.MediumblobField
-	case FieldLongblobField:
-		return x.LongblobField
-	case FieldDateField:
-		return x.DateField
-	case FieldTimeField:
-		return x.TimeField
-	case FieldYearField:
-		return x.YearField
-	case FieldDatetimeField:
-		return x.DatetimeField
-	case FieldTimestampField:
-		return x.TimestampField
-	case FieldUuidField:
-		return x.UuidField
-	}
-	return nil
+// ValidationError lists every field that failed Validate.
+type ValidationError struct {
+	Errors []FieldError
 }
 
-func (x *Entity) GetFieldsValues(fieldList []string) []any {
-	values := make([]any, 0, len(fieldList))
-	for _, field := range fieldList {
-		values = append(values, x.GetFieldValue(field))
+func (e *ValidationError) Error() string {
+	parts := make([]string, 0, len(e.Errors))
+	for _, fe := range e.Errors {
+		parts = append(parts, fe.Field+": "+fe.Reason)
 	}
-	return values
+	return "validation failed: " + strings.Join(parts, "; ")
 }
 
-func GetValuePlaceholder(field string) string {
-	switch field {
-	case FieldId:
-		return "?"
-	case FieldTinySigned:
-		return "?"
-	case FieldTinyUnsigned:
-		return "?"
-	case FieldSmallSigned:
-		return "?"
-	case FieldSmallUnsigned:
-		return "?"
-	case FieldMediumSigned:
-		return "?"
-	case FieldMediumUnsigned:
-		return "?"
-	case FieldIntSigned:
-		return "?"
-	case FieldIntUnsigned:
-		return "?"
-	case FieldBigSigned:
-		return "?"
-	case FieldBigUnsigned:
-		return "?"
-	case FieldFloatField:
-		return "?"
-	case FieldDoubleField:
-		return "?"
-	case FieldRealField:
-		return "?"
-	case FieldDecimalField:
-		return "?"
-	case FieldDecField:
-		return "?"
-	case FieldNumericField:
-		return "?"
-	case FieldFixedField:
-		return "?"
-	case FieldBit1:
-		return "?"
-	case FieldBit8:
-		return "?"
-	case FieldBit64:
-		return "?"
-	case FieldBoolField:
-		return "?"
-	case FieldBooleanField:
-		return "?"
-	case FieldCharField:
-		return "?"
-	case FieldVarcharField:
-		return "?"
-	case FieldTextField:
-		return "?"
-	case FieldTinytextField:
-		return "?"
-	case FieldMediumtextField:
-		return "?"
-	case FieldLongtextField:
-		return "?"
-	case FieldEnumField:
-		return "?"
-	case FieldSetField:
-		return "?"
-	case FieldBinaryField:
-		return "?"
-	case FieldVarbinaryField:
-		return "?"
-	case FieldBlobField:
-		return "?"
-	case FieldTinyblobField:
-		return "?"
-	case FieldMediumblobField:
-		return "?"
-	case FieldLongblobField:
-		return "?"
-	case FieldDateField:
-		return "?"
-	case FieldTimeField:
-		return "?"
-	case FieldYearField:
-		return "?"
-	case FieldDatetimeField:
-		return "?"
-	case FieldTimestampField:
-		return "?"
-	case FieldUuidField:
-		return "?"
-	}
-	return ""
+// DBError is a MariaDB error classified by its error number, see Shared.DBError.
+type DBError = Shared.DBError
+
+var (
+	ErrDuplicateKey        = Shared.ErrDuplicateKey
+	ErrForeignKeyViolation = Shared.ErrForeignKeyViolation
+	ErrDataTooLong         = Shared.ErrDataTooLong
+	ErrDeadlock            = Shared.ErrDeadlock
+	ErrLockTimeout         = Shared.ErrLockTimeout
+)
+
+// BeforeInserter and the other hook interfaces below are checked for by the
+// generated operations, implement them on *Entity in a hand-written file of
+// this package. A hook gets the operation's context, context.Background()
+// when it has none, and transaction. An error from a Before hook aborts the
+// operation, one from an After hook is returned after the statement has run.
+type BeforeInserter interface {
+	BeforeInsert(ctx context.Context, tx *sql.Tx) error
 }
 
-func GetValuesPlaceholders(fieldList []string) []string {
-	placeholders := make([]string, 0, len(fieldList))
-	for _, field := range fieldList {
-		placeholders = append(placeholders, GetValuePlaceholder(field))
-	}
-	return placeholders
+type AfterInserter interface {
+	AfterInsert(ctx context.Context, tx *sql.Tx) error
 }
 
-func GetQualifiedField(field string) string {
-	switch field {
-	case FieldId:
-		return FQTN + ".`" + FieldId + "`"
-	case FieldTinySigned:
-		return FQTN + ".`" + FieldTinySigned + "`"
+type BeforeUpdater interface {
+	BeforeUpdate(ctx context.Context, tx *sql.Tx) error
+}
+
+type AfterUpdater interface {
+	AfterUpdate(ctx context.Context, tx *sql.Tx) error
+}
+
+type BeforeDeleter interface {
+	BeforeDelete(ctx context.Context, tx *sql.Tx) error
+}
+
+type AfterDeleter interface {
+	AfterDelete(ctx context.Context, tx *sql.Tx) error
+}
+
+// AfterLoader is called for every entity read from the database, including
+// the rows returned by RETURNING and by named queries.
+type AfterLoader interface {
+	AfterLoad(ctx context.Context, tx *sql.Tx) error
+}
+
+func runHook(ctx context.Context, tx *sql.Tx, x *Entity, hook string) error {
+	if x == nil {
+		return nil
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	switch hook {
+	case "BeforeInsert":
+		if h, ok := any(x).(BeforeInserter); ok {
+			return h.BeforeInsert(ctx, tx)
+		}
+	case "AfterInsert":
+		if h, ok := any(x).(AfterInserter); ok {
+			return h.AfterInsert(ctx, tx)
+		}
+	case "BeforeUpdate":
+		if h, ok := any(x).(BeforeUpdater); ok {
+			return h.BeforeUpdate(ctx, tx)
+		}
+	case "AfterUpdate":
+		if h, ok := any(x).(AfterUpdater); ok {
+			return h.AfterUpdate(ctx, tx)
+		}
+	case "BeforeDelete":
+		if h, ok := any(x).(BeforeDeleter); ok {
+			return h.BeforeDelete(ctx, tx)
+		}
+	case "AfterDelete":
+		if h, ok := any(x).(AfterDeleter); ok {
+			return h.AfterDelete(ctx, tx)
+		}
+	case "AfterLoad":
+		if h, ok := any(x).(AfterLoader); ok {
+			return h.AfterLoad(ctx, tx)
+		}
+	}
+	return nil
+}
+
+// afterHook runs hook on x once result has succeeded.
+func afterHook(ctx context.Context, tx *sql.Tx, x *Entity, hook string, result *QueryResult) *QueryResult {
+	if result.Error == nil {
+		result.Error = runHook(ctx, tx, x, hook)
+	}
+	return result
+}
+
+func afterLoad(ctx context.Context, tx *sql.Tx, entities ...*Entity) error {
+	for _, x := range entities {
+		if err := runHook(ctx, tx, x, "AfterLoad"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// QueryEvent describes one statement run by a Client.
+type QueryEvent struct {
+	Op           string // "exec" or "query"
+	Table        string // FQTN
+	Name         string // generated operation such as "Insert" or "SelectAll", or the named query
+	Query        string
+	Args         int
+	Replica      bool
+	Duration     time.Duration
+	RowsAffected int64 // rows changed by an exec or returned by a query, -1 when unknown
+	Err          error
+}
+
+// QueryHook observes every statement a Client runs, for logging, metrics or
+// tracing. BeforeQuery may return a derived context, for example one carrying
+// a span; it is used for the statement and passed on to AfterQuery.
+type QueryHook interface {
+	BeforeQuery(ctx context.Context, ev *QueryEvent) context.Context
+	AfterQuery(ctx context.Context, ev *QueryEvent)
+}
+
+// conn is one *sql.DB together with the statements prepared on it.
+type conn struct {
+	db        *sql.DB
+	replica   bool
+	downUntil atomic.Int64
+	stmtMu    sync.RWMutex
+	stmtCache map[string]*sql.Stmt
+	hook      atomic.Pointer[QueryHook]
+}
+
+func newConn(x *sql.DB, replica bool) *conn {
+	return &conn{db: x, replica: replica, stmtCache: make(map[string]*sql.Stmt)}
+}
+
+// report takes a replica out of rotation for ReplicaCooldown when err shows
+// that its connection is broken.
+func (n *conn) report(err error) {
+	if n.replica && Shared.IsConnError(err) {
+		n.downUntil.Store(time.Now().Add(ReplicaCooldown).UnixNano())
+	}
+}
+
+// trace hands a statement to the hook, it returns the context to run the
+// statement with and the func that completes the event.
+func (n *conn) trace(ctx context.Context, op, name, query string, args int) (context.Context, func(rows int64, err error)) {
+	hp := n.hook.Load()
+	if hp == nil {
+		return ctx, noTrace
+	}
+	hook := *hp
+	ev := &QueryEvent{Op: op, Table: FQTN, Name: name, Query: query, Args: args, Replica: n.replica, RowsAffected: -1}
+	hookCtx := ctx
+	if hookCtx == nil {
+		hookCtx = context.Background()
+	}
+	hookCtx = hook.BeforeQuery(hookCtx, ev)
+	if ctx != nil {
+		ctx = hookCtx
+	}
+	start := time.Now()
+	return ctx, func(rows int64, err error) {
+		ev.Duration = time.Since(start)
+		ev.RowsAffected = rows
+		ev.Err = err
+		hook.AfterQuery(hookCtx, ev)
+	}
+}
+
+func noTrace(int64, error) {}
+
+func resultRows(res sql.Result) int64 {
+	if res == nil {
+		return -1
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return -1
+	}
+	return n
+}
+
+// Client owns a primary *sql.DB, optional read replicas and the statements
+// prepared on each of them, several clients can coexist in one process.
+// Writes and everything inside a *sql.Tx go to the primary, reads are spread
+// over the healthy replicas and fall back to the primary when none is left.
+type Client struct {
+	primary  *conn
+	replicas []*conn
+	next     atomic.Uint64
+}
+
+func NewClient(primary *sql.DB, replicas ...*sql.DB) (*Client, error) {
+	c := &Client{primary: newConn(primary, false)}
+	for _, r := range replicas {
+		c.replicas = append(c.replicas, newConn(r, true))
+	}
+	return c, nil
+}
+
+func (c *Client) DB() *sql.DB {
+	return c.primary.db
+}
+
+// CheckReplicas pings every replica, taking the unreachable ones out of
+// rotation and putting the recovered ones back.
+func (c *Client) CheckReplicas(ctx context.Context) {
+	for _, n := range c.replicas {
+		if err := n.db.PingContext(ctx); err != nil {
+			n.downUntil.Store(time.Now().Add(ReplicaCooldown).UnixNano())
+		} else {
+			n.downUntil.Store(0)
+		}
+	}
+}
+
+// SetHook makes h observe every statement the client runs, nil removes it.
+// It is safe to call while the client is in use.
+func (c *Client) SetHook(h QueryHook) {
+	var hp *QueryHook
+	if h != nil {
+		hp = &h
+	}
+	c.primary.hook.Store(hp)
+	for _, n := range c.replicas {
+		n.hook.Store(hp)
+	}
+}
+
+// Querier is implemented by *Client and by the in-memory *Fake, so code that
+// depends on it can be tested without a database.
+type Querier interface {
+	DBTruncate() *QueryResult
+	DBTruncateCtx(ctx context.Context) *QueryResult
+	DBTruncateTx(tx *sql.Tx) *QueryResult
+	DBTruncateCtxTx(ctx context.Context, tx *sql.Tx) *QueryResult
+
+	DBInsert(x *Entity, params *QueryParams) *QueryResult
+	DBInsertCtx(ctx context.Context, x *Entity, params *QueryParams) *QueryResult
+	DBInsertTx(tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult
+	DBInsertCtxTx(ctx context.Context, tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult
+
+	DBInsertReturning(x *Entity, params *QueryParams) *QueryResult
+	DBInsertReturningCtx(ctx context.Context, x *Entity, params *QueryParams) *QueryResult
+	DBInsertReturningTx(tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult
+	DBInsertReturningCtxTx(ctx context.Context, tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult
+
+	DBInsertMany(entities []*Entity, params *QueryParams) *InsertManyResult
+	DBInsertManyCtx(ctx context.Context, entities []*Entity, params *QueryParams) *InsertManyResult
+	DBInsertManyTx(tx *sql.Tx, entities []*Entity, params *QueryParams) *InsertManyResult
+	DBInsertManyCtxTx(ctx context.Context, tx *sql.Tx, entities []*Entity, params *QueryParams) *InsertManyResult
+
+	DBUpsert(x *Entity, params *QueryParams) *QueryResult
+	DBUpsertCtx(ctx context.Context, x *Entity, params *QueryParams) *QueryResult
+	DBUpsertTx(tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult
+	DBUpsertCtxTx(ctx context.Context, tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult
+
+	DBInsertIgnore(x *Entity, params *QueryParams) *QueryResult
+	DBInsertIgnoreCtx(ctx context.Context, x *Entity, params *QueryParams) *QueryResult
+	DBInsertIgnoreTx(tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult
+	DBInsertIgnoreCtxTx(ctx context.Context, tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult
+
+	DBReplace(x *Entity, params *QueryParams) *QueryResult
+	DBReplaceCtx(ctx context.Context, x *Entity, params *QueryParams) *QueryResult
+	DBReplaceTx(tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult
+	DBReplaceCtxTx(ctx context.Context, tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult
+
+	DBDelete(x *Entity, params *QueryParams) *QueryResult
+	DBDeleteCtx(ctx context.Context, x *Entity, params *QueryParams) *QueryResult
+	DBDeleteTx(tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult
+	DBDeleteCtxTx(ctx context.Context, tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult
+
+	DBDeleteReturning(x *Entity, params *QueryParams) *QueryResult
+	DBDeleteReturningCtx(ctx context.Context, x *Entity, params *QueryParams) *QueryResult
+	DBDeleteReturningTx(tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult
+	DBDeleteReturningCtxTx(ctx context.Context, tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult
+
+	DBUpdate(x *Entity, params *QueryParams) *QueryResult
+	DBUpdateCtx(ctx context.Context, x *Entity, params *QueryParams) *QueryResult
+	DBUpdateTx(tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult
+	DBUpdateCtxTx(ctx context.Context, tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult
+
+	DBSelect(x *Entity, params *QueryParams) *QueryResult
+	DBSelectCtx(ctx context.Context, x *Entity, params *QueryParams) *QueryResult
+	DBSelectTx(tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult
+	DBSelectCtxTx(ctx context.Context, tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult
+
+	DBSelectAll(params *QueryParams) *QueryResult
+	DBSelectAllCtx(ctx context.Context, params *QueryParams) *QueryResult
+	DBSelectAllTx(tx *sql.Tx, params *QueryParams) *QueryResult
+	DBSelectAllCtxTx(ctx context.Context, tx *sql.Tx, params *QueryParams) *QueryResult
+
+	DBExists(x *Entity, params *QueryParams) *QueryResult
+	DBExistsCtx(ctx context.Context, x *Entity, params *QueryParams) *QueryResult
+	DBExistsTx(tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult
+	DBExistsCtxTx(ctx context.Context, tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult
+
+	DBSelectPage(x *Entity, params *QueryParams, cursor string) *QueryResult
+	DBSelectPageCtx(ctx context.Context, x *Entity, params *QueryParams, cursor string) *QueryResult
+	DBSelectPageTx(tx *sql.Tx, x *Entity, params *QueryParams, cursor string) *QueryResult
+	DBSelectPageCtxTx(ctx context.Context, tx *sql.Tx, x *Entity, params *QueryParams, cursor string) *QueryResult
+
+	DBGetByPK(id int32) *QueryResult
+	DBGetByPKCtx(ctx context.Context, id int32) *QueryResult
+	DBGetByPKTx(tx *sql.Tx, id int32) *QueryResult
+	DBGetByPKCtxTx(ctx context.Context, tx *sql.Tx, id int32) *QueryResult
+
+	DBGetByUuidField(uuidField string) *QueryResult
+	DBGetByUuidFieldCtx(ctx context.Context, uuidField string) *QueryResult
+	DBGetByUuidFieldTx(tx *sql.Tx, uuidField string) *QueryResult
+	DBGetByUuidFieldCtxTx(ctx context.Context, tx *sql.Tx, uuidField string) *QueryResult
+
+	DBUpdateByPK(x *Entity, params *QueryParams) *QueryResult
+	DBUpdateByPKCtx(ctx context.Context, x *Entity, params *QueryParams) *QueryResult
+	DBUpdateByPKTx(tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult
+	DBUpdateByPKCtxTx(ctx context.Context, tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult
+
+	DBUpdateChanged(x *Entity, params *QueryParams) *QueryResult
+	DBUpdateChangedCtx(ctx context.Context, x *Entity, params *QueryParams) *QueryResult
+	DBUpdateChangedTx(tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult
+	DBUpdateChangedCtxTx(ctx context.Context, tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult
+
+	DBDeleteByPK(x *Entity) *QueryResult
+	DBDeleteByPKCtx(ctx context.Context, x *Entity) *QueryResult
+	DBDeleteByPKTx(tx *sql.Tx, x *Entity) *QueryResult
+	DBDeleteByPKCtxTx(ctx context.Context, tx *sql.Tx, x *Entity) *QueryResult
+
+	DBReload(x *Entity) *QueryResult
+	DBReloadCtx(ctx context.Context, x *Entity) *QueryResult
+	DBReloadTx(tx *sql.Tx, x *Entity) *QueryResult
+	DBReloadCtxTx(ctx context.Context, tx *sql.Tx, x *Entity) *QueryResult
+}
+
+var (
+	_ Querier = (*Client)(nil)
+	_ Querier = (*Fake)(nil)
+)
+
+// reader picks the connection for a read: the primary inside a transaction
+// or under UsePrimary, otherwise the next healthy replica.
+func (c *Client) reader(ctx context.Context, tx *sql.Tx) *conn {
+	if tx != nil || len(c.replicas) == 0 || (ctx != nil && ctx.Value(primaryCtxKey{}) != nil) {
+		return c.primary
+	}
+	now := time.Now().UnixNano()
+	start := c.next.Add(1)
+	for i := range c.replicas {
+		n := c.replicas[(start+uint64(i))%uint64(len(c.replicas))]
+		if n.downUntil.Load() <= now {
+			return n
+		}
+	}
+	return c.primary
+}
+
+type primaryCtxKey struct{}
+
+// UsePrimary returns a context whose reads are sent to the primary, so they
+// observe the caller's own writes.
+func UsePrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryCtxKey{}, true)
+}
+
+// SetDB points the package-level functions and Entity methods at primary
+// and, for reads, at replicas.
+func SetDB(primary *sql.DB, replicas ...*sql.DB) error {
+	c, err := NewClient(primary, replicas...)
+	if err != nil {
+		return err
+	}
+	SetClient(c)
+	return nil
+}
+
+// SetClient makes c the client behind the package-level functions and Entity methods.
+func SetClient(c *Client) {
+	defaultClient = c
+}
+
+// GetClient returns the client behind the package-level functions and Entity methods.
+func GetClient() *Client {
+	return defaultClient
+}
+
+// SetHook sets the hook of the client behind the package-level functions.
+func SetHook(h QueryHook) {
+	defaultClient.SetHook(h)
+}
+
+// Validate checks every field against the column it was generated from:
+// lengths, numeric ranges, enum and set members and date formats. NOT NULL
+// needs no check for most columns, only nullable columns are generated as
+// Null, but a nil []byte is sent as NULL and is rejected.
+func (x *Entity) Validate() error {
+	return x.ValidateFields(Fields...)
+}
+
+// ValidateFields is Validate restricted to fields, for example the ones about
+// to be inserted or updated.
+func (x *Entity) ValidateFields(fields ...string) error {
+	var errs []FieldError
+	for _, field := range fields {
+		if reason := x.validateField(field); reason != "" {
+			errs = append(errs, FieldError{Field: field, Reason: reason})
+		}
+	}
+	if len(errs) > 0 {
+		return &ValidationError{Errors: errs}
+	}
+	return nil
+}
+
+func (x *Entity) validateField(field string) string {
+	switch field {
+	case FieldMediumSigned: // mediumint
+		return checkRange(int64(x.MediumSigned), -8388608, 8388607)
+	case FieldMediumUnsigned: // mediumint unsigned
+		return checkMax(uint64(x.MediumUnsigned), 16777215)
+	case FieldDecimalField: // decimal(20,10)
+		return checkDecimal(x.DecimalField, 20, 10)
+	case FieldDecField: // dec(10,5)
+		return checkDecimal(x.DecField, 10, 5)
+	case FieldNumericField: // numeric(10,7)
+		return checkDecimal(x.NumericField, 10, 7)
+	case FieldFixedField: // fixed(10,6)
+		return checkDecimal(x.FixedField, 10, 6)
+	case FieldBit8: // bit(8)
+		return checkMax(x.Bit8, 255)
+	case FieldCharField: // char(10)
+		return checkChars(x.CharField, 10)
+	case FieldVarcharField: // varchar(255)
+		return checkChars(x.VarcharField, 255)
+	case FieldTextField: // text
+		return checkBytes(len(x.TextField), 65535)
+	case FieldTinytextField: // tinytext
+		return checkBytes(len(x.TinytextField), 255)
+	case FieldMediumtextField: // mediumtext
+		return checkBytes(len(x.MediumtextField), 16777215)
+	case FieldLongtextField: // longtext
+		return checkBytes(len(x.LongtextField), 4294967295)
+	case FieldEnumField: // enum('one','two','three')
+		return checkEnum(x.EnumField, "one", "two", "three")
+	case FieldSetField: // set('a','b','c')
+		return checkSet(x.SetField, "a", "b", "c")
+	case FieldBinaryField: // binary(16)
+		return checkBlob(x.BinaryField, 16)
+	case FieldVarbinaryField: // varbinary(255)
+		return checkBlob(x.VarbinaryField, 255)
+	case FieldBlobField: // blob
+		return checkBlob(x.BlobField, 65535)
+	case FieldTinyblobField: // tinyblob
+		return checkBlob(x.TinyblobField, 255)
+	case FieldMediumblobField: // mediumblob
+		return checkBlob(x.MediumblobField, 16777215)
+	case FieldLongblobField: // longblob
+		return checkBlob(x.LongblobField, 4294967295)
+	case FieldDateField: // date
+		return checkTimeRange(x.DateField, minDatetime, maxDatetime)
+	case FieldTimeField: // time
+		return checkTime(x.TimeField, 0)
+	case FieldYearField: // year
+		return checkYear(x.YearField)
+	case FieldDatetimeField: // datetime
+		return checkTimeRange(x.DatetimeField, minDatetime, maxDatetime)
+	case FieldTimestampField: // timestamp
+		return checkTimeRange(x.TimestampField, minTimestamp, maxTimestamp)
+	case FieldUuidField: // uuid
+		return checkUUID(x.UuidField)
+	}
+	return ""
+}
+
+func checkChars(s string, n int) string {
+	if utf8.RuneCountInString(s) > n {
+		return "longer than " + strconv.Itoa(n) + " characters"
+	}
+	return ""
+}
+
+func checkBytes(n int, max int64) string {
+	if int64(n) > max {
+		return "longer than " + strconv.FormatInt(max, 10) + " bytes"
+	}
+	return ""
+}
+
+// checkBlob is checkBytes for NOT NULL []byte columns, whose nil value the
+// driver sends as NULL.
+func checkBlob(b []byte, max int64) string {
+	if b == nil {
+		return "nil for a NOT NULL column"
+	}
+	return checkBytes(len(b), max)
+}
+
+func checkRange(v, min, max int64) string {
+	if v < min || v > max {
+		return "out of range [" + strconv.FormatInt(min, 10) + ", " + strconv.FormatInt(max, 10) + "]"
+	}
+	return ""
+}
+
+func checkMax(v, max uint64) string {
+	if v > max {
+		return "greater than " + strconv.FormatUint(max, 10)
+	}
+	return ""
+}
+
+// checkDecimal accepts [-+]digits[.digits] with at most precision digits, of
+// which at most scale after the point.
+func checkDecimal(s string, precision, scale int) string {
+	s = strings.TrimLeft(s, "+-")
+	whole, frac, _ := strings.Cut(s, ".")
+	if whole == "" && frac == "" || strings.Trim(whole, "0123456789") != "" || strings.Trim(frac, "0123456789") != "" {
+		return "not a decimal number"
+	}
+	if len(strings.TrimLeft(whole, "0")) > precision-scale {
+		return "more than " + strconv.Itoa(precision-scale) + " digits before the decimal point"
+	}
+	if len(frac) > scale {
+		return "more than " + strconv.Itoa(scale) + " digits after the decimal point"
+	}
+	return ""
+}
+
+func checkEnum(s string, values ...string) string {
+	for _, v := range values {
+		if s == v {
+			return ""
+		}
+	}
+	return "not one of " + strings.Join(values, ", ")
+}
+
+func checkSet(s string, values ...string) string {
+	if s == "" {
+		return ""
+	}
+	for _, member := range strings.Split(s, ",") {
+		if checkEnum(member, values...) != "" {
+			return "member " + member + " is not one of " + strings.Join(values, ", ")
+		}
+	}
+	return ""
+}
+
+// checkUUID accepts the 36 character form with dashes and the 32 character
+// form without them, like the UUID type.
+func checkUUID(s string) string {
+	hex := s
+	if len(s) == 36 {
+		if s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+			return "not a UUID"
+		}
+		hex = s[:8] + s[9:13] + s[14:18] + s[19:23] + s[24:]
+	}
+	if len(hex) != 32 || strings.Trim(strings.ToLower(hex), "0123456789abcdef") != "" {
+		return "not a UUID"
+	}
+	return ""
+}
+
+var (
+	minDatetime  = time.Date(1000, 1, 1, 0, 0, 0, 0, time.UTC)
+	maxDatetime  = time.Date(9999, 12, 31, 23, 59, 59, 999999000, time.UTC)
+	minTimestamp = time.Date(1970, 1, 1, 0, 0, 1, 0, time.UTC)
+	maxTimestamp = time.Date(2038, 1, 19, 3, 14, 7, 999999000, time.UTC)
+)
+
+func checkTimeRange(t, min, max time.Time) string {
+	if t.Before(min) || t.After(max) {
+		return "out of range [" + min.Format(time.DateTime) + ", " + max.Format(time.DateTime) + "]"
+	}
+	return ""
+}
+
+// checkTime accepts [-]HHH:MM:SS with at most fsp fractional digits, within
+// the TIME range of -838:59:59 to 838:59:59.
+func checkTime(s string, fsp int) string {
+	const reason = "not a time in the format [-]HH:MM:SS"
+	base, frac, _ := strings.Cut(strings.TrimPrefix(s, "-"), ".")
+	parts := strings.Split(base, ":")
+	if len(parts) != 3 || len(frac) > fsp || strings.Trim(frac, "0123456789") != "" {
+		return reason
+	}
+	h, herr := strconv.Atoi(parts[0])
+	m, merr := strconv.Atoi(parts[1])
+	sec, serr := strconv.Atoi(parts[2])
+	if herr != nil || merr != nil || serr != nil || len(parts[1]) != 2 || len(parts[2]) != 2 || h < 0 || m > 59 || sec > 59 {
+		return reason
+	}
+	if h > 838 {
+		return "out of range [-838:59:59, 838:59:59]"
+	}
+	return ""
+}
+
+func checkYear(v uint16) string {
+	if v != 0 && (v < 1901 || v > 2155) {
+		return "out of range [1901, 2155]"
+	}
+	return ""
+}
+
+// entitySnapshot is an Entity as it was loaded, see ChangedFields.
+type entitySnapshot struct {
+	entity Entity
+	fields []string
+}
+
+func (x *Entity) takeSnapshot(fields []string) {
+	s := &entitySnapshot{entity: *x, fields: fields}
+	s.entity.loaded = nil
+	s.entity.BinaryField = bytes.Clone(x.BinaryField)
+	s.entity.VarbinaryField = bytes.Clone(x.VarbinaryField)
+	s.entity.BlobField = bytes.Clone(x.BlobField)
+	s.entity.TinyblobField = bytes.Clone(x.TinyblobField)
+	s.entity.MediumblobField = bytes.Clone(x.MediumblobField)
+	s.entity.LongblobField = bytes.Clone(x.LongblobField)
+	x.loaded = s
+}
+
+// hasPrimaryKey reports whether every PrimaryKey field was loaded, so the
+// snapshot can locate the row.
+func (s *entitySnapshot) hasPrimaryKey() bool {
+	for _, key := range PrimaryKey {
+		found := false
+		for _, field := range s.fields {
+			if field == key {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// track snapshots entities read with params.Track.
+func track(params *QueryParams, fields []string, entities ...*Entity) {
+	if params == nil || !params.Track {
+		return
+	}
+	for _, x := range entities {
+		x.takeSnapshot(fields)
+	}
+}
+
+// Track snapshots every field of x as it is now, so that ChangedFields and
+// DBUpdateChanged work for entities read without QueryParams.Track, such as
+// the ones returned by DBGetByPK.
+func (x *Entity) Track() {
+	x.takeSnapshot(Fields)
+}
+
+// ChangedFields returns the loaded fields whose value differs from the one
+// read from the database, nil for an entity that is not tracked.
+func (x *Entity) ChangedFields() []string {
+	if x.loaded == nil {
+		return nil
+	}
+	var changed []string
+	for _, field := range x.loaded.fields {
+		if !fieldEqual(x, &x.loaded.entity, field) {
+			changed = append(changed, field)
+		}
+	}
+	return changed
+}
+
+func fieldEqual(a, b *Entity, field string) bool {
+	switch field {
+	case FieldId:
+		return a.Id == b.Id
+	case FieldTinySigned:
+		return a.TinySigned == b.TinySigned
 	case FieldTinyUnsigned:
-		return FQTN + ".`" + FieldTinyUnsigned + "`"
+		return a.TinyUnsigned == b.TinyUnsigned
 	case FieldSmallSigned:
-		return FQTN + ".`" + FieldSmallSigned + "`"
+		return a.SmallSigned == b.SmallSigned
 	case FieldSmallUnsigned:
-		return FQTN + ".`" + FieldSmallUnsigned + "`"
+		return a.SmallUnsigned == b.SmallUnsigned
 	case FieldMediumSigned:
-		return FQTN + ".`" + FieldMediumSigned + "`"
+		return a.MediumSigned == b.MediumSigned
 	case FieldMediumUnsigned:
-		return FQTN + ".`" + FieldMediumUnsigned + "`"
+		return a.MediumUnsigned == b.MediumUnsigned
 	case FieldIntSigned:
-		return FQTN + ".`" + FieldIntSigned + "`"
+		return a.IntSigned == b.IntSigned
 	case FieldIntUnsigned:
-		return FQTN + ".`" + FieldIntUnsigned + "`"
+		return a.IntUnsigned == b.IntUnsigned
 	case FieldBigSigned:
-		return FQTN + ".`" + FieldBigSigned + "`"
+		return a.BigSigned == b.BigSigned
 	case FieldBigUnsigned:
-		return FQTN + ".`" + FieldBigUnsigned + "`"
+		return a.BigUnsigned == b.BigUnsigned
 	case FieldFloatField:
-		return FQTN + ".`" + FieldFloatField + "`"
+		return a.FloatField == b.FloatField
 	case FieldDoubleField:
-		return FQTN + ".`" + FieldDoubleField + "`"
+		return a.DoubleField == b.DoubleField
 	case FieldRealField:
-		return FQTN + ".`" + FieldRealField + "`"
+		return a.RealField == b.RealField
 	case FieldDecimalField:
-		return FQTN + ".`" + FieldDecimalField + "`"
+		return a.DecimalField == b.DecimalField
 	case FieldDecField:
-		return FQTN + ".`" + FieldDecField + "`"
+		return a.DecField == b.DecField
 	case FieldNumericField:
-		return FQTN + ".`" + FieldNumericField + "`"
+		return a.NumericField == b.NumericField
 	case FieldFixedField:
-		return FQTN + ".`" + FieldFixedField + "`"
+		return a.FixedField == b.FixedField
 	case FieldBit1:
-		return FQTN + ".`" + FieldBit1 + "`"
+		return a.Bit1 == b.Bit1
 	case FieldBit8:
-		return FQTN + ".`" + FieldBit8 + "`"
+		return a.Bit8 == b.Bit8
 	case FieldBit64:
-		return FQTN + ".`" + FieldBit64 + "`"
+		return a.Bit64 == b.Bit64
 	case FieldBoolField:
-		return FQTN + ".`" + FieldBoolField + "`"
+		return a.BoolField == b.BoolField
 	case FieldBooleanField:
-		return FQTN + ".`" + FieldBooleanField + "`"
+		return a.BooleanField == b.BooleanField
 	case FieldCharField:
-		return FQTN + ".`" + FieldCharField + "`"
+		return a.CharField == b.CharField
 	case FieldVarcharField:
-		return FQTN + ".`" + FieldVarcharField + "`"
+		return a.VarcharField == b.VarcharField
 	case FieldTextField:
-		return FQTN + ".`" + FieldTextField + "`"
+		return a.TextField == b.TextField
 	case FieldTinytextField:
-		return FQTN + ".`" + FieldTinytextField + "`"
+		return a.TinytextField == b.TinytextField
 	case FieldMediumtextField:
-		return FQTN + ".`" + FieldMediumtextField + "`"
+		return a.MediumtextField == b.MediumtextField
 	case FieldLongtextField:
-		return FQTN + ".`" + FieldLongtextField + "`"
+		return a.LongtextField == b.LongtextField
 	case FieldEnumField:
-		return FQTN + ".`" + FieldEnumField + "`"
+		return a.EnumField == b.EnumField
 	case FieldSetField:
-		return FQTN + ".`" + FieldSetField + "`"
+		return a.SetField == b.SetField
 	case FieldBinaryField:
-		return FQTN + ".`" + FieldBinaryField + "`"
+		return bytes.Equal(a.BinaryField, b.BinaryField)
 	case FieldVarbinaryField:
-		return FQTN + ".`" + FieldVarbinaryField + "`"
+		return bytes.Equal(a.VarbinaryField, b.VarbinaryField)
 	case FieldBlobField:
-		return FQTN + ".`" + FieldBlobField + "`"
+		return bytes.Equal(a.BlobField, b.BlobField)
 	case FieldTinyblobField:
-		return FQTN + ".`" + FieldTinyblobField + "`"
+		return bytes.Equal(a.TinyblobField, b.TinyblobField)
 	case FieldMediumblobField:
-		return FQTN + ".`" + FieldMediumblobField + "`"
+		return bytes.Equal(a.MediumblobField, b.MediumblobField)
 	case FieldLongblobField:
-		return FQTN + ".`" + FieldLongblobField + "`"
+		return bytes.Equal(a.LongblobField, b.LongblobField)
 	case FieldDateField:
-		return FQTN + ".`" + FieldDateField + "`"
+		return a.DateField.Equal(b.DateField)
 	case FieldTimeField:
-		return FQTN + ".`" + FieldTimeField + "`"
+		return a.TimeField == b.TimeField
 	case FieldYearField:
-		return FQTN + ".`" + FieldYearField + "`"
+		return a.YearField == b.YearField
 	case FieldDatetimeField:
-		return FQTN + ".`" + FieldDatetimeField + "`"
+		return a.DatetimeField.Equal(b.DatetimeField)
 	case FieldTimestampField:
-		return FQTN + ".`" + FieldTimestampField + "`"
+		return a.TimestampField.Equal(b.TimestampField)
 	case FieldUuidField:
-		return FQTN + ".`" + FieldUuidField + "`"
-	}
-	return ""
-}
-
-func GetQualifiedFields(fieldList []string) []string {
-	fields := make([]string, 0, len(fieldList))
-	for _, field := range fieldList {
-		fields = append(fields, GetQualifiedField(field))
+		return a.UuidField == b.UuidField
 	}
-	return fields
+	return true
 }
 
-func GetQualifiedPlaceholder(field string) string {
+func (x *Entity) GetFieldValue(field string) any {
 	switch field {
 	case FieldId:
-		return FQTN + ".`" + FieldId + "` = ?"
+		return x.Id
 	case FieldTinySigned:
-		return FQTN + ".`" + FieldTinySigned + "` = ?"
+		return x.TinySigned
 	case FieldTinyUnsigned:
-		return FQTN + ".`" + FieldTinyUnsigned + "` = ?"
+		return x.TinyUnsigned
 	case FieldSmallSigned:
-		return FQTN + ".`" + FieldSmallSigned + "` = ?"
+		return x.SmallSigned
 	case FieldSmallUnsigned:
-		return FQTN + ".`" + FieldSmallUnsigned + "` = ?"
+		return x.SmallUnsigned
 	case FieldMediumSigned:
-		return FQTN + ".`" + FieldMediumSigned + "` = ?"
+		return x.MediumSigned
 	case FieldMediumUnsigned:
-		return FQTN + ".`" + FieldMediumUnsigned + "` = ?"
+		return x.MediumUnsigned
 	case FieldIntSigned:
-		return FQTN + ".`" + FieldIntSigned + "` = ?"
+		return x.IntSigned
 	case FieldIntUnsigned:
-		return FQTN + ".`" + FieldIntUnsigned + "` = ?"
+		return x.IntUnsigned
 	case FieldBigSigned:
-		return FQTN + ".`" + FieldBigSigned + "` = ?"
+		return x.BigSigned
 	case FieldBigUnsigned:
-		return FQTN + ".`" + FieldBigUnsigned + "` = ?"
+		return x.BigUnsigned
 	case FieldFloatField:
-		return FQTN + ".`" + FieldFloatField + "` = ?"
+		return x.FloatField
 	case FieldDoubleField:
-		return FQTN + ".`" + FieldDoubleField + "` = ?"
+		return x.DoubleField
 	case FieldRealField:
-		return FQTN + ".`" + FieldRealField + "` = ?"
+		return x.RealField
 	case FieldDecimalField:
-		return FQTN + ".`" + FieldDecimalField + "` = ?"
+		return x.DecimalField
 	case FieldDecField:
-		return FQTN + ".`" + FieldDecField + "` = ?"
+		return x.DecField
 	case FieldNumericField:
-		return FQTN + ".`" + FieldNumericField + "` = ?"
+		return x.NumericField
 	case FieldFixedField:
-		return FQTN + ".`" + FieldFixedField + "` = ?"
+		return x.FixedField
 	case FieldBit1:
-		return FQTN + ".`" + FieldBit1 + "` = ?"
+		return x.Bit1
 	case FieldBit8:
-		return FQTN + ".`" + FieldBit8 + "` = ?"
+		return x.Bit8
 	case FieldBit64:
-		return FQTN + ".`" + FieldBit64 + "` = ?"
+		return x.Bit64
 	case FieldBoolField:
-		return FQTN + ".`" + FieldBoolField + "` = ?"
+		return x.BoolField
 	case FieldBooleanField:
-		return FQTN + ".`" + FieldBooleanField + "` = ?"
+		return x.BooleanField
 	case FieldCharField:
-		return FQTN + ".`" + FieldCharField + "` = ?"
+		return x.CharField
 	case FieldVarcharField:
-		return FQTN + ".`" + FieldVarcharField + "` = ?"
+		return x.VarcharField
 	case FieldTextField:
-		return FQTN + ".`" + FieldTextField + "` = ?"
+		return x.TextField
 	case FieldTinytextField:
-		return FQTN + ".`" + FieldTinytextField + "` = ?"
+		return x.TinytextField
 	case FieldMediumtextField:
-		return FQTN + ".`" + FieldMediumtextField + "` = ?"
+		return x.MediumtextField
 	case FieldLongtextField:
-		return FQTN + ".`" + FieldLongtextField + "` = ?"
+		return x.LongtextField
 	case FieldEnumField:
-		return FQTN + ".`" + FieldEnumField + "` = ?"
+		return x.EnumField
 	case FieldSetField:
-		return FQTN + ".`" + FieldSetField + "` = ?"
+		return x.SetField
 	case FieldBinaryField:
-		return FQTN + ".`" + FieldBinaryField + "` = ?"
+		return x.BinaryField
 	case FieldVarbinaryField:
-		return FQTN + ".`" + FieldVarbinaryField + "` = ?"
+		return x.VarbinaryField
 	case FieldBlobField:
-		return FQTN + ".`" + FieldBlobField + "` = ?"
+		return x.BlobField
 	case FieldTinyblobField:
-		return FQTN + ".`" + FieldTinyblobField + "` = ?"
+		return x.TinyblobField
 	case FieldMediumblobField:
-		return FQTN + ".`" + FieldMediumblobField + "` = ?"
+		return x.MediumblobField
 	case FieldLongblobField:
-		return FQTN + ".`" + FieldLongblobField + "` = ?"
+		return x.LongblobField
 	case FieldDateField:
-		return FQTN + ".`" + FieldDateField + "` = ?"
+		return x.DateField
 	case FieldTimeField:
-		return FQTN + ".`" + FieldTimeField + "` = ?"
+		return x.TimeField
 	case FieldYearField:
-		return FQTN + ".`" + FieldYearField + "` = ?"
+		return x.YearField
 	case FieldDatetimeField:
-		return FQTN + ".`" + FieldDatetimeField + "` = ?"
+		return x.DatetimeField
 	case FieldTimestampField:
-		return FQTN + ".`" + FieldTimestampField + "` = ?"
+		return x.TimestampField
 	case FieldUuidField:
-		return FQTN + ".`" + FieldUuidField + "` = ?"
+		return x.UuidField
 	}
-	return ""
+	return nil
 }
 
-func GetQualifiedPlaceholders(fieldList []string) []string {
-	placeholders := make([]string, 0, len(fieldList))
+func (x *Entity) GetFieldsValues(fieldList []string) []any {
+	values := make([]any, 0, len(fieldList))
 	for _, field := range fieldList {
-		placeholders = append(placeholders, GetQualifiedPlaceholder(field))
+		values = append(values, x.GetFieldValue(field))
 	}
-	return placeholders
+	return values
 }
 
-func getPreparedStmt(query string) (*sql.Stmt, error) {
-	stmtMu.RLock()
-	if stmt, ok := stmtCache[query]; ok {
-		stmtMu.RUnlock()
-		return stmt, nil
+func GetValuePlaceholder(field string) string {
+	switch field {
+	case FieldId:
+		return "?"
+	case FieldTinySigned:
+		return "?"
+	case FieldTinyUnsigned:
+		return "?"
+	case FieldSmallSigned:
+		return "?"
+	case FieldSmallUnsigned:
+		return "?"
+	case FieldMediumSigned:
+		return "?"
+	case FieldMediumUnsigned:
+		return "?"
+	case FieldIntSigned:
+		return "?"
+	case FieldIntUnsigned:
+		return "?"
+	case FieldBigSigned:
+		return "?"
+	case FieldBigUnsigned:
+		return "?"
+	case FieldFloatField:
+		return "?"
+	case FieldDoubleField:
+		return "?"
+	case FieldRealField:
+		return "?"
+	case FieldDecimalField:
+		return "?"
+	case FieldDecField:
+		return "?"
+	case FieldNumericField:
+		return "?"
+	case FieldFixedField:
+		return "?"
+	case FieldBit1:
+		return "?"
+	case FieldBit8:
+		return "?"
+	case FieldBit64:
+		return "?"
+	case FieldBoolField:
+		return "?"
+	case FieldBooleanField:
+		return "?"
+	case FieldCharField:
+		return "?"
+	case FieldVarcharField:
+		return "?"
+	case FieldTextField:
+		return "?"
+	case FieldTinytextField:
+		return "?"
+	case FieldMediumtextField:
+		return "?"
+	case FieldLongtextField:
+		return "?"
+	case FieldEnumField:
+		return "?"
+	case FieldSetField:
+		return "?"
+	case FieldBinaryField:
+		return "?"
+	case FieldVarbinaryField:
+		return "?"
+	case FieldBlobField:
+		return "?"
+	case FieldTinyblobField:
+		return "?"
+	case FieldMediumblobField:
+		return "?"
+	case FieldLongblobField:
+		return "?"
+	case FieldDateField:
+		return "?"
+	case FieldTimeField:
+		return "?"
+	case FieldYearField:
+		return "?"
+	case FieldDatetimeField:
+		return "?"
+	case FieldTimestampField:
+		return "?"
+	case FieldUuidField:
+		return "?"
 	}
-	stmtMu.RUnlock()
+	return ""
+}
 
-	stmtMu.Lock()
-	defer stmtMu.Unlock()
-	if stmt, ok := stmtCache[query]; ok {
-		return stmt, nil
-	}
-	stmt, err := db.Prepare(query)
-	if err != nil {
-		return nil, err
+func GetValuesPlaceholders(fieldList []string) []string {
+	placeholders := make([]string, 0, len(fieldList))
+	for _, field := range fieldList {
+		placeholders = append(placeholders, GetValuePlaceholder(field))
 	}
-	stmtCache[query] = stmt
-	return stmt, nil
+	return placeholders
 }
 
-func scanRow(fields []string, rows *sql.Rows) (*Entity, error) {
-	x := &Entity{}
-	var (
-		ptrId              *string
-		ptrTinySigned      *string
-		ptrTinyUnsigned    *string
-		ptrSmallSigned     *string
-		ptrSmallUnsigned   *string
-		ptrMediumSigned    *string
-		ptrMediumUnsigned  *string
-		ptrIntSigned       *string
-		ptrIntUnsigned     *string
-		ptrBigSigned       *string
-		ptrBigUnsigned     *string
-		ptrFloatField      *string
-		ptrDoubleField     *string
-		ptrRealField       *string
-		ptrDecimalField    *string
-		ptrDecField        *string
-		ptrNumericField    *string
-		ptrFixedField      *string
-		ptrBit1            *string
-		ptrBit8            *string
-		ptrBit64           *string
-		ptrBoolField       *string
-		ptrBooleanField    *string
-		ptrCharField       *string
-		ptrVarcharField    *string
-		ptrTextField       *string
-		ptrTinytextField   *string
-		ptrMediumtextField *string
-		ptrLongtextField   *string
-		ptrEnumField       *string
-		ptrSetField        *string
-		ptrBinaryField     *string
-		ptrVarbinaryField  *string
-		ptrBlobField       *string
-		ptrTinyblobField   *string
-		ptrMediumblobField *string
-		ptrLongblobField   *string
-		ptrDateField       *string
-		ptrTimeField       *string
-		ptrYearField       *string
-		ptrDatetimeField   *string
-		ptrTimestampField  *string
-		ptrUuidField       *string
-		scanTargets        []any
-	)
-
-	for _, field := range fields {
-		switch field {
-		case FieldId:
-			scanTargets = append(scanTargets, &ptrId)
-		case FieldTinySigned:
-			scanTargets = append(scanTargets, &ptrTinySigned)
-		case FieldTinyUnsigned:
-			scanTargets = append(scanTargets, &ptrTinyUnsigned)
-		case FieldSmallSigned:
-			scanTargets = append(scanTargets, &ptrSmallSigned)
-		case FieldSmallUnsigned:
-			scanTargets = append(scanTargets, &ptrSmallUnsigned)
-		case FieldMediumSigned:
-			scanTargets = append(scanTargets, &ptrMediumSigned)
-		case FieldMediumUnsigned:
-			scanTargets = append(scanTargets, &ptrMediumUnsigned)
-		case FieldIntSigned:
-			scanTargets = append(scanTargets, &ptrIntSigned)
-		case FieldIntUnsigned:
-			scanTargets = append(scanTargets, &ptrIntUnsigned)
-		case FieldBigSigned:
-			scanTargets = append(scanTargets, &ptrBigSigned)
-		case FieldBigUnsigned:
-			scanTargets = append(scanTargets, &ptrBigUnsigned)
-		case FieldFloatField:
-			scanTargets = append(scanTargets, &ptrFloatField)
-		case FieldDoubleField:
-			scanTargets = append(scanTargets, &ptrDoubleField)
-		case FieldRealField:
-			scanTargets = append(scanTargets, &ptrRealField)
-		case FieldDecimalField:
-			scanTargets = append(scanTargets, &ptrDecimalField)
-		case FieldDecField:
-			scanTargets = append(scanTargets, &ptrDecField)
-		case FieldNumericField:
-			scanTargets = append(scanTargets, &ptrNumericField)
-		case FieldFixedField:
+func GetQualifiedField(field string) string {
+	switch field {
+	case FieldId:
+		return FQTN + ".`" + FieldId + "`"
+	case FieldTinySigned:
+		return FQTN + ".`" + FieldTinySigned + "`"
+	case FieldTinyUnsigned:
+		return FQTN + ".`" + FieldTinyUnsigned + "`"
+	case FieldSmallSigned:
+		return FQTN + ".`" + FieldSmallSigned + "`"
+	case FieldSmallUnsigned:
+		return FQTN + ".`" + FieldSmallUnsigned + "`"
+	case FieldMediumSigned:
+		return FQTN + ".`" + FieldMediumSigned + "`"
+	case FieldMediumUnsigned:
+		return FQTN + ".`" + FieldMediumUnsigned + "`"
+	case FieldIntSigned:
+		return FQTN + ".`" + FieldIntSigned + "`"
+	case FieldIntUnsigned:
+		return FQTN + ".`" + FieldIntUnsigned + "`"
+	case FieldBigSigned:
+		return FQTN + ".`" + FieldBigSigned + "`"
+	case FieldBigUnsigned:
+		return FQTN + ".`" + FieldBigUnsigned + "`"
+	case FieldFloatField:
+		return FQTN + ".`" + FieldFloatField + "`"
+	case FieldDoubleField:
+		return FQTN + ".`" + FieldDoubleField + "`"
+	case FieldRealField:
+		return FQTN + ".`" + FieldRealField + "`"
+	case FieldDecimalField:
+		return FQTN + ".`" + FieldDecimalField + "`"
+	case FieldDecField:
+		return FQTN + ".`" + FieldDecField + "`"
+	case FieldNumericField:
+		return FQTN + ".`" + FieldNumericField + "`"
+	case FieldFixedField:
+		return FQTN + ".`" + FieldFixedField + "`"
+	case FieldBit1:
+		return FQTN + ".`" + FieldBit1 + "`"
+	case FieldBit8:
+		return FQTN + ".`" + FieldBit8 + "`"
+	case FieldBit64:
+		return FQTN + ".`" + FieldBit64 + "`"
+	case FieldBoolField:
+		return FQTN + ".`" + FieldBoolField + "`"
+	case FieldBooleanField:
+		return FQTN + ".`" + FieldBooleanField + "`"
+	case FieldCharField:
+		return FQTN + ".`" + FieldCharField + "`"
+	case FieldVarcharField:
+		return FQTN + ".`" + FieldVarcharField + "`"
+	case FieldTextField:
+		return FQTN + ".`" + FieldTextField + "`"
+	case FieldTinytextField:
+		return FQTN + ".`" + FieldTinytextField + "`"
+	case FieldMediumtextField:
+		return FQTN + ".`" + FieldMediumtextField + "`"
+	case FieldLongtextField:
+		return FQTN + ".`" + FieldLongtextField + "`"
+	case FieldEnumField:
+		return FQTN + ".`" + FieldEnumField + "`"
+	case FieldSetField:
+		return FQTN + ".`" + FieldSetField + "`"
+	case FieldBinaryField:
+		return FQTN + ".`" + FieldBinaryField + "`"
+	case FieldVarbinaryField:
+		return FQTN + ".`" + FieldVarbinaryField + "`"
+	case FieldBlobField:
+		return FQTN + ".`" + FieldBlobField + "`"
+	case FieldTinyblobField:
+		return FQTN + ".`" + FieldTinyblobField + "`"
+	case FieldMediumblobField:
+		return FQTN + ".`" + FieldMediumblobField + "`"
+	case FieldLongblobField:
+		return FQTN + ".`" + FieldLongblobField + "`"
+	case FieldDateField:
+		return FQTN + ".`" + FieldDateField + "`"
+	case FieldTimeField:
+		return FQTN + ".`" + FieldTimeField + "`"
+	case FieldYearField:
+		return FQTN + ".`" + FieldYearField + "`"
+	case FieldDatetimeField:
+		return FQTN + ".`" + FieldDatetimeField + "`"
+	case FieldTimestampField:
+		return FQTN + ".`" + FieldTimestampField + "`"
+	case FieldUuidField:
+		return FQTN + ".`" + FieldUuidField + "`"
+	}
+	return ""
+}
+
+func GetQualifiedFields(fieldList []string) []string {
+	fields := make([]string, 0, len(fieldList))
+	for _, field := range fieldList {
+		fields = append(fields, GetQualifiedField(field))
+	}
+	return fields
+}
+
+func GetQualifiedPlaceholder(field string) string {
+	switch field {
+	case FieldId:
+		return FQTN + ".`" + FieldId + "` = ?"
+	case FieldTinySigned:
+		return FQTN + ".`" + FieldTinySigned + "` = ?"
+	case FieldTinyUnsigned:
+		return FQTN + ".`" + FieldTinyUnsigned + "` = ?"
+	case FieldSmallSigned:
+		return FQTN + ".`" + FieldSmallSigned + "` = ?"
+	case FieldSmallUnsigned:
+		return FQTN + ".`" + FieldSmallUnsigned + "` = ?"
+	case FieldMediumSigned:
+		return FQTN + ".`" + FieldMediumSigned + "` = ?"
+	case FieldMediumUnsigned:
+		return FQTN + ".`" + FieldMediumUnsigned + "` = ?"
+	case FieldIntSigned:
+		return FQTN + ".`" + FieldIntSigned + "` = ?"
+	case FieldIntUnsigned:
+		return FQTN + ".`" + FieldIntUnsigned + "` = ?"
+	case FieldBigSigned:
+		return FQTN + ".`" + FieldBigSigned + "` = ?"
+	case FieldBigUnsigned:
+		return FQTN + ".`" + FieldBigUnsigned + "` = ?"
+	case FieldFloatField:
+		return FQTN + ".`" + FieldFloatField + "` = ?"
+	case FieldDoubleField:
+		return FQTN + ".`" + FieldDoubleField + "` = ?"
+	case FieldRealField:
+		return FQTN + ".`" + FieldRealField + "` = ?"
+	case FieldDecimalField:
+		return FQTN + ".`" + FieldDecimalField + "` = ?"
+	case FieldDecField:
+		return FQTN + ".`" + FieldDecField + "` = ?"
+	case FieldNumericField:
+		return FQTN + ".`" + FieldNumericField + "` = ?"
+	case FieldFixedField:
+		return FQTN + ".`" + FieldFixedField + "` = ?"
+	case FieldBit1:
+		return FQTN + ".`" + FieldBit1 + "` = ?"
+	case FieldBit8:
+		return FQTN + ".`" + FieldBit8 + "` = ?"
+	case FieldBit64:
+		return FQTN + ".`" + FieldBit64 + "` = ?"
+	case FieldBoolField:
+		return FQTN + ".`" + FieldBoolField + "` = ?"
+	case FieldBooleanField:
+		return FQTN + ".`" + FieldBooleanField + "` = ?"
+	case FieldCharField:
+		return FQTN + ".`" + FieldCharField + "` = ?"
+	case FieldVarcharField:
+		return FQTN + ".`" + FieldVarcharField + "` = ?"
+	case FieldTextField:
+		return FQTN + ".`" + FieldTextField + "` = ?"
+	case FieldTinytextField:
+		return FQTN + ".`" + FieldTinytextField + "` = ?"
+	case FieldMediumtextField:
+		return FQTN + ".`" + FieldMediumtextField + "` = ?"
+	case FieldLongtextField:
+		return FQTN + ".`" + FieldLongtextField + "` = ?"
+	case FieldEnumField:
+		return FQTN + ".`" + FieldEnumField + "` = ?"
+	case FieldSetField:
+		return FQTN + ".`" + FieldSetField + "` = ?"
+	case FieldBinaryField:
+		return FQTN + ".`" + FieldBinaryField + "` = ?"
+	case FieldVarbinaryField:
+		return FQTN + ".`" + FieldVarbinaryField + "` = ?"
+	case FieldBlobField:
+		return FQTN + ".`" + FieldBlobField + "` = ?"
+	case FieldTinyblobField:
+		return FQTN + ".`" + FieldTinyblobField + "` = ?"
+	case FieldMediumblobField:
+		return FQTN + ".`" + FieldMediumblobField + "` = ?"
+	case FieldLongblobField:
+		return FQTN + ".`" + FieldLongblobField + "` = ?"
+	case FieldDateField:
+		return FQTN + ".`" + FieldDateField + "` = ?"
+	case FieldTimeField:
+		return FQTN + ".`" + FieldTimeField + "` = ?"
+	case FieldYearField:
+		return FQTN + ".`" + FieldYearField + "` = ?"
+	case FieldDatetimeField:
+		return FQTN + ".`" + FieldDatetimeField + "` = ?"
+	case FieldTimestampField:
+		return FQTN + ".`" + FieldTimestampField + "` = ?"
+	case FieldUuidField:
+		return FQTN + ".`" + FieldUuidField + "` = ?"
+	}
+	return ""
+}
+
+func GetQualifiedPlaceholders(fieldList []string) []string {
+	placeholders := make([]string, 0, len(fieldList))
+	for _, field := range fieldList {
+		placeholders = append(placeholders, GetQualifiedPlaceholder(field))
+	}
+	return placeholders
+}
+
+func GetQualifiedCondition(field string) string {
+	switch field {
+	case FieldId:
+		return FQTN + ".`" + FieldId + "` = ?"
+	case FieldTinySigned:
+		return FQTN + ".`" + FieldTinySigned + "` = ?"
+	case FieldTinyUnsigned:
+		return FQTN + ".`" + FieldTinyUnsigned + "` = ?"
+	case FieldSmallSigned:
+		return FQTN + ".`" + FieldSmallSigned + "` = ?"
+	case FieldSmallUnsigned:
+		return FQTN + ".`" + FieldSmallUnsigned + "` = ?"
+	case FieldMediumSigned:
+		return FQTN + ".`" + FieldMediumSigned + "` = ?"
+	case FieldMediumUnsigned:
+		return FQTN + ".`" + FieldMediumUnsigned + "` = ?"
+	case FieldIntSigned:
+		return FQTN + ".`" + FieldIntSigned + "` = ?"
+	case FieldIntUnsigned:
+		return FQTN + ".`" + FieldIntUnsigned + "` = ?"
+	case FieldBigSigned:
+		return FQTN + ".`" + FieldBigSigned + "` = ?"
+	case FieldBigUnsigned:
+		return FQTN + ".`" + FieldBigUnsigned + "` = ?"
+	case FieldFloatField:
+		return FQTN + ".`" + FieldFloatField + "` = ?"
+	case FieldDoubleField:
+		return FQTN + ".`" + FieldDoubleField + "` = ?"
+	case FieldRealField:
+		return FQTN + ".`" + FieldRealField + "` = ?"
+	case FieldDecimalField:
+		return FQTN + ".`" + FieldDecimalField + "` = ?"
+	case FieldDecField:
+		return FQTN + ".`" + FieldDecField + "` = ?"
+	case FieldNumericField:
+		return FQTN + ".`" + FieldNumericField + "` = ?"
+	case FieldFixedField:
+		return FQTN + ".`" + FieldFixedField + "` = ?"
+	case FieldBit1:
+		return FQTN + ".`" + FieldBit1 + "` = ?"
+	case FieldBit8:
+		return FQTN + ".`" + FieldBit8 + "` = ?"
+	case FieldBit64:
+		return FQTN + ".`" + FieldBit64 + "` = ?"
+	case FieldBoolField:
+		return FQTN + ".`" + FieldBoolField + "` = ?"
+	case FieldBooleanField:
+		return FQTN + ".`" + FieldBooleanField + "` = ?"
+	case FieldCharField:
+		return FQTN + ".`" + FieldCharField + "` = ?"
+	case FieldVarcharField:
+		return FQTN + ".`" + FieldVarcharField + "` = ?"
+	case FieldTextField:
+		return FQTN + ".`" + FieldTextField + "` = ?"
+	case FieldTinytextField:
+		return FQTN + ".`" + FieldTinytextField + "` = ?"
+	case FieldMediumtextField:
+		return FQTN + ".`" + FieldMediumtextField + "` = ?"
+	case FieldLongtextField:
+		return FQTN + ".`" + FieldLongtextField + "` = ?"
+	case FieldEnumField:
+		return FQTN + ".`" + FieldEnumField + "` = ?"
+	case FieldSetField:
+		return FQTN + ".`" + FieldSetField + "` = ?"
+	case FieldBinaryField:
+		return FQTN + ".`" + FieldBinaryField + "` = ?"
+	case FieldVarbinaryField:
+		return FQTN + ".`" + FieldVarbinaryField + "` = ?"
+	case FieldBlobField:
+		return FQTN + ".`" + FieldBlobField + "` = ?"
+	case FieldTinyblobField:
+		return FQTN + ".`" + FieldTinyblobField + "` = ?"
+	case FieldMediumblobField:
+		return FQTN + ".`" + FieldMediumblobField + "` = ?"
+	case FieldLongblobField:
+		return FQTN + ".`" + FieldLongblobField + "` = ?"
+	case FieldDateField:
+		return FQTN + ".`" + FieldDateField + "` = ?"
+	case FieldTimeField:
+		return FQTN + ".`" + FieldTimeField + "` = ?"
+	case FieldYearField:
+		return FQTN + ".`" + FieldYearField + "` = ?"
+	case FieldDatetimeField:
+		return FQTN + ".`" + FieldDatetimeField + "` = ?"
+	case FieldTimestampField:
+		return FQTN + ".`" + FieldTimestampField + "` = ?"
+	case FieldUuidField:
+		return FQTN + ".`" + FieldUuidField + "` = ?"
+	}
+	return ""
+}
+
+func GetQualifiedConditions(fieldList []string) []string {
+	conditions := make([]string, 0, len(fieldList))
+	for _, field := range fieldList {
+		conditions = append(conditions, GetQualifiedCondition(field))
+	}
+	return conditions
+}
+
+func (n *conn) getPreparedStmt(query string) (*sql.Stmt, error) {
+	if n.db == nil {
+		return nil, errors.New("db not initialized")
+	}
+	n.stmtMu.RLock()
+	if stmt, ok := n.stmtCache[query]; ok {
+		n.stmtMu.RUnlock()
+		return stmt, nil
+	}
+	n.stmtMu.RUnlock()
+
+	n.stmtMu.Lock()
+	defer n.stmtMu.Unlock()
+	if stmt, ok := n.stmtCache[query]; ok {
+		return stmt, nil
+	}
+	stmt, err := n.db.Prepare(query)
+	if err != nil {
+		return nil, err
+	}
+	n.stmtCache[query] = stmt
+	return stmt, nil
+}
+
+// timeScanner reads DATE, DATETIME and TIMESTAMP columns into a time.Time,
+// regardless of whether the DSN enables parseTime.
+type timeScanner struct {
+	Time  time.Time
+	Valid bool
+}
+
+func (s *timeScanner) Scan(value any) error {
+	switch v := value.(type) {
+	case nil:
+		s.Time, s.Valid = time.Time{}, false
+		return nil
+	case time.Time:
+		s.Time, s.Valid = v, true
+		return nil
+	case []byte:
+		return s.parse(string(v))
+	case string:
+		return s.parse(v)
+	}
+	return errors.New("timeScanner: unsupported type")
+}
+
+func (s *timeScanner) parse(v string) error {
+	s.Valid = true
+	if strings.HasPrefix(v, "0000-00-00") {
+		s.Time = time.Time{}
+		return nil
+	}
+	layout := "2006-01-02 15:04:05.999999"
+	if len(v) == len("2006-01-02") {
+		layout = "2006-01-02"
+	}
+	t, err := time.ParseInLocation(layout, v, time.UTC)
+	if err != nil {
+		return err
+	}
+	s.Time = t
+	return nil
+}
+
+// bitScanner reads BIT(n) columns, which the driver returns as big-endian bytes.
+type bitScanner struct {
+	Bits  uint64
+	Valid bool
+}
+
+func (s *bitScanner) Scan(value any) error {
+	switch v := value.(type) {
+	case nil:
+		s.Bits, s.Valid = 0, false
+		return nil
+	case []byte:
+		if len(v) > 8 {
+			return errors.New("bitScanner: value exceeds 64 bits")
+		}
+		s.Bits = 0
+		for _, b := range v {
+			s.Bits = s.Bits<<8 | uint64(b)
+		}
+		s.Valid = true
+		return nil
+	case int64:
+		s.Bits, s.Valid = uint64(v), true
+		return nil
+	case uint64:
+		s.Bits, s.Valid = v, true
+		return nil
+	}
+	return errors.New("bitScanner: unsupported type")
+}
+
+func scanRow(fields []string, rows *sql.Rows) (*Entity, error) {
+	x := &Entity{}
+	var (
+		ptrId              *int32
+		ptrTinySigned      *int8
+		ptrTinyUnsigned    *uint8
+		ptrSmallSigned     *int16
+		ptrSmallUnsigned   *uint16
+		ptrMediumSigned    *int32
+		ptrMediumUnsigned  *uint32
+		ptrIntSigned       *int32
+		ptrIntUnsigned     *uint32
+		ptrBigSigned       *int64
+		ptrBigUnsigned     *uint64
+		ptrFloatField      *float32
+		ptrDoubleField     *float64
+		ptrRealField       *float64
+		ptrDecimalField    *string
+		ptrDecField        *string
+		ptrNumericField    *string
+		ptrFixedField      *string
+		scnBit1            bitScanner
+		scnBit8            bitScanner
+		scnBit64           bitScanner
+		ptrBoolField       *bool
+		ptrBooleanField    *bool
+		ptrCharField       *string
+		ptrVarcharField    *string
+		ptrTextField       *string
+		ptrTinytextField   *string
+		ptrMediumtextField *string
+		ptrLongtextField   *string
+		ptrEnumField       *string
+		ptrSetField        *string
+		ptrBinaryField     *[]byte
+		ptrVarbinaryField  *[]byte
+		ptrBlobField       *[]byte
+		ptrTinyblobField   *[]byte
+		ptrMediumblobField *[]byte
+		ptrLongblobField   *[]byte
+		scnDateField       timeScanner
+		ptrTimeField       *string
+		ptrYearField       *uint16
+		scnDatetimeField   timeScanner
+		scnTimestampField  timeScanner
+		ptrUuidField       *string
+		scanTargets        []any
+	)
+
+	for _, field := range fields {
+		switch field {
+		case FieldId:
+			scanTargets = append(scanTargets, &ptrId)
+		case FieldTinySigned:
+			scanTargets = append(scanTargets, &ptrTinySigned)
+		case FieldTinyUnsigned:
+			scanTargets = append(scanTargets, &ptrTinyUnsigned)
+		case FieldSmallSigned:
+			scanTargets = append(scanTargets, &ptrSmallSigned)
+		case FieldSmallUnsigned:
+			scanTargets = append(scanTargets, &ptrSmallUnsigned)
+		case FieldMediumSigned:
+			scanTargets = append(scanTargets, &ptrMediumSigned)
+		case FieldMediumUnsigned:
+			scanTargets = append(scanTargets, &ptrMediumUnsigned)
+		case FieldIntSigned:
+			scanTargets = append(scanTargets, &ptrIntSigned)
+		case FieldIntUnsigned:
+			scanTargets = append(scanTargets, &ptrIntUnsigned)
+		case FieldBigSigned:
+			scanTargets = append(scanTargets, &ptrBigSigned)
+		case FieldBigUnsigned:
+			scanTargets = append(scanTargets, &ptrBigUnsigned)
+		case FieldFloatField:
+			scanTargets = append(scanTargets, &ptrFloatField)
+		case FieldDoubleField:
+			scanTargets = append(scanTargets, &ptrDoubleField)
+		case FieldRealField:
+			scanTargets = append(scanTargets, &ptrRealField)
+		case FieldDecimalField:
+			scanTargets = append(scanTargets, &ptrDecimalField)
+		case FieldDecField:
+			scanTargets = append(scanTargets, &ptrDecField)
+		case FieldNumericField:
+			scanTargets = append(scanTargets, &ptrNumericField)
+		case FieldFixedField:
 			scanTargets = append(scanTargets, &ptrFixedField)
 		case FieldBit1:
-			scanTargets = append(scanTargets, &ptrBit1)
+			scanTargets = append(scanTargets, &scnBit1)
 		case FieldBit8:
-			scanTargets = append(scanTargets, &ptrBit8)
+			scanTargets = append(scanTargets, &scnBit8)
 		case FieldBit64:
-			scanTargets = append(scanTargets, &ptrBit64)
+			scanTargets = append(scanTargets, &scnBit64)
 		case FieldBoolField:
 			scanTargets = append(scanTargets, &ptrBoolField)
 		case FieldBooleanField:
@@ -709,15 +1816,15 @@ func scanRow(fields []string, rows *sql.Rows) (*Entity, error) {
 		case FieldLongblobField:
 			scanTargets = append(scanTargets, &ptrLongblobField)
 		case FieldDateField:
-			scanTargets = append(scanTargets, &ptrDateField)
+			scanTargets = append(scanTargets, &scnDateField)
 		case FieldTimeField:
 			scanTargets = append(scanTargets, &ptrTimeField)
 		case FieldYearField:
 			scanTargets = append(scanTargets, &ptrYearField)
 		case FieldDatetimeField:
-			scanTargets = append(scanTargets, &ptrDatetimeField)
+			scanTargets = append(scanTargets, &scnDatetimeField)
 		case FieldTimestampField:
-			scanTargets = append(scanTargets, &ptrTimestampField)
+			scanTargets = append(scanTargets, &scnTimestampField)
 		case FieldUuidField:
 			scanTargets = append(scanTargets, &ptrUuidField)
 		}
@@ -731,585 +1838,1545 @@ func scanRow(fields []string, rows *sql.Rows) (*Entity, error) {
 	if ptrId != nil {
 		x.Id = *ptrId
 	} else {
-		x.Id = ""
+		x.Id = 0
 	}
 	if ptrTinySigned != nil {
 		x.TinySigned = *ptrTinySigned
 	} else {
-		x.TinySigned = ""
+		x.TinySigned = 0
 	}
 	if ptrTinyUnsigned != nil {
 		x.TinyUnsigned = *ptrTinyUnsigned
 	} else {
-		x.TinyUnsigned = ""
+		x.TinyUnsigned = 0
 	}
 	if ptrSmallSigned != nil {
 		x.SmallSigned = *ptrSmallSigned
 	} else {
-		x.SmallSigned = ""
+		x.SmallSigned = 0
 	}
 	if ptrSmallUnsigned != nil {
 		x.SmallUnsigned = *ptrSmallUnsigned
 	} else {
-		x.SmallUnsigned = ""
+		x.SmallUnsigned = 0
 	}
 	if ptrMediumSigned != nil {
 		x.MediumSigned = *ptrMediumSigned
 	} else {
-		x.MediumSigned = ""
+		x.MediumSigned = 0
 	}
 	if ptrMediumUnsigned != nil {
 		x.MediumUnsigned = *ptrMediumUnsigned
 	} else {
-		x.MediumUnsigned = ""
+		x.MediumUnsigned = 0
 	}
 	if ptrIntSigned != nil {
 		x.IntSigned = *ptrIntSigned
 	} else {
-		x.IntSigned = ""
+		x.IntSigned = 0
 	}
 	if ptrIntUnsigned != nil {
 		x.IntUnsigned = *ptrIntUnsigned
 	} else {
-		x.IntUnsigned = ""
+		x.IntUnsigned = 0
+	}
+	if ptrBigSigned != nil {
+		x.BigSigned = *ptrBigSigned
+	} else {
+		x.BigSigned = 0
+	}
+	if ptrBigUnsigned != nil {
+		x.BigUnsigned = *ptrBigUnsigned
+	} else {
+		x.BigUnsigned = 0
+	}
+	if ptrFloatField != nil {
+		x.FloatField = *ptrFloatField
+	} else {
+		x.FloatField = 0
+	}
+	if ptrDoubleField != nil {
+		x.DoubleField = *ptrDoubleField
+	} else {
+		x.DoubleField = 0
+	}
+	if ptrRealField != nil {
+		x.RealField = *ptrRealField
+	} else {
+		x.RealField = 0
+	}
+	if ptrDecimalField != nil {
+		x.DecimalField = *ptrDecimalField
+	} else {
+		x.DecimalField = ""
+	}
+	if ptrDecField != nil {
+		x.DecField = *ptrDecField
+	} else {
+		x.DecField = ""
+	}
+	if ptrNumericField != nil {
+		x.NumericField = *ptrNumericField
+	} else {
+		x.NumericField = ""
+	}
+	if ptrFixedField != nil {
+		x.FixedField = *ptrFixedField
+	} else {
+		x.FixedField = ""
+	}
+	if scnBit1.Valid {
+		x.Bit1 = scnBit1.Bits != 0
+	} else {
+		x.Bit1 = false
+	}
+	if scnBit8.Valid {
+		x.Bit8 = scnBit8.Bits
+	} else {
+		x.Bit8 = 0
+	}
+	if scnBit64.Valid {
+		x.Bit64 = scnBit64.Bits
+	} else {
+		x.Bit64 = 0
+	}
+	if ptrBoolField != nil {
+		x.BoolField = *ptrBoolField
+	} else {
+		x.BoolField = false
+	}
+	if ptrBooleanField != nil {
+		x.BooleanField = *ptrBooleanField
+	} else {
+		x.BooleanField = false
+	}
+	if ptrCharField != nil {
+		x.CharField = *ptrCharField
+	} else {
+		x.CharField = ""
+	}
+	if ptrVarcharField != nil {
+		x.VarcharField = *ptrVarcharField
+	} else {
+		x.VarcharField = ""
+	}
+	if ptrTextField != nil {
+		x.TextField = *ptrTextField
+	} else {
+		x.TextField = ""
+	}
+	if ptrTinytextField != nil {
+		x.TinytextField = *ptrTinytextField
+	} else {
+		x.TinytextField = ""
+	}
+	if ptrMediumtextField != nil {
+		x.MediumtextField = *ptrMediumtextField
+	} else {
+		x.MediumtextField = ""
+	}
+	if ptrLongtextField != nil {
+		x.LongtextField = *ptrLongtextField
+	} else {
+		x.LongtextField = ""
+	}
+	if ptrEnumField != nil {
+		x.EnumField = *ptrEnumField
+	} else {
+		x.EnumField = ""
+	}
+	if ptrSetField != nil {
+		x.SetField = *ptrSetField
+	} else {
+		x.SetField = ""
+	}
+	if ptrBinaryField != nil {
+		x.BinaryField = *ptrBinaryField
+	} else {
+		x.BinaryField = nil
+	}
+	if ptrVarbinaryField != nil {
+		x.VarbinaryField = *ptrVarbinaryField
+	} else {
+		x.VarbinaryField = nil
+	}
+	if ptrBlobField != nil {
+		x.BlobField = *ptrBlobField
+	} else {
+		x.BlobField = nil
+	}
+	if ptrTinyblobField != nil {
+		x.TinyblobField = *ptrTinyblobField
+	} else {
+		x.TinyblobField = nil
+	}
+	if ptrMediumblobField != nil {
+		x.MediumblobField = *ptrMediumblobField
+	} else {
+		x.MediumblobField = nil
+	}
+	if ptrLongblobField != nil {
+		x.LongblobField = *ptrLongblobField
+	} else {
+		x.LongblobField = nil
+	}
+	if scnDateField.Valid {
+		x.DateField = scnDateField.Time
+	} else {
+		x.DateField = time.Time{}
+	}
+	if ptrTimeField != nil {
+		x.TimeField = *ptrTimeField
+	} else {
+		x.TimeField = ""
+	}
+	if ptrYearField != nil {
+		x.YearField = *ptrYearField
+	} else {
+		x.YearField = 0
+	}
+	if scnDatetimeField.Valid {
+		x.DatetimeField = scnDatetimeField.Time
+	} else {
+		x.DatetimeField = time.Time{}
+	}
+	if scnTimestampField.Valid {
+		x.TimestampField = scnTimestampField.Time
+	} else {
+		x.TimestampField = time.Time{}
+	}
+	if ptrUuidField != nil {
+		x.UuidField = *ptrUuidField
+	} else {
+		x.UuidField = ""
+	}
+	return x, nil
+}
+
+func readRows(fields []string, rows *sql.Rows) (_ []*Entity, err error) {
+	defer func() {
+		if cerr := rows.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}()
+	var results []*Entity
+	for rows.Next() {
+		x, err := scanRow(fields, rows)
+		if err != nil {
+			return results, err
+		}
+		results = append(results, x)
+	}
+	if rerr := rows.Err(); rerr != nil {
+		return results, rerr
+	}
+	return results, nil
+}
+
+func bindStmtCtxTx(base *sql.Stmt, ctx context.Context, tx *sql.Tx) (*sql.Stmt, bool) {
+	if tx == nil {
+		return base, false
+	}
+	if ctx != nil {
+		return tx.StmtContext(ctx, base), true
+	}
+	return tx.Stmt(base), true
+}
+
+func (n *conn) execCore(ctx context.Context, tx *sql.Tx, name, query string, args ...any) (res sql.Result, err error) {
+	ctx, done := n.trace(ctx, "exec", name, query, len(args))
+	defer func() {
+		n.report(err)
+		err = Shared.WrapError(err)
+		done(resultRows(res), err)
+	}()
+	stmt, err := n.getPreparedStmt(query)
+	if err != nil {
+		return nil, err
+	}
+	s, needClose := bindStmtCtxTx(stmt, ctx, tx)
+	if needClose {
+		defer func() {
+			if cerr := s.Close(); err == nil && cerr != nil {
+				err = cerr
+			}
+		}()
+	}
+	if ctx != nil {
+		return s.ExecContext(ctx, args...)
+	}
+	return s.Exec(args...)
+}
+
+// execOnce is execCore without stmtCache, for statements whose text is
+// unlikely to repeat, such as a DBInsertMany batch of an odd size.
+func (n *conn) execOnce(ctx context.Context, tx *sql.Tx, name, query string, args ...any) (res sql.Result, err error) {
+	ctx, done := n.trace(ctx, "exec", name, query, len(args))
+	defer func() {
+		n.report(err)
+		err = Shared.WrapError(err)
+		done(resultRows(res), err)
+	}()
+	if tx != nil {
+		if ctx != nil {
+			return tx.ExecContext(ctx, query, args...)
+		}
+		return tx.Exec(query, args...)
+	}
+	if n.db == nil {
+		return nil, errors.New("db not initialized")
+	}
+	if ctx != nil {
+		return n.db.ExecContext(ctx, query, args...)
+	}
+	return n.db.Exec(query, args...)
+}
+
+func (n *conn) queryCore(ctx context.Context, tx *sql.Tx, name string, fields []string, query string, args ...any) (out []*Entity, err error) {
+	ctx, done := n.trace(ctx, "query", name, query, len(args))
+	defer func() {
+		n.report(err)
+		err = Shared.WrapError(err)
+		done(int64(len(out)), err)
+	}()
+	stmt, err := n.getPreparedStmt(query)
+	if err != nil {
+		return nil, err
+	}
+	s, needClose := bindStmtCtxTx(stmt, ctx, tx)
+	if needClose {
+		defer func() {
+			if cerr := s.Close(); err == nil && cerr != nil {
+				err = cerr
+			}
+		}()
+	}
+	var rows *sql.Rows
+	if ctx != nil {
+		rows, err = s.QueryContext(ctx, args...)
+	} else {
+		rows, err = s.Query(args...)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if out, err = readRows(fields, rows); err != nil {
+		return out, err
+	}
+	return out, afterLoad(ctx, tx, out...)
+}
+
+func (n *conn) queryOneCore(ctx context.Context, tx *sql.Tx, name string, fields []string, query string, args ...any) (out *Entity, err error) {
+	ctx, done := n.trace(ctx, "query", name, query, len(args))
+	defer func() {
+		n.report(err)
+		err = Shared.WrapError(err)
+		rows := int64(0)
+		if out != nil {
+			rows = 1
+		}
+		done(rows, err)
+	}()
+	stmt, err := n.getPreparedStmt(query)
+	if err != nil {
+		return nil, err
+	}
+	s, needClose := bindStmtCtxTx(stmt, ctx, tx)
+	if needClose {
+		defer func() {
+			if cerr := s.Close(); err == nil && cerr != nil {
+				err = cerr
+			}
+		}()
 	}
-	if ptrBigSigned != nil {
-		x.BigSigned = *ptrBigSigned
+	var rows *sql.Rows
+	if ctx != nil {
+		rows, err = s.QueryContext(ctx, args...)
 	} else {
-		x.BigSigned = ""
+		rows, err = s.Query(args...)
 	}
-	if ptrBigUnsigned != nil {
-		x.BigUnsigned = *ptrBigUnsigned
-	} else {
-		x.BigUnsigned = ""
+	if err != nil {
+		return nil, err
 	}
-	if ptrFloatField != nil {
-		x.FloatField = *ptrFloatField
-	} else {
-		x.FloatField = ""
+	defer func() {
+		if cerr := rows.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}()
+	if !rows.Next() {
+		if rerr := rows.Err(); rerr != nil {
+			return nil, rerr
+		}
+		return nil, nil
 	}
-	if ptrDoubleField != nil {
-		x.DoubleField = *ptrDoubleField
-	} else {
-		x.DoubleField = ""
+	ent, err := scanRow(fields, rows)
+	if err != nil {
+		return nil, err
 	}
-	if ptrRealField != nil {
-		x.RealField = *ptrRealField
-	} else {
-		x.RealField = ""
+	if rows.Next() {
+		return nil, errors.New("queryOneCore: expected one row, got multiple")
 	}
-	if ptrDecimalField != nil {
-		x.DecimalField = *ptrDecimalField
-	} else {
-		x.DecimalField = ""
+	if rerr := rows.Err(); rerr != nil {
+		return nil, rerr
 	}
-	if ptrDecField != nil {
-		x.DecField = *ptrDecField
-	} else {
-		x.DecField = ""
+	if err = afterLoad(ctx, tx, ent); err != nil {
+		return nil, err
 	}
-	if ptrNumericField != nil {
-		x.NumericField = *ptrNumericField
-	} else {
-		x.NumericField = ""
+	return ent, nil
+}
+
+func (n *conn) scalarCore(ctx context.Context, tx *sql.Tx, name, query string, args ...any) (_ int, err error) {
+	ctx, done := n.trace(ctx, "query", name, query, len(args))
+	defer func() {
+		n.report(err)
+		err = Shared.WrapError(err)
+		rows := int64(1)
+		if err != nil {
+			rows = 0
+		}
+		done(rows, err)
+	}()
+	stmt, err := n.getPreparedStmt(query)
+	if err != nil {
+		return 0, err
 	}
-	if ptrFixedField != nil {
-		x.FixedField = *ptrFixedField
-	} else {
-		x.FixedField = ""
+	s, needClose := bindStmtCtxTx(stmt, ctx, tx)
+	if needClose {
+		defer func() {
+			if cerr := s.Close(); err == nil && cerr != nil {
+				err = cerr
+			}
+		}()
 	}
-	if ptrBit1 != nil {
-		x.Bit1 = *ptrBit1
+	var v int
+	if ctx != nil {
+		err = s.QueryRowContext(ctx, args...).Scan(&v)
 	} else {
-		x.Bit1 = ""
+		err = s.QueryRow(args...).Scan(&v)
 	}
-	if ptrBit8 != nil {
-		x.Bit8 = *ptrBit8
-	} else {
-		x.Bit8 = ""
+	return v, err
+}
+
+type Condition struct {
+	field    string
+	op       string
+	args     []any
+	children []*Condition
+}
+
+func Eq(field string, value any) *Condition {
+	return &Condition{field: field, op: "=", args: []any{value}}
+}
+
+func Ne(field string, value any) *Condition {
+	return &Condition{field: field, op: "!=", args: []any{value}}
+}
+
+func Lt(field string, value any) *Condition {
+	return &Condition{field: field, op: "<", args: []any{value}}
+}
+
+func Le(field string, value any) *Condition {
+	return &Condition{field: field, op: "<=", args: []any{value}}
+}
+
+func Gt(field string, value any) *Condition {
+	return &Condition{field: field, op: ">", args: []any{value}}
+}
+
+func Ge(field string, value any) *Condition {
+	return &Condition{field: field, op: ">=", args: []any{value}}
+}
+
+func Like(field string, pattern any) *Condition {
+	return &Condition{field: field, op: "LIKE", args: []any{pattern}}
+}
+
+func In(field string, values ...any) *Condition {
+	return &Condition{field: field, op: "IN", args: values}
+}
+
+func NotIn(field string, values ...any) *Condition {
+	return &Condition{field: field, op: "NOT IN", args: values}
+}
+
+func Between(field string, from, to any) *Condition {
+	return &Condition{field: field, op: "BETWEEN", args: []any{from, to}}
+}
+
+func IsNull(field string) *Condition {
+	return &Condition{field: field, op: "IS NULL"}
+}
+
+func IsNotNull(field string) *Condition {
+	return &Condition{field: field, op: "IS NOT NULL"}
+}
+
+func And(conditions ...*Condition) *Condition {
+	return &Condition{op: "AND", children: conditions}
+}
+
+func Or(conditions ...*Condition) *Condition {
+	return &Condition{op: "OR", children: conditions}
+}
+
+// build appends the SQL of the condition to sb and its arguments to args.
+// The generated SQL depends only on the shape of the condition, so the
+// resulting statement is reused from stmtCache across calls.
+func (c *Condition) build(sb *strings.Builder, args []any) ([]any, error) {
+	if c == nil {
+		return args, errors.New("nil condition")
+	}
+	switch c.op {
+	case "AND", "OR":
+		if len(c.children) == 0 {
+			if c.op == "AND" {
+				sb.WriteString("1 = 1")
+			} else {
+				sb.WriteString("1 = 0")
+			}
+			return args, nil
+		}
+		sb.WriteString("(")
+		for i, child := range c.children {
+			if i > 0 {
+				sb.WriteString(" " + c.op + " ")
+			}
+			var err error
+			if args, err = child.build(sb, args); err != nil {
+				return args, err
+			}
+		}
+		sb.WriteString(")")
+		return args, nil
+	}
+	qf := GetQualifiedField(c.field)
+	if qf == "" {
+		return args, errors.New("unknown field: " + c.field)
+	}
+	switch c.op {
+	case "IS NULL", "IS NOT NULL":
+		sb.WriteString(qf + " " + c.op)
+	case "IN", "NOT IN":
+		if len(c.args) == 0 {
+			if c.op == "IN" {
+				sb.WriteString("1 = 0")
+			} else {
+				sb.WriteString("1 = 1")
+			}
+			return args, nil
+		}
+		sb.WriteString(qf + " " + c.op + " (" + strings.Repeat("?, ", len(c.args)-1) + "?)")
+	case "BETWEEN":
+		sb.WriteString(qf + " BETWEEN ? AND ?")
+	default:
+		sb.WriteString(qf + " " + c.op + " ?")
 	}
-	if ptrBit64 != nil {
-		x.Bit64 = *ptrBit64
-	} else {
-		x.Bit64 = ""
+	return append(args, c.args...), nil
+}
+
+// buildWhere combines the equality fields, whose values are taken from x, with
+// the explicit conditions into a single AND-ed WHERE clause.
+func buildWhere(x *Entity, whereFields []string, conditions []*Condition) (string, []any, error) {
+	if len(whereFields) == 0 && len(conditions) == 0 {
+		return "", nil, nil
+	}
+	var sb strings.Builder
+	sb.WriteString(" WHERE ")
+	sb.WriteString(strings.Join(GetQualifiedConditions(whereFields), " AND "))
+	args := x.GetFieldsValues(whereFields)
+	for i, c := range conditions {
+		if i > 0 || len(whereFields) > 0 {
+			sb.WriteString(" AND ")
+		}
+		var err error
+		if args, err = c.build(&sb, args); err != nil {
+			return "", nil, err
+		}
 	}
-	if ptrBoolField != nil {
-		x.BoolField = *ptrBoolField
-	} else {
-		x.BoolField = ""
+	return sb.String(), args, nil
+}
+
+type Order struct {
+	Field string
+	Desc  bool
+}
+
+func Asc(field string) Order {
+	return Order{Field: field}
+}
+
+func Desc(field string) Order {
+	return Order{Field: field, Desc: true}
+}
+
+// buildOrderLimit renders the ORDER BY, LIMIT and OFFSET clauses of params.
+// LIMIT and OFFSET are bound as arguments so that paging does not produce
+// a new prepared statement per page.
+func buildOrderLimit(params *QueryParams) (string, []any, error) {
+	if params == nil {
+		return "", nil, nil
 	}
-	if ptrBooleanField != nil {
-		x.BooleanField = *ptrBooleanField
-	} else {
-		x.BooleanField = ""
+	var sb strings.Builder
+	var args []any
+	for i, o := range params.OrderBy {
+		qf := GetQualifiedField(o.Field)
+		if qf == "" {
+			return "", nil, errors.New("unknown field: " + o.Field)
+		}
+		if i == 0 {
+			sb.WriteString(" ORDER BY ")
+		} else {
+			sb.WriteString(", ")
+		}
+		sb.WriteString(qf)
+		if o.Desc {
+			sb.WriteString(" DESC")
+		} else {
+			sb.WriteString(" ASC")
+		}
 	}
-	if ptrCharField != nil {
-		x.CharField = *ptrCharField
-	} else {
-		x.CharField = ""
+	if params.Limit < 0 || params.Offset < 0 {
+		return "", nil, errors.New("params.Limit and params.Offset must not be negative")
 	}
-	if ptrVarcharField != nil {
-		x.VarcharField = *ptrVarcharField
-	} else {
-		x.VarcharField = ""
+	if params.Limit > 0 {
+		sb.WriteString(" LIMIT ?")
+		args = append(args, params.Limit)
+	} else if params.Offset > 0 {
+		sb.WriteString(" LIMIT 18446744073709551615")
 	}
-	if ptrTextField != nil {
-		x.TextField = *ptrTextField
-	} else {
-		x.TextField = ""
+	if params.Offset > 0 {
+		sb.WriteString(" OFFSET ?")
+		args = append(args, params.Offset)
 	}
-	if ptrTinytextField != nil {
-		x.TinytextField = *ptrTinytextField
-	} else {
-		x.TinytextField = ""
+	return sb.String(), args, nil
+}
+
+type cursorKey struct {
+	Field string `json:"f"`
+	Desc  bool   `json:"d,omitempty"`
+}
+
+type cursorToken struct {
+	Table  string            `json:"t"`
+	Order  []cursorKey       `json:"o"`
+	Values []json.RawMessage `json:"v"`
+}
+
+// encodeCursor captures the values of the ordering fields of x in an opaque token.
+// checkPageOrder rejects orderings that do not order rows totally: every
+// field must be NOT NULL, and together they must cover the primary key or a
+// unique key, or rows would be skipped or repeated across pages.
+func checkPageOrder(orders []Order) error {
+	var zero Entity
+	ordered := make(map[string]bool, len(orders))
+	for _, o := range orders {
+		if _, ok := zero.GetFieldValue(o.Field).(driver.Valuer); ok {
+			return errors.New("DBSelectPage does not support nullable OrderBy field: " + o.Field)
+		}
+		ordered[o.Field] = true
+	}
+	for _, key := range UniqueKeys {
+		covered := true
+		for _, field := range key {
+			if !ordered[field] {
+				covered = false
+				break
+			}
+		}
+		if covered {
+			return nil
+		}
 	}
-	if ptrMediumtextField != nil {
-		x.MediumtextField = *ptrMediumtextField
-	} else {
-		x.MediumtextField = ""
+	return errors.New("DBSelectPage requires params.OrderBy to include the primary key or a unique key")
+}
+
+func encodeCursor(orders []Order, x *Entity) (string, error) {
+	token := cursorToken{Table: FQTN, Order: make([]cursorKey, 0, len(orders)), Values: make([]json.RawMessage, 0, len(orders))}
+	for _, o := range orders {
+		v := x.GetFieldValue(o.Field)
+		if valuer, ok := v.(driver.Valuer); ok {
+			if dv, err := valuer.Value(); err != nil || dv == nil {
+				return "", errors.New("cursor field must not be NULL: " + o.Field)
+			}
+		}
+		raw, err := json.Marshal(v)
+		if err != nil {
+			return "", err
+		}
+		token.Order = append(token.Order, cursorKey{Field: o.Field, Desc: o.Desc})
+		token.Values = append(token.Values, raw)
 	}
-	if ptrLongtextField != nil {
-		x.LongtextField = *ptrLongtextField
-	} else {
-		x.LongtextField = ""
+	b, err := json.Marshal(token)
+	if err != nil {
+		return "", err
 	}
-	if ptrEnumField != nil {
-		x.EnumField = *ptrEnumField
-	} else {
-		x.EnumField = ""
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// decodeCursor validates that cursor was issued for this table and ordering
+// and returns the typed values of the ordering fields.
+func decodeCursor(orders []Order, cursor string) ([]any, error) {
+	errInvalid := errors.New("invalid cursor")
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, errInvalid
 	}
-	if ptrSetField != nil {
-		x.SetField = *ptrSetField
-	} else {
-		x.SetField = ""
+	var token cursorToken
+	if err = json.Unmarshal(b, &token); err != nil {
+		return nil, errInvalid
 	}
-	if ptrBinaryField != nil {
-		x.BinaryField = *ptrBinaryField
-	} else {
-		x.BinaryField = ""
+	if token.Table != FQTN || len(token.Order) != len(orders) || len(token.Values) != len(orders) {
+		return nil, errInvalid
 	}
-	if ptrVarbinaryField != nil {
-		x.VarbinaryField = *ptrVarbinaryField
-	} else {
-		x.VarbinaryField = ""
+	values := make([]any, 0, len(orders))
+	for i, o := range orders {
+		if token.Order[i].Field != o.Field || token.Order[i].Desc != o.Desc {
+			return nil, errors.New("cursor does not match params.OrderBy")
+		}
+		v, err := decodeCursorValue(o.Field, token.Values[i])
+		if err != nil {
+			return nil, errInvalid
+		}
+		values = append(values, v)
 	}
-	if ptrBlobField != nil {
-		x.BlobField = *ptrBlobField
-	} else {
-		x.BlobField = ""
+	return values, nil
+}
+
+// seekCondition selects the rows strictly after values in the given ordering:
+// (a > ?) OR (a = ? AND b > ?) OR ...
+func seekCondition(orders []Order, values []any) *Condition {
+	branches := make([]*Condition, 0, len(orders))
+	for i, o := range orders {
+		terms := make([]*Condition, 0, i+1)
+		for j := 0; j < i; j++ {
+			terms = append(terms, Eq(orders[j].Field, values[j]))
+		}
+		if o.Desc {
+			terms = append(terms, Lt(o.Field, values[i]))
+		} else {
+			terms = append(terms, Gt(o.Field, values[i]))
+		}
+		branches = append(branches, And(terms...))
 	}
-	if ptrTinyblobField != nil {
-		x.TinyblobField = *ptrTinyblobField
-	} else {
-		x.TinyblobField = ""
+	return Or(branches...)
+}
+
+func decodeCursorValue(field string, raw json.RawMessage) (any, error) {
+	switch field {
+	case FieldId:
+		var v int32
+		err := json.Unmarshal(raw, &v)
+		return v, err
+	case FieldTinySigned:
+		var v int8
+		err := json.Unmarshal(raw, &v)
+		return v, err
+	case FieldTinyUnsigned:
+		var v uint8
+		err := json.Unmarshal(raw, &v)
+		return v, err
+	case FieldSmallSigned:
+		var v int16
+		err := json.Unmarshal(raw, &v)
+		return v, err
+	case FieldSmallUnsigned:
+		var v uint16
+		err := json.Unmarshal(raw, &v)
+		return v, err
+	case FieldMediumSigned:
+		var v int32
+		err := json.Unmarshal(raw, &v)
+		return v, err
+	case FieldMediumUnsigned:
+		var v uint32
+		err := json.Unmarshal(raw, &v)
+		return v, err
+	case FieldIntSigned:
+		var v int32
+		err := json.Unmarshal(raw, &v)
+		return v, err
+	case FieldIntUnsigned:
+		var v uint32
+		err := json.Unmarshal(raw, &v)
+		return v, err
+	case FieldBigSigned:
+		var v int64
+		err := json.Unmarshal(raw, &v)
+		return v, err
+	case FieldBigUnsigned:
+		var v uint64
+		err := json.Unmarshal(raw, &v)
+		return v, err
+	case FieldFloatField:
+		var v float32
+		err := json.Unmarshal(raw, &v)
+		return v, err
+	case FieldDoubleField:
+		var v float64
+		err := json.Unmarshal(raw, &v)
+		return v, err
+	case FieldRealField:
+		var v float64
+		err := json.Unmarshal(raw, &v)
+		return v, err
+	case FieldDecimalField:
+		var v string
+		err := json.Unmarshal(raw, &v)
+		return v, err
+	case FieldDecField:
+		var v string
+		err := json.Unmarshal(raw, &v)
+		return v, err
+	case FieldNumericField:
+		var v string
+		err := json.Unmarshal(raw, &v)
+		return v, err
+	case FieldFixedField:
+		var v string
+		err := json.Unmarshal(raw, &v)
+		return v, err
+	case FieldBit1:
+		var v bool
+		err := json.Unmarshal(raw, &v)
+		return v, err
+	case FieldBit8:
+		var v uint64
+		err := json.Unmarshal(raw, &v)
+		return v, err
+	case FieldBit64:
+		var v uint64
+		err := json.Unmarshal(raw, &v)
+		return v, err
+	case FieldBoolField:
+		var v bool
+		err := json.Unmarshal(raw, &v)
+		return v, err
+	case FieldBooleanField:
+		var v bool
+		err := json.Unmarshal(raw, &v)
+		return v, err
+	case FieldCharField:
+		var v string
+		err := json.Unmarshal(raw, &v)
+		return v, err
+	case FieldVarcharField:
+		var v string
+		err := json.Unmarshal(raw, &v)
+		return v, err
+	case FieldTextField:
+		var v string
+		err := json.Unmarshal(raw, &v)
+		return v, err
+	case FieldTinytextField:
+		var v string
+		err := json.Unmarshal(raw, &v)
+		return v, err
+	case FieldMediumtextField:
+		var v string
+		err := json.Unmarshal(raw, &v)
+		return v, err
+	case FieldLongtextField:
+		var v string
+		err := json.Unmarshal(raw, &v)
+		return v, err
+	case FieldEnumField:
+		var v string
+		err := json.Unmarshal(raw, &v)
+		return v, err
+	case FieldSetField:
+		var v string
+		err := json.Unmarshal(raw, &v)
+		return v, err
+	case FieldBinaryField:
+		var v []byte
+		err := json.Unmarshal(raw, &v)
+		return v, err
+	case FieldVarbinaryField:
+		var v []byte
+		err := json.Unmarshal(raw, &v)
+		return v, err
+	case FieldBlobField:
+		var v []byte
+		err := json.Unmarshal(raw, &v)
+		return v, err
+	case FieldTinyblobField:
+		var v []byte
+		err := json.Unmarshal(raw, &v)
+		return v, err
+	case FieldMediumblobField:
+		var v []byte
+		err := json.Unmarshal(raw, &v)
+		return v, err
+	case FieldLongblobField:
+		var v []byte
+		err := json.Unmarshal(raw, &v)
+		return v, err
+	case FieldDateField:
+		var v time.Time
+		err := json.Unmarshal(raw, &v)
+		return v, err
+	case FieldTimeField:
+		var v string
+		err := json.Unmarshal(raw, &v)
+		return v, err
+	case FieldYearField:
+		var v uint16
+		err := json.Unmarshal(raw, &v)
+		return v, err
+	case FieldDatetimeField:
+		var v time.Time
+		err := json.Unmarshal(raw, &v)
+		return v, err
+	case FieldTimestampField:
+		var v time.Time
+		err := json.Unmarshal(raw, &v)
+		return v, err
+	case FieldUuidField:
+		var v string
+		err := json.Unmarshal(raw, &v)
+		return v, err
 	}
-	if ptrMediumblobField != nil {
-		x.MediumblobField = *ptrMediumblobField
-	} else {
-		x.MediumblobField = ""
+	return nil, errors.New("unknown field: " + field)
+}
+
+func (c *Client) dbTruncate(ctx context.Context, tx *sql.Tx) *QueryResult {
+	res, err := c.primary.execCore(ctx, tx, "Truncate", "TRUNCATE TABLE "+FQTN)
+	return &QueryResult{Result: res, Error: err}
+}
+
+func DBTruncate() *QueryResult {
+	return defaultClient.dbTruncate(nil, nil)
+}
+func DBTruncateCtx(ctx context.Context) *QueryResult {
+	return defaultClient.dbTruncate(ctx, nil)
+}
+func DBTruncateTx(tx *sql.Tx) *QueryResult {
+	return defaultClient.dbTruncate(nil, tx)
+}
+func DBTruncateCtxTx(ctx context.Context, tx *sql.Tx) *QueryResult {
+	return defaultClient.dbTruncate(ctx, tx)
+}
+func (c *Client) DBTruncate() *QueryResult {
+	return c.dbTruncate(nil, nil)
+}
+func (c *Client) DBTruncateCtx(ctx context.Context) *QueryResult {
+	return c.dbTruncate(ctx, nil)
+}
+func (c *Client) DBTruncateTx(tx *sql.Tx) *QueryResult {
+	return c.dbTruncate(nil, tx)
+}
+func (c *Client) DBTruncateCtxTx(ctx context.Context, tx *sql.Tx) *QueryResult {
+	return c.dbTruncate(ctx, tx)
+}
+
+func (c *Client) dbInsert(ctx context.Context, tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult {
+	if err := runHook(ctx, tx, x, "BeforeInsert"); err != nil {
+		return &QueryResult{Error: err}
 	}
-	if ptrLongblobField != nil {
-		x.LongblobField = *ptrLongblobField
-	} else {
-		x.LongblobField = ""
+	fieldsToInsert := Fields
+	if params != nil && len(params.Insert) > 0 {
+		fieldsToInsert = params.Insert
 	}
-	if ptrDateField != nil {
-		x.DateField = *ptrDateField
-	} else {
-		x.DateField = ""
+	if ValidateOnWrite {
+		if err := x.ValidateFields(fieldsToInsert...); err != nil {
+			return &QueryResult{Error: err}
+		}
 	}
-	if ptrTimeField != nil {
-		x.TimeField = *ptrTimeField
-	} else {
-		x.TimeField = ""
+	q := "INSERT INTO " + FQTN + " (" + strings.Join(GetQualifiedFields(fieldsToInsert), ", ") + ") VALUES (" + strings.Join(GetValuesPlaceholders(fieldsToInsert), ", ") + ")"
+	res, err := c.primary.execCore(ctx, tx, "Insert", q, x.GetFieldsValues(fieldsToInsert)...)
+	return afterHook(ctx, tx, x, "AfterInsert", &QueryResult{Result: res, Error: err})
+}
+
+func (x *Entity) DBInsert(params *QueryParams) *QueryResult {
+	return defaultClient.dbInsert(nil, nil, x, params)
+}
+func (x *Entity) DBInsertCtx(ctx context.Context, params *QueryParams) *QueryResult {
+	return defaultClient.dbInsert(ctx, nil, x, params)
+}
+func (x *Entity) DBInsertTx(tx *sql.Tx, params *QueryParams) *QueryResult {
+	return defaultClient.dbInsert(nil, tx, x, params)
+}
+func (x *Entity) DBInsertCtxTx(ctx context.Context, tx *sql.Tx, params *QueryParams) *QueryResult {
+	return defaultClient.dbInsert(ctx, tx, x, params)
+}
+func (c *Client) DBInsert(x *Entity, params *QueryParams) *QueryResult {
+	return c.dbInsert(nil, nil, x, params)
+}
+func (c *Client) DBInsertCtx(ctx context.Context, x *Entity, params *QueryParams) *QueryResult {
+	return c.dbInsert(ctx, nil, x, params)
+}
+func (c *Client) DBInsertTx(tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult {
+	return c.dbInsert(nil, tx, x, params)
+}
+func (c *Client) DBInsertCtxTx(ctx context.Context, tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult {
+	return c.dbInsert(ctx, tx, x, params)
+}
+
+// dbInsertReturning inserts x and reads back params.Select (defaults to all
+// fields) as computed by the server, including defaults and trigger changes.
+func (c *Client) dbInsertReturning(ctx context.Context, tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult {
+	if err := runHook(ctx, tx, x, "BeforeInsert"); err != nil {
+		return &QueryResult{Error: err}
 	}
-	if ptrYearField != nil {
-		x.YearField = *ptrYearField
-	} else {
-		x.YearField = ""
+	fieldsToInsert := Fields
+	fieldsToReturn := Fields
+	if params != nil && len(params.Insert) > 0 {
+		fieldsToInsert = params.Insert
 	}
-	if ptrDatetimeField != nil {
-		x.DatetimeField = *ptrDatetimeField
-	} else {
-		x.DatetimeField = ""
+	if ValidateOnWrite {
+		if err := x.ValidateFields(fieldsToInsert...); err != nil {
+			return &QueryResult{Error: err}
+		}
 	}
-	if ptrTimestampField != nil {
-		x.TimestampField = *ptrTimestampField
-	} else {
-		x.TimestampField = ""
+	if params != nil && len(params.Select) > 0 {
+		fieldsToReturn = params.Select
 	}
-	if ptrUuidField != nil {
-		x.UuidField = *ptrUuidField
-	} else {
-		x.UuidField = ""
+	q := "INSERT INTO " + FQTN + " (" + strings.Join(GetQualifiedFields(fieldsToInsert), ", ") + ") VALUES (" + strings.Join(GetValuesPlaceholders(fieldsToInsert), ", ") + ") RETURNING " + strings.Join(GetQualifiedFields(fieldsToReturn), ", ")
+	entities, err := c.primary.queryCore(ctx, tx, "InsertReturning", fieldsToReturn, q, x.GetFieldsValues(fieldsToInsert)...)
+	track(params, fieldsToReturn, entities...)
+	result := &QueryResult{Entities: entities, Error: err}
+	if len(entities) > 0 {
+		result.Entity = entities[0]
 	}
-	return x, nil
+	return afterHook(ctx, tx, x, "AfterInsert", result)
 }
 
-func readRows(fields []string, rows *sql.Rows) (_ []*Entity, err error) {
-	defer func() {
-		if cerr := rows.Close(); cerr != nil && err == nil {
-			err = cerr
-		}
-	}()
-	var results []*Entity
-	for rows.Next() {
-		x, err := scanRow(fields, rows)
-		if err != nil {
-			return results, err
-		}
-		results = append(results, x)
-	}
-	if rerr := rows.Err(); rerr != nil {
-		return results, rerr
-	}
-	return results, nil
+func (x *Entity) DBInsertReturning(params *QueryParams) *QueryResult {
+	return defaultClient.dbInsertReturning(nil, nil, x, params)
+}
+func (x *Entity) DBInsertReturningCtx(ctx context.Context, params *QueryParams) *QueryResult {
+	return defaultClient.dbInsertReturning(ctx, nil, x, params)
+}
+func (x *Entity) DBInsertReturningTx(tx *sql.Tx, params *QueryParams) *QueryResult {
+	return defaultClient.dbInsertReturning(nil, tx, x, params)
+}
+func (x *Entity) DBInsertReturningCtxTx(ctx context.Context, tx *sql.Tx, params *QueryParams) *QueryResult {
+	return defaultClient.dbInsertReturning(ctx, tx, x, params)
+}
+func (c *Client) DBInsertReturning(x *Entity, params *QueryParams) *QueryResult {
+	return c.dbInsertReturning(nil, nil, x, params)
+}
+func (c *Client) DBInsertReturningCtx(ctx context.Context, x *Entity, params *QueryParams) *QueryResult {
+	return c.dbInsertReturning(ctx, nil, x, params)
+}
+func (c *Client) DBInsertReturningTx(tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult {
+	return c.dbInsertReturning(nil, tx, x, params)
+}
+func (c *Client) DBInsertReturningCtxTx(ctx context.Context, tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult {
+	return c.dbInsertReturning(ctx, tx, x, params)
 }
 
-func bindStmtCtxTx(base *sql.Stmt, ctx context.Context, tx *sql.Tx) (*sql.Stmt, bool) {
-	if tx == nil {
-		return base, false
-	}
-	if ctx != nil {
-		return tx.StmtContext(ctx, base), true
+// maxPlaceholders is the protocol limit of bound arguments per prepared statement.
+const maxPlaceholders = 65535
+
+// estimatePacketSize approximates the bytes a bound argument takes in a
+// COM_STMT_EXECUTE packet: its type, length prefix and payload.
+func estimatePacketSize(v any) int {
+	switch v := v.(type) {
+	case nil:
+		return 2
+	case string:
+		return len(v) + 11
+	case []byte:
+		return len(v) + 11
+	case driver.Valuer:
+		if dv, err := v.Value(); err == nil {
+			if _, ok := dv.(driver.Valuer); !ok {
+				return estimatePacketSize(dv)
+			}
+		}
 	}
-	return tx.Stmt(base), true
+	return 14
 }
 
-func execCore(ctx context.Context, tx *sql.Tx, query string, args ...any) (res sql.Result, err error) {
-	stmt, err := getPreparedStmt(query)
-	if err != nil {
-		return nil, err
+// dbInsertMany writes entities with multi-row INSERT statements. Each batch
+// stays under maxPlaceholders bound arguments and MaxAllowedPacket bytes;
+// params.BatchSize lowers the number of rows per batch further. Statements
+// are cached per batch size. A failed batch does not stop the remaining ones,
+// its error is reported in BatchErrors.
+func (c *Client) dbInsertMany(ctx context.Context, tx *sql.Tx, entities []*Entity, params *QueryParams) *InsertManyResult {
+	result := &InsertManyResult{}
+	if len(entities) == 0 {
+		return result
 	}
-	s, needClose := bindStmtCtxTx(stmt, ctx, tx)
-	if needClose {
-		defer func() {
-			if cerr := s.Close(); err == nil && cerr != nil {
-				err = cerr
-			}
-		}()
+	for _, x := range entities {
+		if x == nil {
+			result.Error = errors.New("DBInsertMany does not accept nil entities")
+			return result
+		}
+		if err := runHook(ctx, tx, x, "BeforeInsert"); err != nil {
+			result.Error = err
+			return result
+		}
 	}
-	if ctx != nil {
-		return s.ExecContext(ctx, args...)
+	fieldsToInsert := Fields
+	if params != nil && len(params.Insert) > 0 {
+		fieldsToInsert = params.Insert
 	}
-	return s.Exec(args...)
-}
-
-func queryCore(ctx context.Context, tx *sql.Tx, fields []string, query string, args ...any) (out []*Entity, err error) {
-	stmt, err := getPreparedStmt(query)
-	if err != nil {
-		return nil, err
+	if ValidateOnWrite {
+		for _, x := range entities {
+			if err := x.ValidateFields(fieldsToInsert...); err != nil {
+				result.Error = err
+				return result
+			}
+		}
 	}
-	s, needClose := bindStmtCtxTx(stmt, ctx, tx)
-	if needClose {
-		defer func() {
-			if cerr := s.Close(); err == nil && cerr != nil {
-				err = cerr
+	rowsPerBatch := maxPlaceholders / len(fieldsToInsert)
+	if params != nil && params.BatchSize > 0 && params.BatchSize < rowsPerBatch {
+		rowsPerBatch = params.BatchSize
+	}
+	head := "INSERT INTO " + FQTN + " (" + strings.Join(GetQualifiedFields(fieldsToInsert), ", ") + ") VALUES "
+	row := "(" + strings.Join(GetValuesPlaceholders(fieldsToInsert), ", ") + ")"
+	args := make([]any, 0, rowsPerBatch*len(fieldsToInsert))
+	start, size := 0, len(head)
+	var hookErr error
+	flush := func(end int) {
+		q := head + strings.Repeat(row+", ", end-start-1) + row
+		// Only full batches are prepared and cached, so stmtCache holds one
+		// statement per batch size instead of one per row count.
+		exec := c.primary.execOnce
+		if end-start == rowsPerBatch {
+			exec = c.primary.execCore
+		}
+		res, err := exec(ctx, tx, "InsertMany", q, args...)
+		if err == nil {
+			var n int64
+			if n, err = res.RowsAffected(); err == nil {
+				result.RowsAffected += n
 			}
-		}()
+		}
+		if err == nil {
+			for _, x := range entities[start:end] {
+				if herr := runHook(ctx, tx, x, "AfterInsert"); herr != nil && hookErr == nil {
+					hookErr = herr
+				}
+			}
+		}
+		if err != nil {
+			result.BatchErrors = append(result.BatchErrors, &BatchError{Batch: result.Batches, Offset: start, Count: end - start, Err: err})
+		}
+		result.Batches++
+		start, size, args = end, len(head), args[:0]
+	}
+	for i, x := range entities {
+		values := x.GetFieldsValues(fieldsToInsert)
+		rowSize := len(row) + 2
+		for _, v := range values {
+			rowSize += estimatePacketSize(v)
+		}
+		if i > start && (i-start == rowsPerBatch || size+rowSize > MaxAllowedPacket) {
+			flush(i)
+		}
+		args = append(args, values...)
+		size += rowSize
 	}
-	var rows *sql.Rows
-	if ctx != nil {
-		rows, err = s.QueryContext(ctx, args...)
-	} else {
-		rows, err = s.Query(args...)
+	flush(len(entities))
+	errs := make([]error, 0, len(result.BatchErrors)+1)
+	for _, be := range result.BatchErrors {
+		errs = append(errs, be)
 	}
-	if err != nil {
-		return nil, err
+	if hookErr != nil {
+		errs = append(errs, hookErr)
 	}
-	return readRows(fields, rows)
+	result.Error = errors.Join(errs...)
+	return result
 }
 
-func queryOneCore(ctx context.Context, tx *sql.Tx, fields []string, query string, args ...any) (_ *Entity, err error) {
-	stmt, err := getPreparedStmt(query)
-	if err != nil {
-		return nil, err
-	}
-	s, needClose := bindStmtCtxTx(stmt, ctx, tx)
-	if needClose {
-		defer func() {
-			if cerr := s.Close(); err == nil && cerr != nil {
-				err = cerr
-			}
-		}()
-	}
-	var rows *sql.Rows
-	if ctx != nil {
-		rows, err = s.QueryContext(ctx, args...)
-	} else {
-		rows, err = s.Query(args...)
+func DBInsertMany(entities []*Entity, params *QueryParams) *InsertManyResult {
+	return defaultClient.dbInsertMany(nil, nil, entities, params)
+}
+func DBInsertManyCtx(ctx context.Context, entities []*Entity, params *QueryParams) *InsertManyResult {
+	return defaultClient.dbInsertMany(ctx, nil, entities, params)
+}
+func DBInsertManyTx(tx *sql.Tx, entities []*Entity, params *QueryParams) *InsertManyResult {
+	return defaultClient.dbInsertMany(nil, tx, entities, params)
+}
+func DBInsertManyCtxTx(ctx context.Context, tx *sql.Tx, entities []*Entity, params *QueryParams) *InsertManyResult {
+	return defaultClient.dbInsertMany(ctx, tx, entities, params)
+}
+func (c *Client) DBInsertMany(entities []*Entity, params *QueryParams) *InsertManyResult {
+	return c.dbInsertMany(nil, nil, entities, params)
+}
+func (c *Client) DBInsertManyCtx(ctx context.Context, entities []*Entity, params *QueryParams) *InsertManyResult {
+	return c.dbInsertMany(ctx, nil, entities, params)
+}
+func (c *Client) DBInsertManyTx(tx *sql.Tx, entities []*Entity, params *QueryParams) *InsertManyResult {
+	return c.dbInsertMany(nil, tx, entities, params)
+}
+func (c *Client) DBInsertManyCtxTx(ctx context.Context, tx *sql.Tx, entities []*Entity, params *QueryParams) *InsertManyResult {
+	return c.dbInsertMany(ctx, tx, entities, params)
+}
+
+func (c *Client) dbUpsertCore(ctx context.Context, tx *sql.Tx, x *Entity, params *QueryParams, op, verb, suffix string) *QueryResult {
+	if err := runHook(ctx, tx, x, "BeforeInsert"); err != nil {
+		return &QueryResult{Error: err}
 	}
-	if err != nil {
-		return nil, err
+	fieldsToInsert := Fields
+	if params != nil && len(params.Insert) > 0 {
+		fieldsToInsert = params.Insert
 	}
-	defer func() {
-		if cerr := rows.Close(); cerr != nil && err == nil {
-			err = cerr
-		}
-	}()
-	if !rows.Next() {
-		if rerr := rows.Err(); rerr != nil {
-			return nil, rerr
+	if ValidateOnWrite {
+		if err := x.ValidateFields(fieldsToInsert...); err != nil {
+			return &QueryResult{Error: err}
 		}
-		return nil, nil
 	}
-	ent, err := scanRow(fields, rows)
+	q := verb + " " + FQTN + " (" + strings.Join(GetQualifiedFields(fieldsToInsert), ", ") + ") VALUES (" + strings.Join(GetValuesPlaceholders(fieldsToInsert), ", ") + ")" + suffix
+	res, err := c.primary.execCore(ctx, tx, op, q, x.GetFieldsValues(fieldsToInsert)...)
 	if err != nil {
-		return nil, err
+		return &QueryResult{Result: res, Error: err}
 	}
-	if rows.Next() {
-		return nil, errors.New("queryOneCore: expected one row, got multiple")
+	n, err := res.RowsAffected()
+	if err != nil {
+		return &QueryResult{Result: res, Error: err}
 	}
-	if rerr := rows.Err(); rerr != nil {
-		return nil, rerr
+	outcome := UpsertUpdated
+	switch n {
+	case 0:
+		outcome = UpsertUnchanged
+	case 1:
+		outcome = UpsertInserted
 	}
-	return ent, nil
+	return afterHook(ctx, tx, x, "AfterInsert", &QueryResult{Result: res, Outcome: outcome})
 }
 
-func scalarCore(ctx context.Context, tx *sql.Tx, query string, args ...any) (_ int, err error) {
-	stmt, err := getPreparedStmt(query)
-	if err != nil {
-		return 0, err
-	}
-	s, needClose := bindStmtCtxTx(stmt, ctx, tx)
-	if needClose {
-		defer func() {
-			if cerr := s.Close(); err == nil && cerr != nil {
-				err = cerr
-			}
-		}()
-	}
-	var v int
-	if ctx != nil {
-		err = s.QueryRowContext(ctx, args...).Scan(&v)
-	} else {
-		err = s.QueryRow(args...).Scan(&v)
-	}
-	return v, err
+// dbUpsert inserts x or, when a primary or unique key already exists, updates
+// params.Update on the existing row (defaults to the inserted fields).
+func (c *Client) dbUpsert(ctx context.Context, tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult {
+	fieldsToUpdate := Fields
+	if params != nil && len(params.Update) > 0 {
+		fieldsToUpdate = params.Update
+	} else if params != nil && len(params.Insert) > 0 {
+		fieldsToUpdate = params.Insert
+	}
+	assignments := make([]string, 0, len(fieldsToUpdate))
+	for _, field := range fieldsToUpdate {
+		qf := GetQualifiedField(field)
+		assignments = append(assignments, qf+" = VALUES("+qf+")")
+	}
+	return c.dbUpsertCore(ctx, tx, x, params, "Upsert", "INSERT INTO", " ON DUPLICATE KEY UPDATE "+strings.Join(assignments, ", "))
 }
 
-func DBTruncate() *QueryResult {
-	res, err := execCore(nil, nil, "TRUNCATE TABLE "+FQTN)
-	return &QueryResult{Result: res, Error: err}
+func (x *Entity) DBUpsert(params *QueryParams) *QueryResult {
+	return defaultClient.dbUpsert(nil, nil, x, params)
 }
-func DBTruncateCtx(ctx context.Context) *QueryResult {
-	res, err := execCore(ctx, nil, "TRUNCATE TABLE "+FQTN)
-	return &QueryResult{Result: res, Error: err}
+func (x *Entity) DBUpsertCtx(ctx context.Context, params *QueryParams) *QueryResult {
+	return defaultClient.dbUpsert(ctx, nil, x, params)
 }
-func DBTruncateTx(tx *sql.Tx) *QueryResult {
-	res, err := execCore(nil, tx, "TRUNCATE TABLE "+FQTN)
-	return &QueryResult{Result: res, Error: err}
+func (x *Entity) DBUpsertTx(tx *sql.Tx, params *QueryParams) *QueryResult {
+	return defaultClient.dbUpsert(nil, tx, x, params)
 }
-func DBTruncateCtxTx(ctx context.Context, tx *sql.Tx) *QueryResult {
-	res, err := execCore(ctx, tx, "TRUNCATE TABLE "+FQTN)
-	return &QueryResult{Result: res, Error: err}
+func (x *Entity) DBUpsertCtxTx(ctx context.Context, tx *sql.Tx, params *QueryParams) *QueryResult {
+	return defaultClient.dbUpsert(ctx, tx, x, params)
+}
+func (c *Client) DBUpsert(x *Entity, params *QueryParams) *QueryResult {
+	return c.dbUpsert(nil, nil, x, params)
+}
+func (c *Client) DBUpsertCtx(ctx context.Context, x *Entity, params *QueryParams) *QueryResult {
+	return c.dbUpsert(ctx, nil, x, params)
+}
+func (c *Client) DBUpsertTx(tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult {
+	return c.dbUpsert(nil, tx, x, params)
+}
+func (c *Client) DBUpsertCtxTx(ctx context.Context, tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult {
+	return c.dbUpsert(ctx, tx, x, params)
 }
 
-func (x *Entity) DBInsert(params *QueryParams) *QueryResult {
-	fieldsToInsert := Fields
-	if params != nil && len(params.Insert) > 0 {
-		fieldsToInsert = params.Insert
-	}
-	q := "INSERT INTO " + FQTN + " (" + strings.Join(GetQualifiedFields(fieldsToInsert), ", ") + ") VALUES (" + strings.Join(GetValuesPlaceholders(fieldsToInsert), ", ") + ")"
-	res, err := execCore(nil, nil, q, x.GetFieldsValues(fieldsToInsert)...)
-	return &QueryResult{Result: res, Error: err}
+func (c *Client) dbInsertIgnore(ctx context.Context, tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult {
+	return c.dbUpsertCore(ctx, tx, x, params, "InsertIgnore", "INSERT IGNORE INTO", "")
 }
 
-func (x *Entity) DBInsertCtx(ctx context.Context, params *QueryParams) *QueryResult {
-	fieldsToInsert := Fields
-	if params != nil && len(params.Insert) > 0 {
-		fieldsToInsert = params.Insert
-	}
-	q := "INSERT INTO " + FQTN + " (" + strings.Join(GetQualifiedFields(fieldsToInsert), ", ") + ") VALUES (" + strings.Join(GetValuesPlaceholders(fieldsToInsert), ", ") + ")"
-	res, err := execCore(ctx, nil, q, x.GetFieldsValues(fieldsToInsert)...)
-	return &QueryResult{Result: res, Error: err}
+func (x *Entity) DBInsertIgnore(params *QueryParams) *QueryResult {
+	return defaultClient.dbInsertIgnore(nil, nil, x, params)
+}
+func (x *Entity) DBInsertIgnoreCtx(ctx context.Context, params *QueryParams) *QueryResult {
+	return defaultClient.dbInsertIgnore(ctx, nil, x, params)
+}
+func (x *Entity) DBInsertIgnoreTx(tx *sql.Tx, params *QueryParams) *QueryResult {
+	return defaultClient.dbInsertIgnore(nil, tx, x, params)
+}
+func (x *Entity) DBInsertIgnoreCtxTx(ctx context.Context, tx *sql.Tx, params *QueryParams) *QueryResult {
+	return defaultClient.dbInsertIgnore(ctx, tx, x, params)
+}
+func (c *Client) DBInsertIgnore(x *Entity, params *QueryParams) *QueryResult {
+	return c.dbInsertIgnore(nil, nil, x, params)
+}
+func (c *Client) DBInsertIgnoreCtx(ctx context.Context, x *Entity, params *QueryParams) *QueryResult {
+	return c.dbInsertIgnore(ctx, nil, x, params)
+}
+func (c *Client) DBInsertIgnoreTx(tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult {
+	return c.dbInsertIgnore(nil, tx, x, params)
+}
+func (c *Client) DBInsertIgnoreCtxTx(ctx context.Context, tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult {
+	return c.dbInsertIgnore(ctx, tx, x, params)
+}
+
+func (c *Client) dbReplace(ctx context.Context, tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult {
+	return c.dbUpsertCore(ctx, tx, x, params, "Replace", "REPLACE INTO", "")
+}
+
+func (x *Entity) DBReplace(params *QueryParams) *QueryResult {
+	return defaultClient.dbReplace(nil, nil, x, params)
+}
+func (x *Entity) DBReplaceCtx(ctx context.Context, params *QueryParams) *QueryResult {
+	return defaultClient.dbReplace(ctx, nil, x, params)
+}
+func (x *Entity) DBReplaceTx(tx *sql.Tx, params *QueryParams) *QueryResult {
+	return defaultClient.dbReplace(nil, tx, x, params)
+}
+func (x *Entity) DBReplaceCtxTx(ctx context.Context, tx *sql.Tx, params *QueryParams) *QueryResult {
+	return defaultClient.dbReplace(ctx, tx, x, params)
+}
+func (c *Client) DBReplace(x *Entity, params *QueryParams) *QueryResult {
+	return c.dbReplace(nil, nil, x, params)
+}
+func (c *Client) DBReplaceCtx(ctx context.Context, x *Entity, params *QueryParams) *QueryResult {
+	return c.dbReplace(ctx, nil, x, params)
+}
+func (c *Client) DBReplaceTx(tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult {
+	return c.dbReplace(nil, tx, x, params)
+}
+func (c *Client) DBReplaceCtxTx(ctx context.Context, tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult {
+	return c.dbReplace(ctx, tx, x, params)
 }
 
-func (x *Entity) DBInsertTx(tx *sql.Tx, params *QueryParams) *QueryResult {
-	fieldsToInsert := Fields
-	if params != nil && len(params.Insert) > 0 {
-		fieldsToInsert = params.Insert
+// buildDeleteWhere defaults the WHERE clause of a delete to the primary key
+// of x when params specify neither Where nor Conditions.
+func buildDeleteWhere(x *Entity, params *QueryParams) (string, []any, error) {
+	whereFields := PrimaryKey
+	var conditions []*Condition
+	if params != nil {
+		conditions = params.Conditions
+		if len(params.Where) > 0 || len(conditions) > 0 {
+			whereFields = params.Where
+		}
 	}
-	q := "INSERT INTO " + FQTN + " (" + strings.Join(GetQualifiedFields(fieldsToInsert), ", ") + ") VALUES (" + strings.Join(GetValuesPlaceholders(fieldsToInsert), ", ") + ")"
-	res, err := execCore(nil, tx, q, x.GetFieldsValues(fieldsToInsert)...)
-	return &QueryResult{Result: res, Error: err}
+	return buildWhere(x, whereFields, conditions)
 }
 
-func (x *Entity) DBInsertCtxTx(ctx context.Context, tx *sql.Tx, params *QueryParams) *QueryResult {
-	fieldsToInsert := Fields
-	if params != nil && len(params.Insert) > 0 {
-		fieldsToInsert = params.Insert
+func (c *Client) dbDelete(ctx context.Context, tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult {
+	if err := runHook(ctx, tx, x, "BeforeDelete"); err != nil {
+		return &QueryResult{Error: err}
 	}
-	q := "INSERT INTO " + FQTN + " (" + strings.Join(GetQualifiedFields(fieldsToInsert), ", ") + ") VALUES (" + strings.Join(GetValuesPlaceholders(fieldsToInsert), ", ") + ")"
-	res, err := execCore(ctx, tx, q, x.GetFieldsValues(fieldsToInsert)...)
-	return &QueryResult{Result: res, Error: err}
+	where, args, err := buildDeleteWhere(x, params)
+	if err != nil {
+		return &QueryResult{Error: err}
+	}
+	q := "DELETE FROM " + FQTN + where
+	res, err := c.primary.execCore(ctx, tx, "Delete", q, args...)
+	return afterHook(ctx, tx, x, "AfterDelete", &QueryResult{Result: res, Error: err})
 }
 
 func (x *Entity) DBDelete(params *QueryParams) *QueryResult {
-	whereFields := Fields
-	if params != nil && len(params.Where) > 0 {
-		whereFields = params.Where
-	}
-	q := "DELETE FROM " + FQTN + " WHERE " + strings.Join(GetQualifiedFields(whereFields), " = ? AND ") + " = ?"
-	res, err := execCore(nil, nil, q, x.GetFieldsValues(whereFields)...)
-	return &QueryResult{Result: res, Error: err}
+	return defaultClient.dbDelete(nil, nil, x, params)
 }
-
 func (x *Entity) DBDeleteCtx(ctx context.Context, params *QueryParams) *QueryResult {
-	whereFields := Fields
-	if params != nil && len(params.Where) > 0 {
-		whereFields = params.Where
-	}
-	q := "DELETE FROM " + FQTN + " WHERE " + strings.Join(GetQualifiedFields(whereFields), " = ? AND ") + " = ?"
-	res, err := execCore(ctx, nil, q, x.GetFieldsValues(whereFields)...)
-	return &QueryResult{Result: res, Error: err}
+	return defaultClient.dbDelete(ctx, nil, x, params)
 }
-
 func (x *Entity) DBDeleteTx(tx *sql.Tx, params *QueryParams) *QueryResult {
-	whereFields := Fields
-	if params != nil && len(params.Where) > 0 {
-		whereFields = params.Where
-	}
-	q := "DELETE FROM " + FQTN + " WHERE " + strings.Join(GetQualifiedFields(whereFields), " = ? AND ") + " = ?"
-	res, err := execCore(nil, tx, q, x.GetFieldsValues(whereFields)...)
-	return &QueryResult{Result: res, Error: err}
+	return defaultClient.dbDelete(nil, tx, x, params)
 }
-
 func (x *Entity) DBDeleteCtxTx(ctx context.Context, tx *sql.Tx, params *QueryParams) *QueryResult {
-	whereFields := Fields
-	if params != nil && len(params.Where) > 0 {
-		whereFields = params.Where
-	}
-	q := "DELETE FROM " + FQTN + " WHERE " + strings.Join(GetQualifiedFields(whereFields), " = ? AND ") + " = ?"
-	res, err := execCore(ctx, tx, q, x.GetFieldsValues(whereFields)...)
-	return &QueryResult{Result: res, Error: err}
+	return defaultClient.dbDelete(ctx, tx, x, params)
+}
+func (c *Client) DBDelete(x *Entity, params *QueryParams) *QueryResult {
+	return c.dbDelete(nil, nil, x, params)
+}
+func (c *Client) DBDeleteCtx(ctx context.Context, x *Entity, params *QueryParams) *QueryResult {
+	return c.dbDelete(ctx, nil, x, params)
+}
+func (c *Client) DBDeleteTx(tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult {
+	return c.dbDelete(nil, tx, x, params)
+}
+func (c *Client) DBDeleteCtxTx(ctx context.Context, tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult {
+	return c.dbDelete(ctx, tx, x, params)
 }
 
-func (x *Entity) DBUpdate(params *QueryParams) *QueryResult {
-	if params == nil || len(params.Update) == 0 || len(params.Where) == 0 {
-		return &QueryResult{Error: errors.New("DBUpdate requires both params.Update and params.Where to be specified")}
+// dbDeleteReturning deletes the matching rows and returns params.Select
+// (defaults to all fields) of every removed row.
+func (c *Client) dbDeleteReturning(ctx context.Context, tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult {
+	if err := runHook(ctx, tx, x, "BeforeDelete"); err != nil {
+		return &QueryResult{Error: err}
 	}
-	q := "UPDATE " + FQTN + " SET " + strings.Join(GetQualifiedPlaceholders(params.Update), ", ") + " WHERE " + strings.Join(GetQualifiedFields(params.Where), " = ? AND ") + " = ?"
-	vals := append(x.GetFieldsValues(params.Update), x.GetFieldsValues(params.Where)...)
-	res, err := execCore(nil, nil, q, vals...)
-	return &QueryResult{Result: res, Error: err}
+	fieldsToReturn := Fields
+	if params != nil && len(params.Select) > 0 {
+		fieldsToReturn = params.Select
+	}
+	where, args, err := buildDeleteWhere(x, params)
+	if err != nil {
+		return &QueryResult{Error: err}
+	}
+	q := "DELETE FROM " + FQTN + where + " RETURNING " + strings.Join(GetQualifiedFields(fieldsToReturn), ", ")
+	entities, err := c.primary.queryCore(ctx, tx, "DeleteReturning", fieldsToReturn, q, args...)
+	track(params, fieldsToReturn, entities...)
+	return afterHook(ctx, tx, x, "AfterDelete", &QueryResult{Entities: entities, Error: err})
 }
 
-func (x *Entity) DBUpdateCtx(ctx context.Context, params *QueryParams) *QueryResult {
-	if params == nil || len(params.Update) == 0 || len(params.Where) == 0 {
-		return &QueryResult{Error: errors.New("DBUpdate requires both params.Update and params.Where to be specified")}
-	}
-	q := "UPDATE " + FQTN + " SET " + strings.Join(GetQualifiedPlaceholders(params.Update), ", ") + " WHERE " + strings.Join(GetQualifiedFields(params.Where), " = ? AND ") + " = ?"
-	vals := append(x.GetFieldsValues(params.Update), x.GetFieldsValues(params.Where)...)
-	res, err := execCore(ctx, nil, q, vals...)
-	return &QueryResult{Result: res, Error: err}
+func (x *Entity) DBDeleteReturning(params *QueryParams) *QueryResult {
+	return defaultClient.dbDeleteReturning(nil, nil, x, params)
+}
+func (x *Entity) DBDeleteReturningCtx(ctx context.Context, params *QueryParams) *QueryResult {
+	return defaultClient.dbDeleteReturning(ctx, nil, x, params)
+}
+func (x *Entity) DBDeleteReturningTx(tx *sql.Tx, params *QueryParams) *QueryResult {
+	return defaultClient.dbDeleteReturning(nil, tx, x, params)
+}
+func (x *Entity) DBDeleteReturningCtxTx(ctx context.Context, tx *sql.Tx, params *QueryParams) *QueryResult {
+	return defaultClient.dbDeleteReturning(ctx, tx, x, params)
+}
+func (c *Client) DBDeleteReturning(x *Entity, params *QueryParams) *QueryResult {
+	return c.dbDeleteReturning(nil, nil, x, params)
+}
+func (c *Client) DBDeleteReturningCtx(ctx context.Context, x *Entity, params *QueryParams) *QueryResult {
+	return c.dbDeleteReturning(ctx, nil, x, params)
+}
+func (c *Client) DBDeleteReturningTx(tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult {
+	return c.dbDeleteReturning(nil, tx, x, params)
+}
+func (c *Client) DBDeleteReturningCtxTx(ctx context.Context, tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult {
+	return c.dbDeleteReturning(ctx, tx, x, params)
 }
 
-func (x *Entity) DBUpdateTx(tx *sql.Tx, params *QueryParams) *QueryResult {
-	if params == nil || len(params.Update) == 0 || len(params.Where) == 0 {
-		return &QueryResult{Error: errors.New("DBUpdate requires both params.Update and params.Where to be specified")}
+func (c *Client) dbUpdate(ctx context.Context, tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult {
+	if err := runHook(ctx, tx, x, "BeforeUpdate"); err != nil {
+		return &QueryResult{Error: err}
 	}
-	q := "UPDATE " + FQTN + " SET " + strings.Join(GetQualifiedPlaceholders(params.Update), ", ") + " WHERE " + strings.Join(GetQualifiedFields(params.Where), " = ? AND ") + " = ?"
-	vals := append(x.GetFieldsValues(params.Update), x.GetFieldsValues(params.Where)...)
-	res, err := execCore(nil, tx, q, vals...)
-	return &QueryResult{Result: res, Error: err}
+	if params == nil || len(params.Update) == 0 || (len(params.Where) == 0 && len(params.Conditions) == 0) {
+		return &QueryResult{Error: errors.New("DBUpdate requires params.Update and either params.Where or params.Conditions to be specified")}
+	}
+	if ValidateOnWrite {
+		if err := x.ValidateFields(params.Update...); err != nil {
+			return &QueryResult{Error: err}
+		}
+	}
+	where, whereArgs, err := buildWhere(x, params.Where, params.Conditions)
+	if err != nil {
+		return &QueryResult{Error: err}
+	}
+	q := "UPDATE " + FQTN + " SET " + strings.Join(GetQualifiedPlaceholders(params.Update), ", ") + where
+	vals := append(x.GetFieldsValues(params.Update), whereArgs...)
+	res, err := c.primary.execCore(ctx, tx, "Update", q, vals...)
+	return afterHook(ctx, tx, x, "AfterUpdate", &QueryResult{Result: res, Error: err})
 }
 
+func (x *Entity) DBUpdate(params *QueryParams) *QueryResult {
+	return defaultClient.dbUpdate(nil, nil, x, params)
+}
+func (x *Entity) DBUpdateCtx(ctx context.Context, params *QueryParams) *QueryResult {
+	return defaultClient.dbUpdate(ctx, nil, x, params)
+}
+func (x *Entity) DBUpdateTx(tx *sql.Tx, params *QueryParams) *QueryResult {
+	return defaultClient.dbUpdate(nil, tx, x, params)
+}
 func (x *Entity) DBUpdateCtxTx(ctx context.Context, tx *sql.Tx, params *QueryParams) *QueryResult {
-	if params == nil || len(params.Update) == 0 || len(params.Where) == 0 {
-		return &QueryResult{Error: errors.New("DBUpdate requires both params.Update and params.Where to be specified")}
-	}
-	q := "UPDATE " + FQTN + " SET " + strings.Join(GetQualifiedPlaceholders(params.Update), ", ") + " WHERE " + strings.Join(GetQualifiedFields(params.Where), " = ? AND ") + " = ?"
-	vals := append(x.GetFieldsValues(params.Update), x.GetFieldsValues(params.Where)...)
-	res, err := execCore(ctx, tx, q, vals...)
-	return &QueryResult{Result: res, Error: err}
+	return defaultClient.dbUpdate(ctx, tx, x, params)
+}
+func (c *Client) DBUpdate(x *Entity, params *QueryParams) *QueryResult {
+	return c.dbUpdate(nil, nil, x, params)
+}
+func (c *Client) DBUpdateCtx(ctx context.Context, x *Entity, params *QueryParams) *QueryResult {
+	return c.dbUpdate(ctx, nil, x, params)
+}
+func (c *Client) DBUpdateTx(tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult {
+	return c.dbUpdate(nil, tx, x, params)
+}
+func (c *Client) DBUpdateCtxTx(ctx context.Context, tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult {
+	return c.dbUpdate(ctx, tx, x, params)
 }
 
-func (x *Entity) DBSelect(params *QueryParams) *QueryResult {
+func (c *Client) dbSelect(ctx context.Context, tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult {
 	fieldsToSelect := Fields
-	if params != nil && len(params.Select) > 0 {
-		fieldsToSelect = params.Select
+	var whereFields []string
+	var conditions []*Condition
+	if params != nil {
+		if len(params.Select) > 0 {
+			fieldsToSelect = params.Select
+		}
+		whereFields = params.Where
+		conditions = params.Conditions
 	}
-	q := "SELECT " + strings.Join(GetQualifiedFields(fieldsToSelect), ", ") + " FROM " + FQTN
-	var args []any
-	if params != nil && len(params.Where) > 0 {
-		q += " WHERE " + strings.Join(GetQualifiedFields(params.Where), " = ? AND ") + " = ?"
-		args = x.GetFieldsValues(params.Where)
+	where, args, err := buildWhere(x, whereFields, conditions)
+	if err != nil {
+		return &QueryResult{Error: err}
 	}
-	entities, err := queryCore(nil, nil, fieldsToSelect, q, args...)
+	tail, tailArgs, err := buildOrderLimit(params)
+	if err != nil {
+		return &QueryResult{Error: err}
+	}
+	q := "SELECT " + strings.Join(GetQualifiedFields(fieldsToSelect), ", ") + " FROM " + FQTN + where + tail
+	entities, err := c.reader(ctx, tx).queryCore(ctx, tx, "Select", fieldsToSelect, q, append(args, tailArgs...)...)
+	track(params, fieldsToSelect, entities...)
 	return &QueryResult{Entities: entities, Error: err}
 }
 
+func (x *Entity) DBSelect(params *QueryParams) *QueryResult {
+	return defaultClient.dbSelect(nil, nil, x, params)
+}
 func (x *Entity) DBSelectCtx(ctx context.Context, params *QueryParams) *QueryResult {
-	fieldsToSelect := Fields
-	if params != nil && len(params.Select) > 0 {
-		fieldsToSelect = params.Select
-	}
-	q := "SELECT " + strings.Join(GetQualifiedFields(fieldsToSelect), ", ") + " FROM " + FQTN
-	var args []any
-	if params != nil && len(params.Where) > 0 {
-		q += " WHERE " + strings.Join(GetQualifiedFields(params.Where), " = ? AND ") + " = ?"
-		args = x.GetFieldsValues(params.Where)
-	}
-	entities, err := queryCore(ctx, nil, fieldsToSelect, q, args...)
-	return &QueryResult{Entities: entities, Error: err}
+	return defaultClient.dbSelect(ctx, nil, x, params)
 }
-
 func (x *Entity) DBSelectTx(tx *sql.Tx, params *QueryParams) *QueryResult {
-	fieldsToSelect := Fields
-	if params != nil && len(params.Select) > 0 {
-		fieldsToSelect = params.Select
-	}
-	q := "SELECT " + strings.Join(GetQualifiedFields(fieldsToSelect), ", ") + " FROM " + FQTN
-	var args []any
-	if params != nil && len(params.Where) > 0 {
-		q += " WHERE " + strings.Join(GetQualifiedFields(params.Where), " = ? AND ") + " = ?"
-		args = x.GetFieldsValues(params.Where)
-	}
-	entities, err := queryCore(nil, tx, fieldsToSelect, q, args...)
-	return &QueryResult{Entities: entities, Error: err}
+	return defaultClient.dbSelect(nil, tx, x, params)
 }
-
 func (x *Entity) DBSelectCtxTx(ctx context.Context, tx *sql.Tx, params *QueryParams) *QueryResult {
+	return defaultClient.dbSelect(ctx, tx, x, params)
+}
+func (c *Client) DBSelect(x *Entity, params *QueryParams) *QueryResult {
+	return c.dbSelect(nil, nil, x, params)
+}
+func (c *Client) DBSelectCtx(ctx context.Context, x *Entity, params *QueryParams) *QueryResult {
+	return c.dbSelect(ctx, nil, x, params)
+}
+func (c *Client) DBSelectTx(tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult {
+	return c.dbSelect(nil, tx, x, params)
+}
+func (c *Client) DBSelectCtxTx(ctx context.Context, tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult {
+	return c.dbSelect(ctx, tx, x, params)
+}
+
+// dbSelectAll reads every row of the table. Only the Select, OrderBy, Limit
+// and Offset parts of params are used.
+func (c *Client) dbSelectAll(ctx context.Context, tx *sql.Tx, params *QueryParams) *QueryResult {
 	fieldsToSelect := Fields
 	if params != nil && len(params.Select) > 0 {
 		fieldsToSelect = params.Select
 	}
-	q := "SELECT " + strings.Join(GetQualifiedFields(fieldsToSelect), ", ") + " FROM " + FQTN
-	var args []any
-	if params != nil && len(params.Where) > 0 {
-		q += " WHERE " + strings.Join(GetQualifiedFields(params.Where), " = ? AND ") + " = ?"
-		args = x.GetFieldsValues(params.Where)
+	tail, args, err := buildOrderLimit(params)
+	if err != nil {
+		return &QueryResult{Error: err}
 	}
-	entities, err := queryCore(ctx, tx, fieldsToSelect, q, args...)
+	q := "SELECT " + strings.Join(GetQualifiedFields(fieldsToSelect), ", ") + " FROM " + FQTN + tail
+	entities, err := c.reader(ctx, tx).queryCore(ctx, tx, "SelectAll", fieldsToSelect, q, args...)
+	track(params, fieldsToSelect, entities...)
 	return &QueryResult{Entities: entities, Error: err}
 }
 
-func DBSelectAll() *QueryResult {
-	q := "SELECT " + strings.Join(GetQualifiedFields(Fields), ", ") + " FROM " + FQTN
-	entities, err := queryCore(nil, nil, Fields, q)
-	return &QueryResult{Entities: entities, Error: err}
+func DBSelectAll(params *QueryParams) *QueryResult {
+	return defaultClient.dbSelectAll(nil, nil, params)
 }
-
-func DBSelectAllCtx(ctx context.Context) *QueryResult {
-	q := "SELECT " + strings.Join(GetQualifiedFields(Fields), ", ") + " FROM " + FQTN
-	entities, err := queryCore(ctx, nil, Fields, q)
-	return &QueryResult{Entities: entities, Error: err}
+func DBSelectAllCtx(ctx context.Context, params *QueryParams) *QueryResult {
+	return defaultClient.dbSelectAll(ctx, nil, params)
 }
-
-func DBSelectAllTx(tx *sql.Tx) *QueryResult {
-	q := "SELECT " + strings.Join(GetQualifiedFields(Fields), ", ") + " FROM " + FQTN
-	entities, err := queryCore(nil, tx, Fields, q)
-	return &QueryResult{Entities: entities, Error: err}
+func DBSelectAllTx(tx *sql.Tx, params *QueryParams) *QueryResult {
+	return defaultClient.dbSelectAll(nil, tx, params)
 }
-
-func DBSelectAllCtxTx(ctx context.Context, tx *sql.Tx) *QueryResult {
-	q := "SELECT " + strings.Join(GetQualifiedFields(Fields), ", ") + " FROM " + FQTN
-	entities, err := queryCore(ctx, tx, Fields, q)
-	return &QueryResult{Entities: entities, Error: err}
+func DBSelectAllCtxTx(ctx context.Context, tx *sql.Tx, params *QueryParams) *QueryResult {
+	return defaultClient.dbSelectAll(ctx, tx, params)
+}
+func (c *Client) DBSelectAll(params *QueryParams) *QueryResult {
+	return c.dbSelectAll(nil, nil, params)
+}
+func (c *Client) DBSelectAllCtx(ctx context.Context, params *QueryParams) *QueryResult {
+	return c.dbSelectAll(ctx, nil, params)
+}
+func (c *Client) DBSelectAllTx(tx *sql.Tx, params *QueryParams) *QueryResult {
+	return c.dbSelectAll(nil, tx, params)
+}
+func (c *Client) DBSelectAllCtxTx(ctx context.Context, tx *sql.Tx, params *QueryParams) *QueryResult {
+	return c.dbSelectAll(ctx, tx, params)
 }
 
-func (x *Entity) DBExists(params *QueryParams) *QueryResult {
+func (c *Client) dbExists(ctx context.Context, tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult {
 	if params == nil {
 		return &QueryResult{Error: errors.New("DBExists requires params to be specified"), Exists: false}
 	}
@@ -1318,35 +3385,16 @@ func (x *Entity) DBExists(params *QueryParams) *QueryResult {
 		fieldsToSelect = Fields
 	}
 	whereFields := params.Where
-	if len(whereFields) == 0 {
+	if len(whereFields) == 0 && len(params.Conditions) == 0 {
 		whereFields = Fields
 	}
-	q := "SELECT " + strings.Join(GetQualifiedFields(fieldsToSelect), ", ") + " FROM " + FQTN + " WHERE " + strings.Join(GetQualifiedFields(whereFields), " = ? AND ") + " = ? LIMIT 1"
-	entities, err := queryCore(nil, nil, fieldsToSelect, q, x.GetFieldsValues(whereFields)...)
+	where, args, err := buildWhere(x, whereFields, params.Conditions)
 	if err != nil {
 		return &QueryResult{Error: err, Exists: false}
 	}
-	if len(entities) == 0 {
-		return &QueryResult{Exists: false}
-	}
-	*x = *entities[0]
-	return &QueryResult{Exists: true}
-}
-
-func (x *Entity) DBExistsCtx(ctx context.Context, params *QueryParams) *QueryResult {
-	if params == nil {
-		return &QueryResult{Error: errors.New("DBExists requires params to be specified"), Exists: false}
-	}
-	fieldsToSelect := params.Select
-	if len(fieldsToSelect) == 0 {
-		fieldsToSelect = Fields
-	}
-	whereFields := params.Where
-	if len(whereFields) == 0 {
-		whereFields = Fields
-	}
-	q := "SELECT " + strings.Join(GetQualifiedFields(fieldsToSelect), ", ") + " FROM " + FQTN + " WHERE " + strings.Join(GetQualifiedFields(whereFields), " = ? AND ") + " = ? LIMIT 1"
-	entities, err := queryCore(ctx, nil, fieldsToSelect, q, x.GetFieldsValues(whereFields)...)
+	q := "SELECT " + strings.Join(GetQualifiedFields(fieldsToSelect), ", ") + " FROM " + FQTN + where + " LIMIT 1"
+	entities, err := c.reader(ctx, tx).queryCore(ctx, tx, "Exists", fieldsToSelect, q, args...)
+	track(params, fieldsToSelect, entities...)
 	if err != nil {
 		return &QueryResult{Error: err, Exists: false}
 	}
@@ -1357,50 +3405,311 @@ func (x *Entity) DBExistsCtx(ctx context.Context, params *QueryParams) *QueryRes
 	return &QueryResult{Exists: true}
 }
 
+func (x *Entity) DBExists(params *QueryParams) *QueryResult {
+	return defaultClient.dbExists(nil, nil, x, params)
+}
+func (x *Entity) DBExistsCtx(ctx context.Context, params *QueryParams) *QueryResult {
+	return defaultClient.dbExists(ctx, nil, x, params)
+}
 func (x *Entity) DBExistsTx(tx *sql.Tx, params *QueryParams) *QueryResult {
-	if params == nil {
-		return &QueryResult{Error: errors.New("DBExists requires params to be specified"), Exists: false}
-	}
-	fieldsToSelect := params.Select
-	if len(fieldsToSelect) == 0 {
-		fieldsToSelect = Fields
+	return defaultClient.dbExists(nil, tx, x, params)
+}
+func (x *Entity) DBExistsCtxTx(ctx context.Context, tx *sql.Tx, params *QueryParams) *QueryResult {
+	return defaultClient.dbExists(ctx, tx, x, params)
+}
+func (c *Client) DBExists(x *Entity, params *QueryParams) *QueryResult {
+	return c.dbExists(nil, nil, x, params)
+}
+func (c *Client) DBExistsCtx(ctx context.Context, x *Entity, params *QueryParams) *QueryResult {
+	return c.dbExists(ctx, nil, x, params)
+}
+func (c *Client) DBExistsTx(tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult {
+	return c.dbExists(nil, tx, x, params)
+}
+func (c *Client) DBExistsCtxTx(ctx context.Context, tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult {
+	return c.dbExists(ctx, tx, x, params)
+}
+
+// dbSelectPage reads one page of at most params.Limit rows ordered by
+// params.OrderBy, which must include a unique key. Pass the NextCursor of the
+// previous page to continue; an empty NextCursor means there are no more rows.
+func (c *Client) dbSelectPage(ctx context.Context, tx *sql.Tx, x *Entity, params *QueryParams, cursor string) *QueryResult {
+	if params == nil || len(params.OrderBy) == 0 || params.Limit <= 0 {
+		return &QueryResult{Error: errors.New("DBSelectPage requires params.OrderBy and a positive params.Limit to be specified")}
+	}
+	if params.Offset != 0 {
+		return &QueryResult{Error: errors.New("DBSelectPage does not support params.Offset")}
+	}
+	if err := checkPageOrder(params.OrderBy); err != nil {
+		return &QueryResult{Error: err}
+	}
+	if len(params.Select) > 0 {
+		for _, o := range params.OrderBy {
+			found := false
+			for _, field := range params.Select {
+				if field == o.Field {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return &QueryResult{Error: errors.New("DBSelectPage requires params.Select to include OrderBy field: " + o.Field)}
+			}
+		}
 	}
-	whereFields := params.Where
-	if len(whereFields) == 0 {
-		whereFields = Fields
+	page := *params
+	page.Limit = params.Limit + 1
+	if cursor != "" {
+		values, err := decodeCursor(params.OrderBy, cursor)
+		if err != nil {
+			return &QueryResult{Error: err}
+		}
+		page.Conditions = append(append(make([]*Condition, 0, len(params.Conditions)+1), params.Conditions...), seekCondition(params.OrderBy, values))
 	}
-	q := "SELECT " + strings.Join(GetQualifiedFields(fieldsToSelect), ", ") + " FROM " + FQTN + " WHERE " + strings.Join(GetQualifiedFields(whereFields), " = ? AND ") + " = ? LIMIT 1"
-	entities, err := queryCore(nil, tx, fieldsToSelect, q, x.GetFieldsValues(whereFields)...)
-	if err != nil {
-		return &QueryResult{Error: err, Exists: false}
+	result := c.dbSelect(ctx, tx, x, &page)
+	if result.Error != nil || len(result.Entities) <= params.Limit {
+		return result
 	}
-	if len(entities) == 0 {
-		return &QueryResult{Exists: false}
+	result.Entities = result.Entities[:params.Limit]
+	result.NextCursor, result.Error = encodeCursor(params.OrderBy, result.Entities[params.Limit-1])
+	return result
+}
+
+func (x *Entity) DBSelectPage(params *QueryParams, cursor string) *QueryResult {
+	return defaultClient.dbSelectPage(nil, nil, x, params, cursor)
+}
+func (x *Entity) DBSelectPageCtx(ctx context.Context, params *QueryParams, cursor string) *QueryResult {
+	return defaultClient.dbSelectPage(ctx, nil, x, params, cursor)
+}
+func (x *Entity) DBSelectPageTx(tx *sql.Tx, params *QueryParams, cursor string) *QueryResult {
+	return defaultClient.dbSelectPage(nil, tx, x, params, cursor)
+}
+func (x *Entity) DBSelectPageCtxTx(ctx context.Context, tx *sql.Tx, params *QueryParams, cursor string) *QueryResult {
+	return defaultClient.dbSelectPage(ctx, tx, x, params, cursor)
+}
+func (c *Client) DBSelectPage(x *Entity, params *QueryParams, cursor string) *QueryResult {
+	return c.dbSelectPage(nil, nil, x, params, cursor)
+}
+func (c *Client) DBSelectPageCtx(ctx context.Context, x *Entity, params *QueryParams, cursor string) *QueryResult {
+	return c.dbSelectPage(ctx, nil, x, params, cursor)
+}
+func (c *Client) DBSelectPageTx(tx *sql.Tx, x *Entity, params *QueryParams, cursor string) *QueryResult {
+	return c.dbSelectPage(nil, tx, x, params, cursor)
+}
+func (c *Client) DBSelectPageCtxTx(ctx context.Context, tx *sql.Tx, x *Entity, params *QueryParams, cursor string) *QueryResult {
+	return c.dbSelectPage(ctx, tx, x, params, cursor)
+}
+
+func (c *Client) dbGetByKey(ctx context.Context, tx *sql.Tx, key []string, values ...any) *QueryResult {
+	q := "SELECT " + strings.Join(GetQualifiedFields(Fields), ", ") + " FROM " + FQTN + " WHERE " + strings.Join(GetQualifiedConditions(key), " AND ")
+	entity, err := c.reader(ctx, tx).queryOneCore(ctx, tx, "GetByKey", Fields, q, values...)
+	return &QueryResult{Entity: entity, Exists: entity != nil, Error: err}
+}
+
+func (c *Client) dbGetByPK(ctx context.Context, tx *sql.Tx, id int32) *QueryResult {
+	return c.dbGetByKey(ctx, tx, PrimaryKey, id)
+}
+
+func DBGetByPK(id int32) *QueryResult {
+	return defaultClient.dbGetByPK(nil, nil, id)
+}
+func DBGetByPKCtx(ctx context.Context, id int32) *QueryResult {
+	return defaultClient.dbGetByPK(ctx, nil, id)
+}
+func DBGetByPKTx(tx *sql.Tx, id int32) *QueryResult {
+	return defaultClient.dbGetByPK(nil, tx, id)
+}
+func DBGetByPKCtxTx(ctx context.Context, tx *sql.Tx, id int32) *QueryResult {
+	return defaultClient.dbGetByPK(ctx, tx, id)
+}
+func (c *Client) DBGetByPK(id int32) *QueryResult {
+	return c.dbGetByPK(nil, nil, id)
+}
+func (c *Client) DBGetByPKCtx(ctx context.Context, id int32) *QueryResult {
+	return c.dbGetByPK(ctx, nil, id)
+}
+func (c *Client) DBGetByPKTx(tx *sql.Tx, id int32) *QueryResult {
+	return c.dbGetByPK(nil, tx, id)
+}
+func (c *Client) DBGetByPKCtxTx(ctx context.Context, tx *sql.Tx, id int32) *QueryResult {
+	return c.dbGetByPK(ctx, tx, id)
+}
+
+func (c *Client) dbGetByUuidField(ctx context.Context, tx *sql.Tx, uuidField string) *QueryResult {
+	return c.dbGetByKey(ctx, tx, []string{FieldUuidField}, uuidField)
+}
+
+func DBGetByUuidField(uuidField string) *QueryResult {
+	return defaultClient.dbGetByUuidField(nil, nil, uuidField)
+}
+func DBGetByUuidFieldCtx(ctx context.Context, uuidField string) *QueryResult {
+	return defaultClient.dbGetByUuidField(ctx, nil, uuidField)
+}
+func DBGetByUuidFieldTx(tx *sql.Tx, uuidField string) *QueryResult {
+	return defaultClient.dbGetByUuidField(nil, tx, uuidField)
+}
+func DBGetByUuidFieldCtxTx(ctx context.Context, tx *sql.Tx, uuidField string) *QueryResult {
+	return defaultClient.dbGetByUuidField(ctx, tx, uuidField)
+}
+func (c *Client) DBGetByUuidField(uuidField string) *QueryResult {
+	return c.dbGetByUuidField(nil, nil, uuidField)
+}
+func (c *Client) DBGetByUuidFieldCtx(ctx context.Context, uuidField string) *QueryResult {
+	return c.dbGetByUuidField(ctx, nil, uuidField)
+}
+func (c *Client) DBGetByUuidFieldTx(tx *sql.Tx, uuidField string) *QueryResult {
+	return c.dbGetByUuidField(nil, tx, uuidField)
+}
+func (c *Client) DBGetByUuidFieldCtxTx(ctx context.Context, tx *sql.Tx, uuidField string) *QueryResult {
+	return c.dbGetByUuidField(ctx, tx, uuidField)
+}
+
+// dbUpdateByPK updates params.Update (defaults to every non-key field) of the
+// row identified by the primary key of x.
+func (c *Client) dbUpdateByPK(ctx context.Context, tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult {
+	p := &QueryParams{Update: nonPrimaryKeyFields, Where: PrimaryKey}
+	if params != nil {
+		if len(params.Update) > 0 {
+			p.Update = params.Update
+		}
+		p.Conditions = params.Conditions
 	}
-	*x = *entities[0]
-	return &QueryResult{Exists: true}
+	return c.dbUpdate(ctx, tx, x, p)
 }
 
-func (x *Entity) DBExistsCtxTx(ctx context.Context, tx *sql.Tx, params *QueryParams) *QueryResult {
-	if params == nil {
-		return &QueryResult{Error: errors.New("DBExists requires params to be specified"), Exists: false}
+func (x *Entity) DBUpdateByPK(params *QueryParams) *QueryResult {
+	return defaultClient.dbUpdateByPK(nil, nil, x, params)
+}
+func (x *Entity) DBUpdateByPKCtx(ctx context.Context, params *QueryParams) *QueryResult {
+	return defaultClient.dbUpdateByPK(ctx, nil, x, params)
+}
+func (x *Entity) DBUpdateByPKTx(tx *sql.Tx, params *QueryParams) *QueryResult {
+	return defaultClient.dbUpdateByPK(nil, tx, x, params)
+}
+func (x *Entity) DBUpdateByPKCtxTx(ctx context.Context, tx *sql.Tx, params *QueryParams) *QueryResult {
+	return defaultClient.dbUpdateByPK(ctx, tx, x, params)
+}
+func (c *Client) DBUpdateByPK(x *Entity, params *QueryParams) *QueryResult {
+	return c.dbUpdateByPK(nil, nil, x, params)
+}
+func (c *Client) DBUpdateByPKCtx(ctx context.Context, x *Entity, params *QueryParams) *QueryResult {
+	return c.dbUpdateByPK(ctx, nil, x, params)
+}
+func (c *Client) DBUpdateByPKTx(tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult {
+	return c.dbUpdateByPK(nil, tx, x, params)
+}
+func (c *Client) DBUpdateByPKCtxTx(ctx context.Context, tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult {
+	return c.dbUpdateByPK(ctx, tx, x, params)
+}
+
+// dbUpdateChanged updates only the fields of x that changed since it was
+// loaded, locating the row by the primary key it was loaded with, and sends
+// nothing when no field changed. params may add Conditions.
+func (c *Client) dbUpdateChanged(ctx context.Context, tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult {
+	if x.loaded == nil {
+		return &QueryResult{Error: errors.New("DBUpdateChanged requires an entity loaded from the database")}
 	}
-	fieldsToSelect := params.Select
-	if len(fieldsToSelect) == 0 {
-		fieldsToSelect = Fields
+	if !x.loaded.hasPrimaryKey() {
+		return &QueryResult{Error: errors.New("DBUpdateChanged requires the primary key to have been loaded")}
 	}
-	whereFields := params.Where
-	if len(whereFields) == 0 {
-		whereFields = Fields
+	changed := x.ChangedFields()
+	if len(changed) == 0 {
+		return &QueryResult{Result: driver.RowsAffected(0)}
 	}
-	q := "SELECT " + strings.Join(GetQualifiedFields(fieldsToSelect), ", ") + " FROM " + FQTN + " WHERE " + strings.Join(GetQualifiedFields(whereFields), " = ? AND ") + " = ? LIMIT 1"
-	entities, err := queryCore(ctx, tx, fieldsToSelect, q, x.GetFieldsValues(whereFields)...)
-	if err != nil {
-		return &QueryResult{Error: err, Exists: false}
+	p := &QueryParams{Update: changed}
+	for _, field := range PrimaryKey {
+		p.Conditions = append(p.Conditions, Eq(field, x.loaded.entity.GetFieldValue(field)))
 	}
-	if len(entities) == 0 {
-		return &QueryResult{Exists: false}
+	if params != nil {
+		p.Conditions = append(p.Conditions, params.Conditions...)
 	}
-	*x = *entities[0]
-	return &QueryResult{Exists: true}
+	result := c.dbUpdate(ctx, tx, x, p)
+	if result.Error == nil {
+		x.takeSnapshot(x.loaded.fields)
+	}
+	return result
+}
+
+func (x *Entity) DBUpdateChanged(params *QueryParams) *QueryResult {
+	return defaultClient.dbUpdateChanged(nil, nil, x, params)
+}
+func (x *Entity) DBUpdateChangedCtx(ctx context.Context, params *QueryParams) *QueryResult {
+	return defaultClient.dbUpdateChanged(ctx, nil, x, params)
+}
+func (x *Entity) DBUpdateChangedTx(tx *sql.Tx, params *QueryParams) *QueryResult {
+	return defaultClient.dbUpdateChanged(nil, tx, x, params)
+}
+func (x *Entity) DBUpdateChangedCtxTx(ctx context.Context, tx *sql.Tx, params *QueryParams) *QueryResult {
+	return defaultClient.dbUpdateChanged(ctx, tx, x, params)
+}
+func (c *Client) DBUpdateChanged(x *Entity, params *QueryParams) *QueryResult {
+	return c.dbUpdateChanged(nil, nil, x, params)
+}
+func (c *Client) DBUpdateChangedCtx(ctx context.Context, x *Entity, params *QueryParams) *QueryResult {
+	return c.dbUpdateChanged(ctx, nil, x, params)
+}
+func (c *Client) DBUpdateChangedTx(tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult {
+	return c.dbUpdateChanged(nil, tx, x, params)
+}
+func (c *Client) DBUpdateChangedCtxTx(ctx context.Context, tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult {
+	return c.dbUpdateChanged(ctx, tx, x, params)
+}
+
+func (c *Client) dbDeleteByPK(ctx context.Context, tx *sql.Tx, x *Entity) *QueryResult {
+	return c.dbDelete(ctx, tx, x, &QueryParams{Where: PrimaryKey})
+}
+
+func (x *Entity) DBDeleteByPK() *QueryResult {
+	return defaultClient.dbDeleteByPK(nil, nil, x)
+}
+func (x *Entity) DBDeleteByPKCtx(ctx context.Context) *QueryResult {
+	return defaultClient.dbDeleteByPK(ctx, nil, x)
+}
+func (x *Entity) DBDeleteByPKTx(tx *sql.Tx) *QueryResult {
+	return defaultClient.dbDeleteByPK(nil, tx, x)
+}
+func (x *Entity) DBDeleteByPKCtxTx(ctx context.Context, tx *sql.Tx) *QueryResult {
+	return defaultClient.dbDeleteByPK(ctx, tx, x)
+}
+func (c *Client) DBDeleteByPK(x *Entity) *QueryResult {
+	return c.dbDeleteByPK(nil, nil, x)
+}
+func (c *Client) DBDeleteByPKCtx(ctx context.Context, x *Entity) *QueryResult {
+	return c.dbDeleteByPK(ctx, nil, x)
+}
+func (c *Client) DBDeleteByPKTx(tx *sql.Tx, x *Entity) *QueryResult {
+	return c.dbDeleteByPK(nil, tx, x)
+}
+func (c *Client) DBDeleteByPKCtxTx(ctx context.Context, tx *sql.Tx, x *Entity) *QueryResult {
+	return c.dbDeleteByPK(ctx, tx, x)
+}
+
+// dbReload refreshes every field of x from the row identified by its primary key.
+func (c *Client) dbReload(ctx context.Context, tx *sql.Tx, x *Entity) *QueryResult {
+	return c.dbExists(ctx, tx, x, &QueryParams{Select: Fields, Where: PrimaryKey, Track: x.loaded != nil})
+}
+
+func (x *Entity) DBReload() *QueryResult {
+	return defaultClient.dbReload(nil, nil, x)
+}
+func (x *Entity) DBReloadCtx(ctx context.Context) *QueryResult {
+	return defaultClient.dbReload(ctx, nil, x)
+}
+func (x *Entity) DBReloadTx(tx *sql.Tx) *QueryResult {
+	return defaultClient.dbReload(nil, tx, x)
+}
+func (x *Entity) DBReloadCtxTx(ctx context.Context, tx *sql.Tx) *QueryResult {
+	return defaultClient.dbReload(ctx, tx, x)
+}
+func (c *Client) DBReload(x *Entity) *QueryResult {
+	return c.dbReload(nil, nil, x)
+}
+func (c *Client) DBReloadCtx(ctx context.Context, x *Entity) *QueryResult {
+	return c.dbReload(ctx, nil, x)
+}
+func (c *Client) DBReloadTx(tx *sql.Tx, x *Entity) *QueryResult {
+	return c.dbReload(nil, tx, x)
+}
+func (c *Client) DBReloadCtxTx(ctx context.Context, tx *sql.Tx, x *Entity) *QueryResult {
+	return c.dbReload(ctx, tx, x)
 }
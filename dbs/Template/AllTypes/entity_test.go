@@ -3,9 +3,11 @@ package AllTypes
 import (
 	"bytes"
 	"database/sql"
+	"errors"
 	"math/rand"
-	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/google/uuid"
@@ -46,29 +48,29 @@ func TestMain(m *testing.M) {
 func TestAllFieldsRoundtrip(t *testing.T) {
 	u := uuid.New().String()
 	e := Entity{
-		Id:              strconv.Itoa(rand.Intn(1_000_000_000)),
-		TinySigned:      "42",
-		TinyUnsigned:    "42",
-		SmallSigned:     "42",
-		SmallUnsigned:   "42",
-		MediumSigned:    "42",
-		MediumUnsigned:  "42",
-		IntSigned:       "42",
-		IntUnsigned:     "42",
-		BigSigned:       "42",
-		BigUnsigned:     "42",
-		FloatField:      "1.23",
-		DoubleField:     "3.14159",
-		RealField:       "2.71828",
+		Id:              rand.Int31n(1_000_000_000),
+		TinySigned:      42,
+		TinyUnsigned:    42,
+		SmallSigned:     42,
+		SmallUnsigned:   42,
+		MediumSigned:    42,
+		MediumUnsigned:  42,
+		IntSigned:       42,
+		IntUnsigned:     42,
+		BigSigned:       42,
+		BigUnsigned:     42,
+		FloatField:      1.23,
+		DoubleField:     3.14159,
+		RealField:       2.71828,
 		DecimalField:    "1234567890.1234567890",
 		DecField:        "12345.12345",
 		NumericField:    "999.9999999",
 		FixedField:      "9999.999999",
-		Bit1:            "\x01",
-		Bit8:            "\x7F",
-		Bit64:           "\x00\x00\x00\x00\x00\x00\x00\x01",
-		BoolField:       "1",
-		BooleanField:    "0",
+		Bit1:            true,
+		Bit8:            0x7F,
+		Bit64:           1,
+		BoolField:       true,
+		BooleanField:    false,
 		CharField:       "char10___",
 		VarcharField:    "varchar test",
 		TextField:       "some long text",
@@ -77,17 +79,17 @@ func TestAllFieldsRoundtrip(t *testing.T) {
 		LongtextField:   "longtext content",
 		EnumField:       "two",
 		SetField:        "a,b",
-		BinaryField:     string(append([]byte{0x01, 0x02, 0x03}, make([]byte, 13)...)),
-		VarbinaryField:  string([]byte{0x04, 0x05, 0x06}),
-		BlobField:       "blob_data",
-		TinyblobField:   "tinyblob",
-		MediumblobField: string(bytes.Repeat([]byte("M"), 128)),
-		LongblobField:   string(bytes.Repeat([]byte("L"), 256)),
-		DateField:       "2025-06-29",
+		BinaryField:     append([]byte{0x01, 0x02, 0x03}, make([]byte, 13)...),
+		VarbinaryField:  []byte{0x04, 0x05, 0x06},
+		BlobField:       []byte("blob_data"),
+		TinyblobField:   []byte("tinyblob"),
+		MediumblobField: bytes.Repeat([]byte("M"), 128),
+		LongblobField:   bytes.Repeat([]byte("L"), 256),
+		DateField:       time.Date(2025, 6, 29, 0, 0, 0, 0, time.UTC),
 		TimeField:       "12:34:56",
-		YearField:       "2025",
-		DatetimeField:   "2025-06-29 12:34:56.000000",
-		TimestampField:  "2025-06-29 12:34:56",
+		YearField:       2025,
+		DatetimeField:   time.Date(2025, 6, 29, 12, 34, 56, 0, time.UTC),
+		TimestampField:  time.Date(2025, 6, 29, 12, 34, 56, 0, time.UTC),
 		UuidField:       u,
 	}
 
@@ -96,7 +98,7 @@ func TestAllFieldsRoundtrip(t *testing.T) {
 		t.Fatal(result.Error)
 	}
 
-	result = DBSelectAll()
+	result = DBSelectAll(nil)
 	if result.Error != nil {
 		t.Fatal(result.Error)
 	}
@@ -167,13 +169,13 @@ func TestAllFieldsRoundtrip(t *testing.T) {
 		t.Errorf("FixedField mismatch: got %v, want %v", found.FixedField, e.FixedField)
 	}
 	if found.Bit1 != e.Bit1 {
-		t.Errorf("Bit1 mismatch: got %v, want %v", []byte(found.Bit1), []byte(e.Bit1))
+		t.Errorf("Bit1 mismatch: got %v, want %v", found.Bit1, e.Bit1)
 	}
 	if found.Bit8 != e.Bit8 {
-		t.Errorf("Bit8 mismatch: got %v, want %v", []byte(found.Bit8), []byte(e.Bit8))
+		t.Errorf("Bit8 mismatch: got %v, want %v", found.Bit8, e.Bit8)
 	}
 	if found.Bit64 != e.Bit64 {
-		t.Errorf("Bit64 mismatch: got %v, want %v", []byte(found.Bit64), []byte(e.Bit64))
+		t.Errorf("Bit64 mismatch: got %v, want %v", found.Bit64, e.Bit64)
 	}
 	if found.BoolField != e.BoolField {
 		t.Errorf("BoolField mismatch: got %v, want %v", found.BoolField, e.BoolField)
@@ -205,25 +207,25 @@ func TestAllFieldsRoundtrip(t *testing.T) {
 	if found.SetField != e.SetField {
 		t.Errorf("SetField mismatch: got %v, want %v", found.SetField, e.SetField)
 	}
-	if found.BinaryField != e.BinaryField {
-		t.Errorf("BinaryField mismatch: got %v, want %v", []byte(found.BinaryField), []byte(e.BinaryField))
+	if !bytes.Equal(found.BinaryField, e.BinaryField) {
+		t.Errorf("BinaryField mismatch: got %v, want %v", found.BinaryField, e.BinaryField)
 	}
-	if found.VarbinaryField != e.VarbinaryField {
-		t.Errorf("VarbinaryField mismatch: got %v, want %v", []byte(found.VarbinaryField), []byte(e.VarbinaryField))
+	if !bytes.Equal(found.VarbinaryField, e.VarbinaryField) {
+		t.Errorf("VarbinaryField mismatch: got %v, want %v", found.VarbinaryField, e.VarbinaryField)
 	}
-	if found.BlobField != e.BlobField {
-		t.Errorf("BlobField mismatch: got %v, want %v", []byte(found.BlobField), []byte(e.BlobField))
+	if !bytes.Equal(found.BlobField, e.BlobField) {
+		t.Errorf("BlobField mismatch: got %v, want %v", found.BlobField, e.BlobField)
 	}
-	if found.TinyblobField != e.TinyblobField {
-		t.Errorf("TinyblobField mismatch: got %v, want %v", []byte(found.TinyblobField), []byte(e.TinyblobField))
+	if !bytes.Equal(found.TinyblobField, e.TinyblobField) {
+		t.Errorf("TinyblobField mismatch: got %v, want %v", found.TinyblobField, e.TinyblobField)
 	}
-	if found.MediumblobField != e.MediumblobField {
-		t.Errorf("MediumblobField mismatch: got %v, want %v", []byte(found.MediumblobField), []byte(e.MediumblobField))
+	if !bytes.Equal(found.MediumblobField, e.MediumblobField) {
+		t.Errorf("MediumblobField mismatch: got %v, want %v", found.MediumblobField, e.MediumblobField)
 	}
-	if found.LongblobField != e.LongblobField {
-		t.Errorf("LongblobField mismatch: got %v, want %v", []byte(found.LongblobField), []byte(e.LongblobField))
+	if !bytes.Equal(found.LongblobField, e.LongblobField) {
+		t.Errorf("LongblobField mismatch: got %v, want %v", found.LongblobField, e.LongblobField)
 	}
-	if found.DateField != e.DateField {
+	if !found.DateField.Equal(e.DateField) {
 		t.Errorf("DateField mismatch: got %v, want %v", found.DateField, e.DateField)
 	}
 	if found.TimeField != e.TimeField {
@@ -232,13 +234,99 @@ func TestAllFieldsRoundtrip(t *testing.T) {
 	if found.YearField != e.YearField {
 		t.Errorf("YearField mismatch: got %v, want %v", found.YearField, e.YearField)
 	}
-	if found.DatetimeField != e.DatetimeField {
+	if !found.DatetimeField.Equal(e.DatetimeField) {
 		t.Errorf("DatetimeField mismatch: got %v, want %v", found.DatetimeField, e.DatetimeField)
 	}
-	if found.TimestampField != e.TimestampField {
+	if !found.TimestampField.Equal(e.TimestampField) {
 		t.Errorf("TimestampField mismatch: got %v, want %v", found.TimestampField, e.TimestampField)
 	}
 	if found.UuidField != e.UuidField {
 		t.Errorf("UuidField mismatch: got %v, want %v", found.UuidField, e.UuidField)
 	}
 }
+
+func TestScannersAcceptDriverRepresentations(t *testing.T) {
+	var ts timeScanner
+	if err := ts.Scan([]byte("2025-06-29 12:34:56.123456")); err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2025, 6, 29, 12, 34, 56, 123456000, time.UTC); !ts.Valid || !ts.Time.Equal(want) {
+		t.Errorf("datetime: got %v, want %v", ts.Time, want)
+	}
+	if err := ts.Scan([]byte("2025-06-29")); err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2025, 6, 29, 0, 0, 0, 0, time.UTC); !ts.Time.Equal(want) {
+		t.Errorf("date: got %v, want %v", ts.Time, want)
+	}
+	if err := ts.Scan(nil); err != nil || ts.Valid {
+		t.Errorf("expected NULL to reset the scanner, got valid=%v err=%v", ts.Valid, err)
+	}
+
+	var bs bitScanner
+	if err := bs.Scan([]byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01, 0x02}); err != nil {
+		t.Fatal(err)
+	}
+	if !bs.Valid || bs.Bits != 0x0102 {
+		t.Errorf("bit: got %#x, want %#x", bs.Bits, 0x0102)
+	}
+	if err := bs.Scan(make([]byte, 9)); err == nil {
+		t.Error("expected an error for values wider than 64 bits")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	e := Entity{
+		DecimalField:    "-1234567890.1234567890",
+		DecField:        "12345.12345",
+		NumericField:    "999.9999999",
+		FixedField:      "9999.999999",
+		CharField:       "char10____",
+		EnumField:       "two",
+		SetField:        "a,c",
+		BinaryField:     make([]byte, 16),
+		VarbinaryField:  []byte{},
+		BlobField:       []byte{},
+		TinyblobField:   []byte{},
+		MediumblobField: []byte{},
+		LongblobField:   []byte{},
+		DateField:       time.Date(2025, 6, 29, 0, 0, 0, 0, time.UTC),
+		TimeField:       "-838:59:59",
+		YearField:       2025,
+		DatetimeField:   time.Date(2025, 6, 29, 12, 34, 56, 0, time.UTC),
+		TimestampField:  time.Date(2025, 6, 29, 12, 34, 56, 0, time.UTC),
+		UuidField:       uuid.New().String(),
+	}
+	if err := e.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	e.MediumUnsigned = 1 << 24
+	e.DecField = "123456.1"
+	e.CharField = "eleven chars"
+	e.EnumField = "four"
+	e.SetField = "a,d"
+	e.BinaryField = make([]byte, 17)
+	e.BlobField = nil
+	e.TimeField = "12:60:00"
+	e.YearField = 1900
+	e.TimestampField = time.Date(2038, 1, 20, 0, 0, 0, 0, time.UTC)
+	e.UuidField = "not-a-uuid"
+	err := e.Validate()
+	var ve *ValidationError
+	if !errors.As(err, &ve) {
+		t.Fatalf("expected a ValidationError, got %v", err)
+	}
+	var got []string
+	for _, fe := range ve.Errors {
+		got = append(got, fe.Field)
+	}
+	want := []string{FieldMediumUnsigned, FieldDecField, FieldCharField, FieldEnumField, FieldSetField, FieldBinaryField, FieldBlobField, FieldTimeField, FieldYearField, FieldTimestampField, FieldUuidField}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("unexpected invalid fields\n got: %v\nwant: %v", got, want)
+	}
+
+	if err := e.ValidateFields(FieldId, FieldVarcharField); err != nil {
+		t.Errorf("expected only the given fields to be checked, got %v", err)
+	}
+}
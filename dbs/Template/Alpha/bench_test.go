@@ -36,7 +36,7 @@ func BenchmarkEntityDBInsertRawSQL(b *testing.B) {
 		b.Fatalf("setup failed: %v", err)
 	}
 
-	stmt, err := db.Prepare("INSERT INTO alpha (`Uuid`, `FirstInsert`, `LastUpdate`, `Animal`, `BigNumber`, `test_field`) VALUES (?, ?, ?, ?, ?, ?)")
+	stmt, err := c.Prepare("INSERT INTO alpha (`Uuid`, `FirstInsert`, `LastUpdate`, `Animal`, `BigNumber`, `test_field`) VALUES (?, ?, ?, ?, ?, ?)")
 	if err != nil {
 		b.Fatal(err)
 	}
@@ -74,8 +74,8 @@ func BenchmarkEntityDBInsertMarGO(b *testing.B) {
 			FirstInsert: "2024-01-01 15:04:05.000000",
 			LastUpdate:  "2024-01-01 15:04:05.000000",
 			Animal:      "Animal",
-			BigNumber:   "1234567890",
-			TestField:   "Test",
+			BigNumber:   NewNull("1234567890"),
+			TestField:   NewNull("Test"),
 		}
 		b.StartTimer()
 
@@ -94,7 +94,7 @@ func BenchmarkEntityDBInsertBun(b *testing.B) {
 		b.Fatalf("setup failed: %v", err)
 	}
 
-	dbx := bun.NewDB(db, mysqldialect.New())
+	dbx := bun.NewDB(c, mysqldialect.New())
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
@@ -121,7 +121,7 @@ func BenchmarkEntityDBInsertGorm(b *testing.B) {
 	}
 
 	gdb, err := gorm.Open(gormysql.New(gormysql.Config{
-		Conn: db, // reuse existing *sql.DB
+		Conn: c, // reuse existing *sql.DB
 	}), &gorm.Config{})
 	if err != nil {
 		b.Fatalf("gorm open failed: %v", err)
@@ -178,13 +178,13 @@ func BenchmarkEntityDBDeleteRawSQL(b *testing.B) {
 		b.Fatalf("setup failed: %v", err)
 	}
 
-	insertStmt, err := db.Prepare("INSERT INTO alpha (`Uuid`, `FirstInsert`, `LastUpdate`, `Animal`, `BigNumber`, `test_field`) VALUES (?, ?, ?, ?, ?, ?)")
+	insertStmt, err := c.Prepare("INSERT INTO alpha (`Uuid`, `FirstInsert`, `LastUpdate`, `Animal`, `BigNumber`, `test_field`) VALUES (?, ?, ?, ?, ?, ?)")
 	if err != nil {
 		b.Fatal(err)
 	}
 	defer insertStmt.Close()
 
-	deleteStmt, err := db.Prepare("DELETE FROM alpha WHERE `Uuid` = ?")
+	deleteStmt, err := c.Prepare("DELETE FROM alpha WHERE `Uuid` = ?")
 	if err != nil {
 		b.Fatal(err)
 	}
@@ -230,8 +230,8 @@ func BenchmarkEntityDBDeleteMarGO(b *testing.B) {
 			FirstInsert: "2024-01-01 15:04:05.000000",
 			LastUpdate:  "2024-01-01 15:04:05.000000",
 			Animal:      "Animal",
-			BigNumber:   "1234567890",
-			TestField:   "Test",
+			BigNumber:   NewNull("1234567890"),
+			TestField:   NewNull("Test"),
 		}
 		result := e.DBInsert(NewQueryParams().WithInsert(Fields...))
 		if err := result.Error; err != nil {
@@ -258,7 +258,7 @@ func BenchmarkEntityDBDeleteGorm(b *testing.B) {
 	}
 
 	gdb, err := gorm.Open(gormysql.New(gormysql.Config{
-		Conn: db,
+		Conn: c,
 	}), &gorm.Config{})
 	if err != nil {
 		b.Fatalf("gorm open failed: %v", err)
@@ -296,7 +296,7 @@ func BenchmarkEntityDBDeleteBun(b *testing.B) {
 		b.Fatalf("setup failed: %v", err)
 	}
 
-	dbx := bun.NewDB(db, mysqldialect.New())
+	dbx := bun.NewDB(c, mysqldialect.New())
 
 	entities := make([]*Alpha, b.N)
 	for i := 0; i < b.N; i++ {
@@ -370,7 +370,7 @@ func BenchmarkEntityDBSelectRawSQL(b *testing.B) {
 		b.Fatalf("setup failed: %v", err)
 	}
 
-	stmtInsert, err := db.Prepare("INSERT INTO alpha (`Uuid`, `FirstInsert`, `LastUpdate`, `Animal`, `BigNumber`, `test_field`) VALUES (?, ?, ?, ?, ?, ?)")
+	stmtInsert, err := c.Prepare("INSERT INTO alpha (`Uuid`, `FirstInsert`, `LastUpdate`, `Animal`, `BigNumber`, `test_field`) VALUES (?, ?, ?, ?, ?, ?)")
 	if err != nil {
 		b.Fatal(err)
 	}
@@ -393,7 +393,7 @@ func BenchmarkEntityDBSelectRawSQL(b *testing.B) {
 		}
 	}
 
-	stmtSelect, err := db.Prepare("SELECT `Uuid`, `FirstInsert`, `LastUpdate`, `Animal`, `BigNumber`, `test_field` FROM alpha WHERE `Uuid` = ?")
+	stmtSelect, err := c.Prepare("SELECT `Uuid`, `FirstInsert`, `LastUpdate`, `Animal`, `BigNumber`, `test_field` FROM alpha WHERE `Uuid` = ?")
 	if err != nil {
 		b.Fatal(err)
 	}
@@ -433,8 +433,8 @@ func BenchmarkEntityDBSelectMarGO(b *testing.B) {
 			FirstInsert: "2024-01-01 15:04:05.000000",
 			LastUpdate:  "2024-01-01 15:04:05.000000",
 			Animal:      "Animal",
-			BigNumber:   "1234567890",
-			TestField:   "Test",
+			BigNumber:   NewNull("1234567890"),
+			TestField:   NewNull("Test"),
 		}
 		result := e.DBInsert(NewQueryParams().WithInsert(Fields...))
 		if err := result.Error; err != nil {
@@ -467,7 +467,7 @@ func BenchmarkEntityDBSelectBun(b *testing.B) {
 		b.Fatalf("setup failed: %v", err)
 	}
 
-	dbx := bun.NewDB(db, mysqldialect.New())
+	dbx := bun.NewDB(c, mysqldialect.New())
 
 	entities := make([]*Alpha, b.N)
 	for i := 0; i < b.N; i++ {
@@ -508,7 +508,7 @@ func BenchmarkEntityDBSelectGorm(b *testing.B) {
 	}
 
 	gdb, err := gorm.Open(gormysql.New(gormysql.Config{
-		Conn: db,
+		Conn: c,
 	}), &gorm.Config{})
 	if err != nil {
 		b.Fatalf("gorm open failed: %v", err)
@@ -539,3 +539,30 @@ func BenchmarkEntityDBSelectGorm(b *testing.B) {
 		}
 	}
 }
+
+func BenchmarkEntityDBInsertManyMarGO(b *testing.B) {
+	b.Skip()
+
+	result := DBTruncate()
+	if err := result.Error; err != nil {
+		b.Fatalf("setup failed: %v", err)
+	}
+
+	entities := make([]*Entity, b.N)
+	for i := 0; i < b.N; i++ {
+		entities[i] = &Entity{
+			Uuid:        uuid.NewString(),
+			FirstInsert: "2024-01-01 15:04:05.000000",
+			LastUpdate:  "2024-01-01 15:04:05.000000",
+			Animal:      "Animal",
+			BigNumber:   NewNull("1234567890"),
+			TestField:   NewNull("Test"),
+		}
+	}
+
+	b.ResetTimer()
+	res := DBInsertMany(entities, NewQueryParams().WithInsert(Fields...).WithBatchSize(1000))
+	if err := res.Error; err != nil {
+		b.Fatalf("insert failed: %v", err)
+	}
+}
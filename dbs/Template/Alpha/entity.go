@@ -7,10 +7,18 @@ package Alpha
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
 	"encoding/base64"
+	"encoding/json"
 	"errors"
+	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+
+	"github.com/rah-0/margo-test/dbs/Template/Shared"
 )
 
 const (
@@ -21,16 +29,34 @@ const (
 	FieldAnimal      = "Animal"
 	FieldBigNumber   = "BigNumber"
 	FieldTestField   = "test_field"
+	// ConcurrencyToken is checked by DBUpdateByPK and DBUpdateChanged.
+	ConcurrencyToken = FieldLastUpdate
+	// CreatedAtField and UpdatedAtField are filled by the generated writes, see Clock.
+	CreatedAtField = FieldFirstInsert
+	UpdatedAtField = FieldLastUpdate
 )
 
 var (
-	Fields    = []string{FieldUuid, FieldFirstInsert, FieldLastUpdate, FieldAnimal, FieldBigNumber, FieldTestField}
-	db        *sql.DB
-	stmtMu    sync.RWMutex
-	stmtCache = make(map[string]*sql.Stmt)
-	queries   = map[string]*NamedQuery{
+	Fields              = []string{FieldUuid, FieldFirstInsert, FieldLastUpdate, FieldAnimal, FieldBigNumber, FieldTestField}
+	PrimaryKey          = []string{FieldUuid}
+	UniqueKeys          = map[string][]string{"PRIMARY": {FieldUuid}}
+	nonPrimaryKeyFields = []string{FieldAnimal, FieldBigNumber, FieldTestField}
+	defaultClient       = &Client{primary: newConn(nil, false)}
+	queries             = map[string]*NamedQuery{
 		"GetAllAnimals": {QueryEncoded: "U0VMRUNUIGBBbmltYWxgLCBgQmlnTnVtYmVyYApGUk9NIGBhbHBoYWA="},
 	}
+	queriesOnce sync.Once
+	queriesErr  error
+	// ReplicaCooldown is how long a replica with a broken connection is skipped.
+	ReplicaCooldown = 30 * time.Second
+	// MaxAllowedPacket should match the server's max_allowed_packet, DBInsertMany keeps each batch below it.
+	MaxAllowedPacket = 16 << 20
+	// ValidateOnWrite makes inserts, upserts and updates run ValidateFields on
+	// the written fields after the Before hooks and fail without sending SQL.
+	ValidateOnWrite = false
+	// Clock supplies CreatedAtField and UpdatedAtField, which are then also
+	// set on the written entity. When nil the server's NOW(6) is written.
+	Clock = time.Now
 )
 
 type NamedQuery struct {
@@ -40,20 +66,98 @@ type NamedQuery struct {
 }
 
 type Entity struct {
-	Uuid        string `json:",omitempty,omitzero"`
-	FirstInsert string `json:",omitempty,omitzero"`
-	LastUpdate  string `json:",omitempty,omitzero"`
-	Animal      string `json:",omitempty,omitzero"`
-	BigNumber   string `json:",omitempty,omitzero"`
-	TestField   string `json:",omitempty,omitzero"`
+	Uuid        string       `json:",omitempty,omitzero"`
+	FirstInsert string       `json:",omitempty,omitzero"`
+	LastUpdate  string       `json:",omitempty,omitzero"`
+	Animal      string       `json:",omitempty,omitzero"`
+	BigNumber   Null[string] `json:",omitempty,omitzero"`
+	TestField   Null[string] `json:",omitempty,omitzero"`
+
+	// loaded is set on tracked entities, see ChangedFields.
+	loaded *entitySnapshot
+}
+
+// Null holds the value of a nullable column, keeping SQL NULL distinct from the zero value.
+type Null[T any] struct {
+	V     T
+	Valid bool
+}
+
+func NewNull[T any](v T) Null[T] {
+	return Null[T]{V: v, Valid: true}
+}
+
+func (n *Null[T]) Scan(value any) error {
+	return (*sql.Null[T])(n).Scan(value)
+}
+
+func (n Null[T]) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	if v, ok := any(n.V).(uint64); ok {
+		return v, nil
+	}
+	return driver.DefaultParameterConverter.ConvertValue(n.V)
+}
+
+func (n Null[T]) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(n.V)
+}
+
+func (n *Null[T]) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		*n = Null[T]{}
+		return nil
+	}
+	if err := json.Unmarshal(b, &n.V); err != nil {
+		return err
+	}
+	n.Valid = true
+	return nil
+}
+
+type InsertManyResult struct {
+	RowsAffected int64
+	Batches      int
+	BatchErrors  []*BatchError
+	Error        error
+}
+
+type BatchError struct {
+	Batch  int
+	Offset int
+	Count  int
+	Err    error
+}
+
+func (e *BatchError) Error() string {
+	return "batch " + strconv.Itoa(e.Batch) + " (entities " + strconv.Itoa(e.Offset) + "-" + strconv.Itoa(e.Offset+e.Count-1) + "): " + e.Err.Error()
+}
+
+func (e *BatchError) Unwrap() error {
+	return e.Err
 }
 
 type QueryParams struct {
-	Select []string
-	Where  []string
-	Insert []string
-	Update []string
-	Params []any
+	Select     []string
+	Where      []string
+	Insert     []string
+	Update     []string
+	Conditions []*Condition
+	OrderBy    []Order
+	Limit      int
+	Offset     int
+	BatchSize  int
+	Params     []any
+	Track      bool
+	// token makes DBUpdate check ConcurrencyToken, set by DBUpdateByPK and DBUpdateChanged.
+	token bool
+	// hooked tells DBUpdate that BeforeUpdate already ran, set by DBUpdateChanged.
+	hooked bool
 }
 
 func NewQueryParams() *QueryParams {
@@ -65,6 +169,12 @@ func (qp *QueryParams) WithSelect(fields ...string) *QueryParams {
 	return qp
 }
 
+// WithTracking makes reads snapshot the entities they return, see ChangedFields.
+func (qp *QueryParams) WithTracking() *QueryParams {
+	qp.Track = true
+	return qp
+}
+
 func (qp *QueryParams) WithWhere(fields ...string) *QueryParams {
 	qp.Where = fields
 	return qp
@@ -80,587 +190,2372 @@ func (qp *QueryParams) WithUpdate(fields ...string) *QueryParams {
 	return qp
 }
 
+func (qp *QueryParams) WithConditions(conditions ...*Condition) *QueryParams {
+	qp.Conditions = conditions
+	return qp
+}
+
+func (qp *QueryParams) WithOrderBy(orders ...Order) *QueryParams {
+	qp.OrderBy = orders
+	return qp
+}
+
+func (qp *QueryParams) WithLimit(limit int) *QueryParams {
+	qp.Limit = limit
+	return qp
+}
+
+func (qp *QueryParams) WithOffset(offset int) *QueryParams {
+	qp.Offset = offset
+	return qp
+}
+
+func (qp *QueryParams) WithBatchSize(size int) *QueryParams {
+	qp.BatchSize = size
+	return qp
+}
+
 func (qp *QueryParams) WithParams(params ...any) *QueryParams {
 	qp.Params = params
 	return qp
 }
 
+// UpsertOutcome reports what an upsert did to the row, derived from MariaDB's
+// affected-rows count: 1 for an insert, 2 for an update or replace and 0 for a
+// row that was left unchanged or ignored. Connections opened with
+// clientFoundRows=true report unchanged rows as updated.
+type UpsertOutcome int
+
+const (
+	UpsertUnknown UpsertOutcome = iota
+	UpsertUnchanged
+	UpsertInserted
+	UpsertUpdated
+)
+
+func (o UpsertOutcome) String() string {
+	switch o {
+	case UpsertUnchanged:
+		return "unchanged"
+	case UpsertInserted:
+		return "inserted"
+	case UpsertUpdated:
+		return "updated"
+	}
+	return "unknown"
+}
+
 type QueryResult struct {
-	Entities []*Entity
-	Entity   *Entity
-	Error    error
-	Result   sql.Result
-	Exists   bool
+	Entities   []*Entity
+	Entity     *Entity
+	Error      error
+	Result     sql.Result
+	Exists     bool
+	NextCursor string
+	Outcome    UpsertOutcome
 }
 
-func SetDB(x *sql.DB) error {
-	db = x
-	for _, q := range queries {
-		b, err := base64.StdEncoding.DecodeString(q.QueryEncoded)
-		if err != nil {
-			return err
-		}
-		q.Query = string(b)
-	}
-	return nil
+// FieldError is a field whose value does not fit its column.
+type FieldError struct {
+	Field  string
+	Reason string
 }
 
-func (x *Entity) GetFieldValue(field string) any {
-	switch field {
-	case FieldUuid:
-		return x.Uuid
-	case FieldFirstInsert:
-		return x.FirstInsert
-	case FieldLastUpdate:
-		return x.LastUpdate
-	case FieldAnimal:
-		return x.Animal
-	case FieldBigNumber:
-		return x.BigNumber
-	case FieldTestField:
-		return x.TestField
-	}
-	return nil
+// ValidationError lists every field that failed Validate.
+type ValidationError struct {
+	Errors []FieldError
 }
 
-func (x *Entity) GetFieldsValues(fieldList []string) []any {
-	values := make([]any, 0, len(fieldList))
-	for _, field := range fieldList {
-		values = append(values, x.GetFieldValue(field))
+func (e *ValidationError) Error() string {
+	parts := make([]string, 0, len(e.Errors))
+	for _, fe := range e.Errors {
+		parts = append(parts, fe.Field+": "+fe.Reason)
 	}
-	return values
+	return "validation failed: " + strings.Join(parts, "; ")
 }
 
-func GetValuePlaceholder(field string) string {
-	switch field {
-	case FieldUuid:
-		return "?"
-	case FieldFirstInsert:
-		return "?"
-	case FieldLastUpdate:
-		return "?"
-	case FieldAnimal:
-		return "?"
-	case FieldBigNumber:
-		return "?"
-	case FieldTestField:
-		return "?"
+// DBError is a MariaDB error classified by its error number, see Shared.DBError.
+type DBError = Shared.DBError
+
+var (
+	ErrDuplicateKey        = Shared.ErrDuplicateKey
+	ErrForeignKeyViolation = Shared.ErrForeignKeyViolation
+	ErrDataTooLong         = Shared.ErrDataTooLong
+	ErrDeadlock            = Shared.ErrDeadlock
+	ErrLockTimeout         = Shared.ErrLockTimeout
+)
+
+// ErrStaleEntity is returned by DBUpdateByPK and DBUpdateChanged when no row matched the
+// concurrency token, because the row was changed or deleted since the entity was read.
+var ErrStaleEntity = errors.New("stale entity: the row was changed or deleted since it was read")
+
+const timestampLayout = "2006-01-02 15:04:05.000000"
+
+// clockTime returns the time of Clock, or of the local clock when Clock is
+// nil, in UTC.
+func clockTime() time.Time {
+	if Clock != nil {
+		return Clock().UTC()
 	}
-	return ""
+	return time.Now().UTC()
 }
 
-func GetValuesPlaceholders(fieldList []string) []string {
-	placeholders := make([]string, 0, len(fieldList))
-	for _, field := range fieldList {
-		placeholders = append(placeholders, GetValuePlaceholder(field))
+func hasField(fields []string, field string) bool {
+	for _, f := range fields {
+		if f == field {
+			return true
+		}
 	}
-	return placeholders
+	return false
 }
 
-func GetQualifiedField(field string) string {
+func (x *Entity) setTimestamp(field, value string) {
 	switch field {
-	case FieldUuid:
-		return FQTN + ".`" + FieldUuid + "`"
 	case FieldFirstInsert:
-		return FQTN + ".`" + FieldFirstInsert + "`"
+		x.FirstInsert = value
 	case FieldLastUpdate:
-		return FQTN + ".`" + FieldLastUpdate + "`"
-	case FieldAnimal:
-		return FQTN + ".`" + FieldAnimal + "`"
-	case FieldBigNumber:
-		return FQTN + ".`" + FieldBigNumber + "`"
-	case FieldTestField:
-		return FQTN + ".`" + FieldTestField + "`"
+		x.LastUpdate = value
 	}
-	return ""
 }
 
-func GetQualifiedFields(fieldList []string) []string {
-	fields := make([]string, 0, len(fieldList))
-	for _, field := range fieldList {
-		fields = append(fields, GetQualifiedField(field))
+// stampInsert returns fields with the managed timestamps appended, and the
+// subset of them bound from the entities. A timestamp is managed unless
+// params.Insert names it. With a Clock the managed timestamps are set on the
+// entities and bound; without one they are written as NOW(6) and cleared on
+// the entities until the row is read back.
+func stampInsert(fields []string, params *QueryParams, entities ...*Entity) (columns, bound []string) {
+	var explicit []string
+	if params != nil {
+		explicit = params.Insert
+	}
+	managed := make([]string, 0, 2)
+	for _, field := range []string{CreatedAtField, UpdatedAtField} {
+		if !hasField(explicit, field) {
+			managed = append(managed, field)
+		}
 	}
-	return fields
+	for _, field := range fields {
+		if !hasField(managed, field) {
+			columns = append(columns, field)
+			bound = append(bound, field)
+		}
+	}
+	var value string
+	if Clock != nil {
+		value = clockTime().Format(timestampLayout)
+	}
+	for _, field := range managed {
+		columns = append(columns, field)
+		if Clock != nil {
+			bound = append(bound, field)
+		}
+		for _, x := range entities {
+			x.setTimestamp(field, value)
+		}
+	}
+	return columns, bound
 }
 
-func GetQualifiedPlaceholder(field string) string {
-	switch field {
-	case FieldUuid:
-		return FQTN + ".`" + FieldUuid + "` = ?"
-	case FieldFirstInsert:
-		return FQTN + ".`" + FieldFirstInsert + "` = ?"
-	case FieldLastUpdate:
-		return FQTN + ".`" + FieldLastUpdate + "` = ?"
-	case FieldAnimal:
-		return FQTN + ".`" + FieldAnimal + "` = ?"
-	case FieldBigNumber:
-		return FQTN + ".`" + FieldBigNumber + "` = ?"
-	case FieldTestField:
-		return FQTN + ".`" + FieldTestField + "` = ?"
+// tokenInserted reports whether an insert of columns left ConcurrencyToken to
+// NOW(6) and bound the primary key, so the token can be read back.
+func tokenInserted(columns, bound []string) bool {
+	if !hasField(columns, ConcurrencyToken) || hasField(bound, ConcurrencyToken) {
+		return false
+	}
+	for _, field := range PrimaryKey {
+		if !hasField(bound, field) {
+			return false
+		}
 	}
-	return ""
+	return true
 }
 
-func GetQualifiedPlaceholders(fieldList []string) []string {
-	placeholders := make([]string, 0, len(fieldList))
-	for _, field := range fieldList {
-		placeholders = append(placeholders, GetQualifiedPlaceholder(field))
+// stampPlaceholders returns the value placeholders of columns, NOW(6) for
+// the ones that are not bound.
+func stampPlaceholders(columns, bound []string) []string {
+	placeholders := make([]string, 0, len(columns))
+	for _, field := range columns {
+		if hasField(bound, field) {
+			placeholders = append(placeholders, GetValuePlaceholder(field))
+		} else {
+			placeholders = append(placeholders, "NOW(6)")
+		}
 	}
 	return placeholders
 }
 
-func getPreparedStmt(query string) (*sql.Stmt, error) {
-	stmtMu.RLock()
-	if stmt, ok := stmtCache[query]; ok {
-		stmtMu.RUnlock()
-		return stmt, nil
-	}
-	stmtMu.RUnlock()
+// updateStamp reports whether an update of the update fields refreshes
+// UpdatedAtField, which it does unless update names it, and returns the value
+// to bind. The value is empty without a Clock, NOW(6) is written then. Either
+// way the value is set on x.
+func updateStamp(x *Entity, update []string) (bool, string) {
+	if hasField(update, UpdatedAtField) {
+		return false, ""
+	}
+	var value string
+	if Clock != nil {
+		value = clockTime().Format(timestampLayout)
+	}
+	x.setTimestamp(UpdatedAtField, value)
+	return true, value
+}
 
-	stmtMu.Lock()
-	defer stmtMu.Unlock()
-	if stmt, ok := stmtCache[query]; ok {
-		return stmt, nil
-	}
-	stmt, err := db.Prepare(query)
-	if err != nil {
-		return nil, err
+// stampSet appends the assignment of UpdatedAtField to the SET list of an
+// UPDATE and its value to vals.
+func stampSet(set []string, vals []any, value string) ([]string, []any) {
+	if value == "" {
+		return append(set, GetQualifiedField(UpdatedAtField)+" = NOW(6)"), vals
 	}
-	stmtCache[query] = stmt
-	return stmt, nil
+	return append(set, GetQualifiedPlaceholder(UpdatedAtField)), append(vals, value)
 }
 
-func scanRow(fields []string, rows *sql.Rows) (*Entity, error) {
-	x := &Entity{}
-	var (
-		ptrUuid        *string
-		ptrFirstInsert *string
-		ptrLastUpdate  *string
-		ptrAnimal      *string
-		ptrBigNumber   *string
-		ptrTestField   *string
-		scanTargets    []any
-	)
-
+// upsertUpdateFields keeps CreatedAtField and UpdatedAtField out of the fields
+// an upsert updates on a duplicate key unless params.Update names them. stamp
+// reports whether UpdatedAtField is to be refreshed when another field changes.
+func upsertUpdateFields(fields []string, params *QueryParams) (out []string, stamp bool) {
+	var explicit []string
+	if params != nil {
+		explicit = params.Update
+	}
+	out = make([]string, 0, len(fields))
 	for _, field := range fields {
-		switch field {
-		case FieldUuid:
-			scanTargets = append(scanTargets, &ptrUuid)
-		case FieldFirstInsert:
-			scanTargets = append(scanTargets, &ptrFirstInsert)
-		case FieldLastUpdate:
-			scanTargets = append(scanTargets, &ptrLastUpdate)
-		case FieldAnimal:
-			scanTargets = append(scanTargets, &ptrAnimal)
-		case FieldBigNumber:
-			scanTargets = append(scanTargets, &ptrBigNumber)
-		case FieldTestField:
-			scanTargets = append(scanTargets, &ptrTestField)
+		if (field != CreatedAtField && field != UpdatedAtField) || hasField(explicit, field) {
+			out = append(out, field)
 		}
 	}
-
-	err := rows.Scan(scanTargets...)
-	if err != nil {
-		return nil, err
-	}
-
-	if ptrUuid != nil {
-		x.Uuid = *ptrUuid
-	} else {
-		x.Uuid = ""
-	}
-	if ptrFirstInsert != nil {
-		x.FirstInsert = *ptrFirstInsert
-	} else {
-		x.FirstInsert = ""
-	}
-	if ptrLastUpdate != nil {
-		x.LastUpdate = *ptrLastUpdate
-	} else {
-		x.LastUpdate = ""
-	}
-	if ptrAnimal != nil {
-		x.Animal = *ptrAnimal
-	} else {
-		x.Animal = ""
-	}
-	if ptrBigNumber != nil {
-		x.BigNumber = *ptrBigNumber
-	} else {
-		x.BigNumber = ""
-	}
-	if ptrTestField != nil {
-		x.TestField = *ptrTestField
-	} else {
-		x.TestField = ""
-	}
-	return x, nil
+	return out, !hasField(out, UpdatedAtField)
 }
 
-func readRows(fields []string, rows *sql.Rows) (_ []*Entity, err error) {
-	defer func() {
-		if cerr := rows.Close(); cerr != nil && err == nil {
-			err = cerr
+// upsertAssignments returns the ON DUPLICATE KEY UPDATE assignments of fields.
+// With stamp, UpdatedAtField keeps its value unless one of fields changes. It
+// is assigned first because later assignments see the updated columns.
+func upsertAssignments(fields []string, stamp bool) []string {
+	out := make([]string, 0, len(fields)+1)
+	if stamp {
+		same := make([]string, 0, len(fields))
+		for _, field := range fields {
+			qf := GetQualifiedField(field)
+			same = append(same, qf+" <=> VALUES("+qf+")")
 		}
-	}()
-	var results []*Entity
-	for rows.Next() {
-		x, err := scanRow(fields, rows)
-		if err != nil {
-			return results, err
+		qf := GetQualifiedField(UpdatedAtField)
+		if len(same) == 0 {
+			out = append(out, qf+" = "+qf)
+		} else {
+			out = append(out, qf+" = IF("+strings.Join(same, " AND ")+", "+qf+", VALUES("+qf+"))")
 		}
-		results = append(results, x)
 	}
-	if rerr := rows.Err(); rerr != nil {
-		return results, rerr
+	for _, field := range fields {
+		qf := GetQualifiedField(field)
+		out = append(out, qf+" = VALUES("+qf+")")
 	}
-	return results, nil
+	return out
 }
 
-func bindStmtCtxTx(base *sql.Stmt, ctx context.Context, tx *sql.Tx) (*sql.Stmt, bool) {
-	if tx == nil {
-		return base, false
+// tokenUpdate returns the token x was read with and the value replacing it,
+// both empty when x carries no token or update sets the token explicitly.
+// The value is empty when Clock is nil and the database writes NOW(6).
+func tokenUpdate(x *Entity, update []string) (string, string) {
+	if x.LastUpdate == "" || hasField(update, ConcurrencyToken) {
+		return "", ""
 	}
-	if ctx != nil {
-		return tx.StmtContext(ctx, base), true
+	if Clock == nil {
+		return x.LastUpdate, ""
 	}
-	return tx.Stmt(base), true
+	return x.LastUpdate, nextToken(x.LastUpdate)
 }
 
-func execCore(ctx context.Context, tx *sql.Tx, query string, args ...any) (res sql.Result, err error) {
-	stmt, err := getPreparedStmt(query)
-	if err != nil {
-		return nil, err
-	}
-	s, needClose := bindStmtCtxTx(stmt, ctx, tx)
-	if needClose {
-		defer func() {
-			if cerr := s.Close(); err == nil && cerr != nil {
-				err = cerr
-			}
-		}()
-	}
-	if ctx != nil {
-		return s.ExecContext(ctx, args...)
-	}
-	return s.Exec(args...)
+// nextToken returns the current time as a token that differs from old.
+func nextToken(old string) string {
+	now := clockTime()
+	next := now.Format(timestampLayout)
+	if next == old {
+		next = now.Add(time.Microsecond).Format(timestampLayout)
+	}
+	return next
 }
 
-func queryCore(ctx context.Context, tx *sql.Tx, fields []string, query string, args ...any) (out []*Entity, err error) {
-	stmt, err := getPreparedStmt(query)
+// BeforeInserter and the other hook interfaces below are checked for by the
+// generated operations, implement them on *Entity in a hand-written file of
+// this package. A hook gets the operation's context, context.Background()
+// when it has none, and transaction. An error from a Before hook aborts the
+// operation, one from an After hook is returned after the statement has run.
+type BeforeInserter interface {
+	BeforeInsert(ctx context.Context, tx *sql.Tx) error
+}
+
+type AfterInserter interface {
+	AfterInsert(ctx context.Context, tx *sql.Tx) error
+}
+
+type BeforeUpdater interface {
+	BeforeUpdate(ctx context.Context, tx *sql.Tx) error
+}
+
+type AfterUpdater interface {
+	AfterUpdate(ctx context.Context, tx *sql.Tx) error
+}
+
+type BeforeDeleter interface {
+	BeforeDelete(ctx context.Context, tx *sql.Tx) error
+}
+
+type AfterDeleter interface {
+	AfterDelete(ctx context.Context, tx *sql.Tx) error
+}
+
+// AfterLoader is called for every entity read from the database, including
+// the rows returned by RETURNING and by named queries.
+type AfterLoader interface {
+	AfterLoad(ctx context.Context, tx *sql.Tx) error
+}
+
+func runHook(ctx context.Context, tx *sql.Tx, x *Entity, hook string) error {
+	if x == nil {
+		return nil
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	switch hook {
+	case "BeforeInsert":
+		if h, ok := any(x).(BeforeInserter); ok {
+			return h.BeforeInsert(ctx, tx)
+		}
+	case "AfterInsert":
+		if h, ok := any(x).(AfterInserter); ok {
+			return h.AfterInsert(ctx, tx)
+		}
+	case "BeforeUpdate":
+		if h, ok := any(x).(BeforeUpdater); ok {
+			return h.BeforeUpdate(ctx, tx)
+		}
+	case "AfterUpdate":
+		if h, ok := any(x).(AfterUpdater); ok {
+			return h.AfterUpdate(ctx, tx)
+		}
+	case "BeforeDelete":
+		if h, ok := any(x).(BeforeDeleter); ok {
+			return h.BeforeDelete(ctx, tx)
+		}
+	case "AfterDelete":
+		if h, ok := any(x).(AfterDeleter); ok {
+			return h.AfterDelete(ctx, tx)
+		}
+	case "AfterLoad":
+		if h, ok := any(x).(AfterLoader); ok {
+			return h.AfterLoad(ctx, tx)
+		}
+	}
+	return nil
+}
+
+// afterHook runs hook on x once result has succeeded.
+func afterHook(ctx context.Context, tx *sql.Tx, x *Entity, hook string, result *QueryResult) *QueryResult {
+	if result.Error == nil {
+		result.Error = runHook(ctx, tx, x, hook)
+	}
+	return result
+}
+
+func afterLoad(ctx context.Context, tx *sql.Tx, entities ...*Entity) error {
+	for _, x := range entities {
+		if err := runHook(ctx, tx, x, "AfterLoad"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// QueryEvent describes one statement run by a Client.
+type QueryEvent struct {
+	Op           string // "exec" or "query"
+	Table        string // FQTN
+	Name         string // generated operation such as "Insert" or "SelectAll", or the named query
+	Query        string
+	Args         int
+	Replica      bool
+	Duration     time.Duration
+	RowsAffected int64 // rows changed by an exec or returned by a query, -1 when unknown
+	Err          error
+}
+
+// QueryHook observes every statement a Client runs, for logging, metrics or
+// tracing. BeforeQuery may return a derived context, for example one carrying
+// a span; it is used for the statement and passed on to AfterQuery.
+type QueryHook interface {
+	BeforeQuery(ctx context.Context, ev *QueryEvent) context.Context
+	AfterQuery(ctx context.Context, ev *QueryEvent)
+}
+
+// conn is one *sql.DB together with the statements prepared on it.
+type conn struct {
+	db        *sql.DB
+	replica   bool
+	downUntil atomic.Int64
+	stmtMu    sync.RWMutex
+	stmtCache map[string]*sql.Stmt
+	hook      atomic.Pointer[QueryHook]
+}
+
+func newConn(x *sql.DB, replica bool) *conn {
+	return &conn{db: x, replica: replica, stmtCache: make(map[string]*sql.Stmt)}
+}
+
+// report takes a replica out of rotation for ReplicaCooldown when err shows
+// that its connection is broken.
+func (n *conn) report(err error) {
+	if n.replica && Shared.IsConnError(err) {
+		n.downUntil.Store(time.Now().Add(ReplicaCooldown).UnixNano())
+	}
+}
+
+// trace hands a statement to the hook, it returns the context to run the
+// statement with and the func that completes the event.
+func (n *conn) trace(ctx context.Context, op, name, query string, args int) (context.Context, func(rows int64, err error)) {
+	hp := n.hook.Load()
+	if hp == nil {
+		return ctx, noTrace
+	}
+	hook := *hp
+	ev := &QueryEvent{Op: op, Table: FQTN, Name: name, Query: query, Args: args, Replica: n.replica, RowsAffected: -1}
+	hookCtx := ctx
+	if hookCtx == nil {
+		hookCtx = context.Background()
+	}
+	hookCtx = hook.BeforeQuery(hookCtx, ev)
+	if ctx != nil {
+		ctx = hookCtx
+	}
+	start := time.Now()
+	return ctx, func(rows int64, err error) {
+		ev.Duration = time.Since(start)
+		ev.RowsAffected = rows
+		ev.Err = err
+		hook.AfterQuery(hookCtx, ev)
+	}
+}
+
+func noTrace(int64, error) {}
+
+func resultRows(res sql.Result) int64 {
+	if res == nil {
+		return -1
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return -1
+	}
+	return n
+}
+
+// Client owns a primary *sql.DB, optional read replicas and the statements
+// prepared on each of them, several clients can coexist in one process.
+// Writes and everything inside a *sql.Tx go to the primary, reads are spread
+// over the healthy replicas and fall back to the primary when none is left.
+type Client struct {
+	primary  *conn
+	replicas []*conn
+	next     atomic.Uint64
+}
+
+func NewClient(primary *sql.DB, replicas ...*sql.DB) (*Client, error) {
+	if err := decodeQueries(); err != nil {
+		return nil, err
+	}
+	c := &Client{primary: newConn(primary, false)}
+	for _, r := range replicas {
+		c.replicas = append(c.replicas, newConn(r, true))
+	}
+	return c, nil
+}
+
+func (c *Client) DB() *sql.DB {
+	return c.primary.db
+}
+
+// CheckReplicas pings every replica, taking the unreachable ones out of
+// rotation and putting the recovered ones back.
+func (c *Client) CheckReplicas(ctx context.Context) {
+	for _, n := range c.replicas {
+		if err := n.db.PingContext(ctx); err != nil {
+			n.downUntil.Store(time.Now().Add(ReplicaCooldown).UnixNano())
+		} else {
+			n.downUntil.Store(0)
+		}
+	}
+}
+
+// SetHook makes h observe every statement the client runs, nil removes it.
+// It is safe to call while the client is in use.
+func (c *Client) SetHook(h QueryHook) {
+	var hp *QueryHook
+	if h != nil {
+		hp = &h
+	}
+	c.primary.hook.Store(hp)
+	for _, n := range c.replicas {
+		n.hook.Store(hp)
+	}
+}
+
+// Querier is implemented by *Client and by the in-memory *Fake, so code that
+// depends on it can be tested without a database.
+type Querier interface {
+	DBTruncate() *QueryResult
+	DBTruncateCtx(ctx context.Context) *QueryResult
+	DBTruncateTx(tx *sql.Tx) *QueryResult
+	DBTruncateCtxTx(ctx context.Context, tx *sql.Tx) *QueryResult
+
+	DBInsert(x *Entity, params *QueryParams) *QueryResult
+	DBInsertCtx(ctx context.Context, x *Entity, params *QueryParams) *QueryResult
+	DBInsertTx(tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult
+	DBInsertCtxTx(ctx context.Context, tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult
+
+	DBInsertReturning(x *Entity, params *QueryParams) *QueryResult
+	DBInsertReturningCtx(ctx context.Context, x *Entity, params *QueryParams) *QueryResult
+	DBInsertReturningTx(tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult
+	DBInsertReturningCtxTx(ctx context.Context, tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult
+
+	DBInsertMany(entities []*Entity, params *QueryParams) *InsertManyResult
+	DBInsertManyCtx(ctx context.Context, entities []*Entity, params *QueryParams) *InsertManyResult
+	DBInsertManyTx(tx *sql.Tx, entities []*Entity, params *QueryParams) *InsertManyResult
+	DBInsertManyCtxTx(ctx context.Context, tx *sql.Tx, entities []*Entity, params *QueryParams) *InsertManyResult
+
+	DBUpsert(x *Entity, params *QueryParams) *QueryResult
+	DBUpsertCtx(ctx context.Context, x *Entity, params *QueryParams) *QueryResult
+	DBUpsertTx(tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult
+	DBUpsertCtxTx(ctx context.Context, tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult
+
+	DBInsertIgnore(x *Entity, params *QueryParams) *QueryResult
+	DBInsertIgnoreCtx(ctx context.Context, x *Entity, params *QueryParams) *QueryResult
+	DBInsertIgnoreTx(tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult
+	DBInsertIgnoreCtxTx(ctx context.Context, tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult
+
+	DBReplace(x *Entity, params *QueryParams) *QueryResult
+	DBReplaceCtx(ctx context.Context, x *Entity, params *QueryParams) *QueryResult
+	DBReplaceTx(tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult
+	DBReplaceCtxTx(ctx context.Context, tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult
+
+	DBDelete(x *Entity, params *QueryParams) *QueryResult
+	DBDeleteCtx(ctx context.Context, x *Entity, params *QueryParams) *QueryResult
+	DBDeleteTx(tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult
+	DBDeleteCtxTx(ctx context.Context, tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult
+
+	DBDeleteReturning(x *Entity, params *QueryParams) *QueryResult
+	DBDeleteReturningCtx(ctx context.Context, x *Entity, params *QueryParams) *QueryResult
+	DBDeleteReturningTx(tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult
+	DBDeleteReturningCtxTx(ctx context.Context, tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult
+
+	DBUpdate(x *Entity, params *QueryParams) *QueryResult
+	DBUpdateCtx(ctx context.Context, x *Entity, params *QueryParams) *QueryResult
+	DBUpdateTx(tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult
+	DBUpdateCtxTx(ctx context.Context, tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult
+
+	DBSelect(x *Entity, params *QueryParams) *QueryResult
+	DBSelectCtx(ctx context.Context, x *Entity, params *QueryParams) *QueryResult
+	DBSelectTx(tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult
+	DBSelectCtxTx(ctx context.Context, tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult
+
+	DBSelectAll(params *QueryParams) *QueryResult
+	DBSelectAllCtx(ctx context.Context, params *QueryParams) *QueryResult
+	DBSelectAllTx(tx *sql.Tx, params *QueryParams) *QueryResult
+	DBSelectAllCtxTx(ctx context.Context, tx *sql.Tx, params *QueryParams) *QueryResult
+
+	DBExists(x *Entity, params *QueryParams) *QueryResult
+	DBExistsCtx(ctx context.Context, x *Entity, params *QueryParams) *QueryResult
+	DBExistsTx(tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult
+	DBExistsCtxTx(ctx context.Context, tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult
+
+	DBSelectPage(x *Entity, params *QueryParams, cursor string) *QueryResult
+	DBSelectPageCtx(ctx context.Context, x *Entity, params *QueryParams, cursor string) *QueryResult
+	DBSelectPageTx(tx *sql.Tx, x *Entity, params *QueryParams, cursor string) *QueryResult
+	DBSelectPageCtxTx(ctx context.Context, tx *sql.Tx, x *Entity, params *QueryParams, cursor string) *QueryResult
+
+	DBGetByPK(uuid string) *QueryResult
+	DBGetByPKCtx(ctx context.Context, uuid string) *QueryResult
+	DBGetByPKTx(tx *sql.Tx, uuid string) *QueryResult
+	DBGetByPKCtxTx(ctx context.Context, tx *sql.Tx, uuid string) *QueryResult
+
+	DBUpdateByPK(x *Entity, params *QueryParams) *QueryResult
+	DBUpdateByPKCtx(ctx context.Context, x *Entity, params *QueryParams) *QueryResult
+	DBUpdateByPKTx(tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult
+	DBUpdateByPKCtxTx(ctx context.Context, tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult
+
+	DBUpdateChanged(x *Entity, params *QueryParams) *QueryResult
+	DBUpdateChangedCtx(ctx context.Context, x *Entity, params *QueryParams) *QueryResult
+	DBUpdateChangedTx(tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult
+	DBUpdateChangedCtxTx(ctx context.Context, tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult
+
+	DBDeleteByPK(x *Entity) *QueryResult
+	DBDeleteByPKCtx(ctx context.Context, x *Entity) *QueryResult
+	DBDeleteByPKTx(tx *sql.Tx, x *Entity) *QueryResult
+	DBDeleteByPKCtxTx(ctx context.Context, tx *sql.Tx, x *Entity) *QueryResult
+
+	DBReload(x *Entity) *QueryResult
+	DBReloadCtx(ctx context.Context, x *Entity) *QueryResult
+	DBReloadTx(tx *sql.Tx, x *Entity) *QueryResult
+	DBReloadCtxTx(ctx context.Context, tx *sql.Tx, x *Entity) *QueryResult
+
+	QueryGetAllAnimals() *QueryResult
+	QueryGetAllAnimalsCtx(ctx context.Context) *QueryResult
+	QueryGetAllAnimalsTx(tx *sql.Tx) *QueryResult
+	QueryGetAllAnimalsCtxTx(ctx context.Context, tx *sql.Tx) *QueryResult
+}
+
+var (
+	_ Querier = (*Client)(nil)
+	_ Querier = (*Fake)(nil)
+)
+
+// reader picks the connection for a read: the primary inside a transaction
+// or under UsePrimary, otherwise the next healthy replica.
+func (c *Client) reader(ctx context.Context, tx *sql.Tx) *conn {
+	if tx != nil || len(c.replicas) == 0 || (ctx != nil && ctx.Value(primaryCtxKey{}) != nil) {
+		return c.primary
+	}
+	now := time.Now().UnixNano()
+	start := c.next.Add(1)
+	for i := range c.replicas {
+		n := c.replicas[(start+uint64(i))%uint64(len(c.replicas))]
+		if n.downUntil.Load() <= now {
+			return n
+		}
+	}
+	return c.primary
+}
+
+type primaryCtxKey struct{}
+
+// UsePrimary returns a context whose reads are sent to the primary, so they
+// observe the caller's own writes.
+func UsePrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryCtxKey{}, true)
+}
+
+// SetDB points the package-level functions and Entity methods at primary
+// and, for reads, at replicas.
+func SetDB(primary *sql.DB, replicas ...*sql.DB) error {
+	c, err := NewClient(primary, replicas...)
+	if err != nil {
+		return err
+	}
+	SetClient(c)
+	return nil
+}
+
+// SetClient makes c the client behind the package-level functions and Entity methods.
+func SetClient(c *Client) {
+	defaultClient = c
+}
+
+// GetClient returns the client behind the package-level functions and Entity methods.
+func GetClient() *Client {
+	return defaultClient
+}
+
+// SetHook sets the hook of the client behind the package-level functions.
+func SetHook(h QueryHook) {
+	defaultClient.SetHook(h)
+}
+
+func decodeQueries() error {
+	queriesOnce.Do(func() {
+		for _, q := range queries {
+			b, err := base64.StdEncoding.DecodeString(q.QueryEncoded)
+			if err != nil {
+				queriesErr = err
+				return
+			}
+			q.Query = string(b)
+		}
+	})
+	return queriesErr
+}
+
+// Validate checks every field against the column it was generated from:
+// lengths, numeric ranges, enum and set members and date formats. NOT NULL
+// needs no check, only nullable columns are generated as Null.
+func (x *Entity) Validate() error {
+	return x.ValidateFields(Fields...)
+}
+
+// ValidateFields is Validate restricted to fields, for example the ones about
+// to be inserted or updated.
+func (x *Entity) ValidateFields(fields ...string) error {
+	var errs []FieldError
+	for _, field := range fields {
+		if reason := x.validateField(field); reason != "" {
+			errs = append(errs, FieldError{Field: field, Reason: reason})
+		}
+	}
+	if len(errs) > 0 {
+		return &ValidationError{Errors: errs}
+	}
+	return nil
+}
+
+func (x *Entity) validateField(field string) string {
+	switch field {
+	case FieldUuid: // uuid
+		return checkUUID(x.Uuid)
+	case FieldFirstInsert: // datetime(6)
+		return checkDatetime(x.FirstInsert, 6)
+	case FieldLastUpdate: // datetime(6)
+		return checkDatetime(x.LastUpdate, 6)
+	case FieldAnimal: // varchar(255)
+		return checkChars(x.Animal, 255)
+	case FieldBigNumber: // bigint
+		if x.BigNumber.Valid {
+			return checkInt(x.BigNumber.V, -9223372036854775808, 9223372036854775807)
+		}
+	case FieldTestField: // varchar(255)
+		if x.TestField.Valid {
+			return checkChars(x.TestField.V, 255)
+		}
+	}
+	return ""
+}
+
+func checkChars(s string, n int) string {
+	if utf8.RuneCountInString(s) > n {
+		return "longer than " + strconv.Itoa(n) + " characters"
+	}
+	return ""
+}
+
+func checkRange(v, min, max int64) string {
+	if v < min || v > max {
+		return "out of range [" + strconv.FormatInt(min, 10) + ", " + strconv.FormatInt(max, 10) + "]"
+	}
+	return ""
+}
+
+func checkInt(s string, min, max int64) string {
+	v, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return "not an integer in range [" + strconv.FormatInt(min, 10) + ", " + strconv.FormatInt(max, 10) + "]"
+	}
+	return checkRange(v, min, max)
+}
+
+// checkUUID accepts the 36 character form with dashes and the 32 character
+// form without them, like the UUID type.
+func checkUUID(s string) string {
+	hex := s
+	if len(s) == 36 {
+		if s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+			return "not a UUID"
+		}
+		hex = s[:8] + s[9:13] + s[14:18] + s[19:23] + s[24:]
+	}
+	if len(hex) != 32 || strings.Trim(strings.ToLower(hex), "0123456789abcdef") != "" {
+		return "not a UUID"
+	}
+	return ""
+}
+
+// checkDatetime accepts YYYY-MM-DD HH:MM:SS with at most fsp fractional
+// digits, within the DATETIME range.
+func checkDatetime(s string, fsp int) string {
+	base, frac, _ := strings.Cut(s, ".")
+	t, err := time.Parse(time.DateTime, base)
+	if err != nil || len(frac) > fsp || strings.Trim(frac, "0123456789") != "" {
+		return "not a datetime in the format YYYY-MM-DD HH:MM:SS" + strings.Repeat("f", fsp)
+	}
+	return checkTimeRange(t, minDatetime, maxDatetime)
+}
+
+var (
+	minDatetime  = time.Date(1000, 1, 1, 0, 0, 0, 0, time.UTC)
+	maxDatetime  = time.Date(9999, 12, 31, 23, 59, 59, 999999000, time.UTC)
+	minTimestamp = time.Date(1970, 1, 1, 0, 0, 1, 0, time.UTC)
+	maxTimestamp = time.Date(2038, 1, 19, 3, 14, 7, 999999000, time.UTC)
+)
+
+func checkTimeRange(t, min, max time.Time) string {
+	if t.Before(min) || t.After(max) {
+		return "out of range [" + min.Format(time.DateTime) + ", " + max.Format(time.DateTime) + "]"
+	}
+	return ""
+}
+
+// entitySnapshot is an Entity as it was loaded, see ChangedFields.
+type entitySnapshot struct {
+	entity Entity
+	fields []string
+}
+
+func (x *Entity) takeSnapshot(fields []string) {
+	s := &entitySnapshot{entity: *x, fields: fields}
+	s.entity.loaded = nil
+	x.loaded = s
+}
+
+// hasPrimaryKey reports whether every PrimaryKey field was loaded, so the
+// snapshot can locate the row.
+func (s *entitySnapshot) hasPrimaryKey() bool {
+	for _, key := range PrimaryKey {
+		found := false
+		for _, field := range s.fields {
+			if field == key {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// track snapshots entities read with params.Track.
+func track(params *QueryParams, fields []string, entities ...*Entity) {
+	if params == nil || !params.Track {
+		return
+	}
+	for _, x := range entities {
+		x.takeSnapshot(fields)
+	}
+}
+
+// Track snapshots every field of x as it is now, so that ChangedFields and
+// DBUpdateChanged work for entities read without QueryParams.Track, such as
+// the ones returned by DBGetByPK.
+func (x *Entity) Track() {
+	x.takeSnapshot(Fields)
+}
+
+// ChangedFields returns the loaded fields whose value differs from the one
+// read from the database, nil for an entity that is not tracked.
+func (x *Entity) ChangedFields() []string {
+	if x.loaded == nil {
+		return nil
+	}
+	var changed []string
+	for _, field := range x.loaded.fields {
+		if !fieldEqual(x, &x.loaded.entity, field) {
+			changed = append(changed, field)
+		}
+	}
+	return changed
+}
+
+func fieldEqual(a, b *Entity, field string) bool {
+	switch field {
+	case FieldUuid:
+		return a.Uuid == b.Uuid
+	case FieldFirstInsert:
+		return a.FirstInsert == b.FirstInsert
+	case FieldLastUpdate:
+		return a.LastUpdate == b.LastUpdate
+	case FieldAnimal:
+		return a.Animal == b.Animal
+	case FieldBigNumber:
+		return a.BigNumber == b.BigNumber
+	case FieldTestField:
+		return a.TestField == b.TestField
+	}
+	return true
+}
+
+func (x *Entity) GetFieldValue(field string) any {
+	switch field {
+	case FieldUuid:
+		return x.Uuid
+	case FieldFirstInsert:
+		return x.FirstInsert
+	case FieldLastUpdate:
+		return x.LastUpdate
+	case FieldAnimal:
+		return x.Animal
+	case FieldBigNumber:
+		return x.BigNumber
+	case FieldTestField:
+		return x.TestField
+	}
+	return nil
+}
+
+func (x *Entity) GetFieldsValues(fieldList []string) []any {
+	values := make([]any, 0, len(fieldList))
+	for _, field := range fieldList {
+		values = append(values, x.GetFieldValue(field))
+	}
+	return values
+}
+
+func GetValuePlaceholder(field string) string {
+	switch field {
+	case FieldUuid:
+		return "?"
+	case FieldFirstInsert:
+		return "?"
+	case FieldLastUpdate:
+		return "?"
+	case FieldAnimal:
+		return "?"
+	case FieldBigNumber:
+		return "?"
+	case FieldTestField:
+		return "?"
+	}
+	return ""
+}
+
+func GetValuesPlaceholders(fieldList []string) []string {
+	placeholders := make([]string, 0, len(fieldList))
+	for _, field := range fieldList {
+		placeholders = append(placeholders, GetValuePlaceholder(field))
+	}
+	return placeholders
+}
+
+func GetQualifiedField(field string) string {
+	switch field {
+	case FieldUuid:
+		return FQTN + ".`" + FieldUuid + "`"
+	case FieldFirstInsert:
+		return FQTN + ".`" + FieldFirstInsert + "`"
+	case FieldLastUpdate:
+		return FQTN + ".`" + FieldLastUpdate + "`"
+	case FieldAnimal:
+		return FQTN + ".`" + FieldAnimal + "`"
+	case FieldBigNumber:
+		return FQTN + ".`" + FieldBigNumber + "`"
+	case FieldTestField:
+		return FQTN + ".`" + FieldTestField + "`"
+	}
+	return ""
+}
+
+func GetQualifiedFields(fieldList []string) []string {
+	fields := make([]string, 0, len(fieldList))
+	for _, field := range fieldList {
+		fields = append(fields, GetQualifiedField(field))
+	}
+	return fields
+}
+
+func GetQualifiedPlaceholder(field string) string {
+	switch field {
+	case FieldUuid:
+		return FQTN + ".`" + FieldUuid + "` = ?"
+	case FieldFirstInsert:
+		return FQTN + ".`" + FieldFirstInsert + "` = ?"
+	case FieldLastUpdate:
+		return FQTN + ".`" + FieldLastUpdate + "` = ?"
+	case FieldAnimal:
+		return FQTN + ".`" + FieldAnimal + "` = ?"
+	case FieldBigNumber:
+		return FQTN + ".`" + FieldBigNumber + "` = ?"
+	case FieldTestField:
+		return FQTN + ".`" + FieldTestField + "` = ?"
+	}
+	return ""
+}
+
+func GetQualifiedPlaceholders(fieldList []string) []string {
+	placeholders := make([]string, 0, len(fieldList))
+	for _, field := range fieldList {
+		placeholders = append(placeholders, GetQualifiedPlaceholder(field))
+	}
+	return placeholders
+}
+
+func GetQualifiedCondition(field string) string {
+	switch field {
+	case FieldUuid:
+		return FQTN + ".`" + FieldUuid + "` = ?"
+	case FieldFirstInsert:
+		return FQTN + ".`" + FieldFirstInsert + "` = ?"
+	case FieldLastUpdate:
+		return FQTN + ".`" + FieldLastUpdate + "` = ?"
+	case FieldAnimal:
+		return FQTN + ".`" + FieldAnimal + "` = ?"
+	case FieldBigNumber:
+		return FQTN + ".`" + FieldBigNumber + "` <=> ?"
+	case FieldTestField:
+		return FQTN + ".`" + FieldTestField + "` <=> ?"
+	}
+	return ""
+}
+
+func GetQualifiedConditions(fieldList []string) []string {
+	conditions := make([]string, 0, len(fieldList))
+	for _, field := range fieldList {
+		conditions = append(conditions, GetQualifiedCondition(field))
+	}
+	return conditions
+}
+
+func (n *conn) getPreparedStmt(query string) (*sql.Stmt, error) {
+	if n.db == nil {
+		return nil, errors.New("db not initialized")
+	}
+	n.stmtMu.RLock()
+	if stmt, ok := n.stmtCache[query]; ok {
+		n.stmtMu.RUnlock()
+		return stmt, nil
+	}
+	n.stmtMu.RUnlock()
+
+	n.stmtMu.Lock()
+	defer n.stmtMu.Unlock()
+	if stmt, ok := n.stmtCache[query]; ok {
+		return stmt, nil
+	}
+	stmt, err := n.db.Prepare(query)
+	if err != nil {
+		return nil, err
+	}
+	n.stmtCache[query] = stmt
+	return stmt, nil
+}
+
+func scanRow(fields []string, rows *sql.Rows) (*Entity, error) {
+	x := &Entity{}
+	var (
+		ptrUuid        *string
+		ptrFirstInsert *string
+		ptrLastUpdate  *string
+		ptrAnimal      *string
+		ptrBigNumber   *string
+		ptrTestField   *string
+		scanTargets    []any
+	)
+
+	for _, field := range fields {
+		switch field {
+		case FieldUuid:
+			scanTargets = append(scanTargets, &ptrUuid)
+		case FieldFirstInsert:
+			scanTargets = append(scanTargets, &ptrFirstInsert)
+		case FieldLastUpdate:
+			scanTargets = append(scanTargets, &ptrLastUpdate)
+		case FieldAnimal:
+			scanTargets = append(scanTargets, &ptrAnimal)
+		case FieldBigNumber:
+			scanTargets = append(scanTargets, &ptrBigNumber)
+		case FieldTestField:
+			scanTargets = append(scanTargets, &ptrTestField)
+		}
+	}
+
+	err := rows.Scan(scanTargets...)
+	if err != nil {
+		return nil, err
+	}
+
+	if ptrUuid != nil {
+		x.Uuid = *ptrUuid
+	} else {
+		x.Uuid = ""
+	}
+	if ptrFirstInsert != nil {
+		x.FirstInsert = *ptrFirstInsert
+	} else {
+		x.FirstInsert = ""
+	}
+	if ptrLastUpdate != nil {
+		x.LastUpdate = *ptrLastUpdate
+	} else {
+		x.LastUpdate = ""
+	}
+	if ptrAnimal != nil {
+		x.Animal = *ptrAnimal
+	} else {
+		x.Animal = ""
+	}
+	if ptrBigNumber != nil {
+		x.BigNumber = Null[string]{V: *ptrBigNumber, Valid: true}
+	} else {
+		x.BigNumber = Null[string]{}
+	}
+	if ptrTestField != nil {
+		x.TestField = Null[string]{V: *ptrTestField, Valid: true}
+	} else {
+		x.TestField = Null[string]{}
+	}
+	return x, nil
+}
+
+func readRows(fields []string, rows *sql.Rows) (_ []*Entity, err error) {
+	defer func() {
+		if cerr := rows.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}()
+	var results []*Entity
+	for rows.Next() {
+		x, err := scanRow(fields, rows)
+		if err != nil {
+			return results, err
+		}
+		results = append(results, x)
+	}
+	if rerr := rows.Err(); rerr != nil {
+		return results, rerr
+	}
+	return results, nil
+}
+
+func bindStmtCtxTx(base *sql.Stmt, ctx context.Context, tx *sql.Tx) (*sql.Stmt, bool) {
+	if tx == nil {
+		return base, false
+	}
+	if ctx != nil {
+		return tx.StmtContext(ctx, base), true
+	}
+	return tx.Stmt(base), true
+}
+
+func (n *conn) execCore(ctx context.Context, tx *sql.Tx, name, query string, args ...any) (res sql.Result, err error) {
+	ctx, done := n.trace(ctx, "exec", name, query, len(args))
+	defer func() {
+		n.report(err)
+		err = Shared.WrapError(err)
+		done(resultRows(res), err)
+	}()
+	stmt, err := n.getPreparedStmt(query)
+	if err != nil {
+		return nil, err
+	}
+	s, needClose := bindStmtCtxTx(stmt, ctx, tx)
+	if needClose {
+		defer func() {
+			if cerr := s.Close(); err == nil && cerr != nil {
+				err = cerr
+			}
+		}()
+	}
+	if ctx != nil {
+		return s.ExecContext(ctx, args...)
+	}
+	return s.Exec(args...)
+}
+
+// execOnce is execCore without stmtCache, for statements whose text is
+// unlikely to repeat, such as a DBInsertMany batch of an odd size.
+func (n *conn) execOnce(ctx context.Context, tx *sql.Tx, name, query string, args ...any) (res sql.Result, err error) {
+	ctx, done := n.trace(ctx, "exec", name, query, len(args))
+	defer func() {
+		n.report(err)
+		err = Shared.WrapError(err)
+		done(resultRows(res), err)
+	}()
+	if tx != nil {
+		if ctx != nil {
+			return tx.ExecContext(ctx, query, args...)
+		}
+		return tx.Exec(query, args...)
+	}
+	if n.db == nil {
+		return nil, errors.New("db not initialized")
+	}
+	if ctx != nil {
+		return n.db.ExecContext(ctx, query, args...)
+	}
+	return n.db.Exec(query, args...)
+}
+
+func (n *conn) queryCore(ctx context.Context, tx *sql.Tx, name string, fields []string, query string, args ...any) (out []*Entity, err error) {
+	ctx, done := n.trace(ctx, "query", name, query, len(args))
+	defer func() {
+		n.report(err)
+		err = Shared.WrapError(err)
+		done(int64(len(out)), err)
+	}()
+	stmt, err := n.getPreparedStmt(query)
+	if err != nil {
+		return nil, err
+	}
+	s, needClose := bindStmtCtxTx(stmt, ctx, tx)
+	if needClose {
+		defer func() {
+			if cerr := s.Close(); err == nil && cerr != nil {
+				err = cerr
+			}
+		}()
+	}
+	var rows *sql.Rows
+	if ctx != nil {
+		rows, err = s.QueryContext(ctx, args...)
+	} else {
+		rows, err = s.Query(args...)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if out, err = readRows(fields, rows); err != nil {
+		return out, err
+	}
+	return out, afterLoad(ctx, tx, out...)
+}
+
+func (n *conn) queryOneCore(ctx context.Context, tx *sql.Tx, name string, fields []string, query string, args ...any) (out *Entity, err error) {
+	ctx, done := n.trace(ctx, "query", name, query, len(args))
+	defer func() {
+		n.report(err)
+		err = Shared.WrapError(err)
+		rows := int64(0)
+		if out != nil {
+			rows = 1
+		}
+		done(rows, err)
+	}()
+	stmt, err := n.getPreparedStmt(query)
+	if err != nil {
+		return nil, err
+	}
+	s, needClose := bindStmtCtxTx(stmt, ctx, tx)
+	if needClose {
+		defer func() {
+			if cerr := s.Close(); err == nil && cerr != nil {
+				err = cerr
+			}
+		}()
+	}
+	var rows *sql.Rows
+	if ctx != nil {
+		rows, err = s.QueryContext(ctx, args...)
+	} else {
+		rows, err = s.Query(args...)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if cerr := rows.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}()
+	if !rows.Next() {
+		if rerr := rows.Err(); rerr != nil {
+			return nil, rerr
+		}
+		return nil, nil
+	}
+	ent, err := scanRow(fields, rows)
 	if err != nil {
 		return nil, err
 	}
+	if rows.Next() {
+		return nil, errors.New("queryOneCore: expected one row, got multiple")
+	}
+	if rerr := rows.Err(); rerr != nil {
+		return nil, rerr
+	}
+	if err = afterLoad(ctx, tx, ent); err != nil {
+		return nil, err
+	}
+	return ent, nil
+}
+
+func (n *conn) scalarCore(ctx context.Context, tx *sql.Tx, name, query string, args ...any) (_ int, err error) {
+	ctx, done := n.trace(ctx, "query", name, query, len(args))
+	defer func() {
+		n.report(err)
+		err = Shared.WrapError(err)
+		rows := int64(1)
+		if err != nil {
+			rows = 0
+		}
+		done(rows, err)
+	}()
+	stmt, err := n.getPreparedStmt(query)
+	if err != nil {
+		return 0, err
+	}
+	s, needClose := bindStmtCtxTx(stmt, ctx, tx)
+	if needClose {
+		defer func() {
+			if cerr := s.Close(); err == nil && cerr != nil {
+				err = cerr
+			}
+		}()
+	}
+	var v int
+	if ctx != nil {
+		err = s.QueryRowContext(ctx, args...).Scan(&v)
+	} else {
+		err = s.QueryRow(args...).Scan(&v)
+	}
+	return v, err
+}
+
+// readToken reads back the ConcurrencyToken NOW(6) wrote to the row of x.
+// Without it the entity would carry no token and its next update would go
+// unchecked.
+func (c *Client) readToken(ctx context.Context, tx *sql.Tx, x *Entity) (_ string, err error) {
+	where, args, err := buildWhere(x, PrimaryKey, nil)
+	if err != nil {
+		return "", err
+	}
+	q := "SELECT " + GetQualifiedField(ConcurrencyToken) + " FROM " + FQTN + where
+	n := c.primary
+	ctx, done := n.trace(ctx, "query", "ReadToken", q, len(args))
+	defer func() {
+		n.report(err)
+		err = Shared.WrapError(err)
+		rows := int64(1)
+		if err != nil {
+			rows = 0
+		}
+		done(rows, err)
+	}()
+	stmt, err := n.getPreparedStmt(q)
+	if err != nil {
+		return "", err
+	}
 	s, needClose := bindStmtCtxTx(stmt, ctx, tx)
 	if needClose {
 		defer func() {
 			if cerr := s.Close(); err == nil && cerr != nil {
 				err = cerr
 			}
-		}()
+		}()
+	}
+	var v string
+	if ctx != nil {
+		err = s.QueryRowContext(ctx, args...).Scan(&v)
+	} else {
+		err = s.QueryRow(args...).Scan(&v)
+	}
+	return v, err
+}
+
+type Condition struct {
+	field    string
+	op       string
+	args     []any
+	children []*Condition
+}
+
+func Eq(field string, value any) *Condition {
+	return &Condition{field: field, op: "=", args: []any{value}}
+}
+
+func Ne(field string, value any) *Condition {
+	return &Condition{field: field, op: "!=", args: []any{value}}
+}
+
+func Lt(field string, value any) *Condition {
+	return &Condition{field: field, op: "<", args: []any{value}}
+}
+
+func Le(field string, value any) *Condition {
+	return &Condition{field: field, op: "<=", args: []any{value}}
+}
+
+func Gt(field string, value any) *Condition {
+	return &Condition{field: field, op: ">", args: []any{value}}
+}
+
+func Ge(field string, value any) *Condition {
+	return &Condition{field: field, op: ">=", args: []any{value}}
+}
+
+func Like(field string, pattern any) *Condition {
+	return &Condition{field: field, op: "LIKE", args: []any{pattern}}
+}
+
+func In(field string, values ...any) *Condition {
+	return &Condition{field: field, op: "IN", args: values}
+}
+
+func NotIn(field string, values ...any) *Condition {
+	return &Condition{field: field, op: "NOT IN", args: values}
+}
+
+func Between(field string, from, to any) *Condition {
+	return &Condition{field: field, op: "BETWEEN", args: []any{from, to}}
+}
+
+func IsNull(field string) *Condition {
+	return &Condition{field: field, op: "IS NULL"}
+}
+
+func IsNotNull(field string) *Condition {
+	return &Condition{field: field, op: "IS NOT NULL"}
+}
+
+func And(conditions ...*Condition) *Condition {
+	return &Condition{op: "AND", children: conditions}
+}
+
+func Or(conditions ...*Condition) *Condition {
+	return &Condition{op: "OR", children: conditions}
+}
+
+// build appends the SQL of the condition to sb and its arguments to args.
+// The generated SQL depends only on the shape of the condition, so the
+// resulting statement is reused from stmtCache across calls.
+func (c *Condition) build(sb *strings.Builder, args []any) ([]any, error) {
+	if c == nil {
+		return args, errors.New("nil condition")
+	}
+	switch c.op {
+	case "AND", "OR":
+		if len(c.children) == 0 {
+			if c.op == "AND" {
+				sb.WriteString("1 = 1")
+			} else {
+				sb.WriteString("1 = 0")
+			}
+			return args, nil
+		}
+		sb.WriteString("(")
+		for i, child := range c.children {
+			if i > 0 {
+				sb.WriteString(" " + c.op + " ")
+			}
+			var err error
+			if args, err = child.build(sb, args); err != nil {
+				return args, err
+			}
+		}
+		sb.WriteString(")")
+		return args, nil
+	}
+	qf := GetQualifiedField(c.field)
+	if qf == "" {
+		return args, errors.New("unknown field: " + c.field)
+	}
+	switch c.op {
+	case "IS NULL", "IS NOT NULL":
+		sb.WriteString(qf + " " + c.op)
+	case "IN", "NOT IN":
+		if len(c.args) == 0 {
+			if c.op == "IN" {
+				sb.WriteString("1 = 0")
+			} else {
+				sb.WriteString("1 = 1")
+			}
+			return args, nil
+		}
+		sb.WriteString(qf + " " + c.op + " (" + strings.Repeat("?, ", len(c.args)-1) + "?)")
+	case "BETWEEN":
+		sb.WriteString(qf + " BETWEEN ? AND ?")
+	default:
+		sb.WriteString(qf + " " + c.op + " ?")
 	}
-	var rows *sql.Rows
-	if ctx != nil {
-		rows, err = s.QueryContext(ctx, args...)
-	} else {
-		rows, err = s.Query(args...)
+	return append(args, c.args...), nil
+}
+
+// buildWhere combines the equality fields, whose values are taken from x, with
+// the explicit conditions into a single AND-ed WHERE clause.
+func buildWhere(x *Entity, whereFields []string, conditions []*Condition) (string, []any, error) {
+	if len(whereFields) == 0 && len(conditions) == 0 {
+		return "", nil, nil
+	}
+	var sb strings.Builder
+	sb.WriteString(" WHERE ")
+	sb.WriteString(strings.Join(GetQualifiedConditions(whereFields), " AND "))
+	args := x.GetFieldsValues(whereFields)
+	for i, c := range conditions {
+		if i > 0 || len(whereFields) > 0 {
+			sb.WriteString(" AND ")
+		}
+		var err error
+		if args, err = c.build(&sb, args); err != nil {
+			return "", nil, err
+		}
 	}
-	if err != nil {
-		return nil, err
+	return sb.String(), args, nil
+}
+
+type Order struct {
+	Field string
+	Desc  bool
+}
+
+func Asc(field string) Order {
+	return Order{Field: field}
+}
+
+func Desc(field string) Order {
+	return Order{Field: field, Desc: true}
+}
+
+// buildOrderLimit renders the ORDER BY, LIMIT and OFFSET clauses of params.
+// LIMIT and OFFSET are bound as arguments so that paging does not produce
+// a new prepared statement per page.
+func buildOrderLimit(params *QueryParams) (string, []any, error) {
+	if params == nil {
+		return "", nil, nil
+	}
+	var sb strings.Builder
+	var args []any
+	for i, o := range params.OrderBy {
+		qf := GetQualifiedField(o.Field)
+		if qf == "" {
+			return "", nil, errors.New("unknown field: " + o.Field)
+		}
+		if i == 0 {
+			sb.WriteString(" ORDER BY ")
+		} else {
+			sb.WriteString(", ")
+		}
+		sb.WriteString(qf)
+		if o.Desc {
+			sb.WriteString(" DESC")
+		} else {
+			sb.WriteString(" ASC")
+		}
+	}
+	if params.Limit < 0 || params.Offset < 0 {
+		return "", nil, errors.New("params.Limit and params.Offset must not be negative")
 	}
-	return readRows(fields, rows)
+	if params.Limit > 0 {
+		sb.WriteString(" LIMIT ?")
+		args = append(args, params.Limit)
+	} else if params.Offset > 0 {
+		sb.WriteString(" LIMIT 18446744073709551615")
+	}
+	if params.Offset > 0 {
+		sb.WriteString(" OFFSET ?")
+		args = append(args, params.Offset)
+	}
+	return sb.String(), args, nil
 }
 
-func queryOneCore(ctx context.Context, tx *sql.Tx, fields []string, query string, args ...any) (_ *Entity, err error) {
-	stmt, err := getPreparedStmt(query)
-	if err != nil {
-		return nil, err
+type cursorKey struct {
+	Field string `json:"f"`
+	Desc  bool   `json:"d,omitempty"`
+}
+
+type cursorToken struct {
+	Table  string            `json:"t"`
+	Order  []cursorKey       `json:"o"`
+	Values []json.RawMessage `json:"v"`
+}
+
+// encodeCursor captures the values of the ordering fields of x in an opaque token.
+// checkPageOrder rejects orderings that do not order rows totally: every
+// field must be NOT NULL, and together they must cover the primary key or a
+// unique key, or rows would be skipped or repeated across pages.
+func checkPageOrder(orders []Order) error {
+	var zero Entity
+	ordered := make(map[string]bool, len(orders))
+	for _, o := range orders {
+		if _, ok := zero.GetFieldValue(o.Field).(driver.Valuer); ok {
+			return errors.New("DBSelectPage does not support nullable OrderBy field: " + o.Field)
+		}
+		ordered[o.Field] = true
+	}
+	for _, key := range UniqueKeys {
+		covered := true
+		for _, field := range key {
+			if !ordered[field] {
+				covered = false
+				break
+			}
+		}
+		if covered {
+			return nil
+		}
 	}
-	s, needClose := bindStmtCtxTx(stmt, ctx, tx)
-	if needClose {
-		defer func() {
-			if cerr := s.Close(); err == nil && cerr != nil {
-				err = cerr
+	return errors.New("DBSelectPage requires params.OrderBy to include the primary key or a unique key")
+}
+
+func encodeCursor(orders []Order, x *Entity) (string, error) {
+	token := cursorToken{Table: FQTN, Order: make([]cursorKey, 0, len(orders)), Values: make([]json.RawMessage, 0, len(orders))}
+	for _, o := range orders {
+		v := x.GetFieldValue(o.Field)
+		if valuer, ok := v.(driver.Valuer); ok {
+			if dv, err := valuer.Value(); err != nil || dv == nil {
+				return "", errors.New("cursor field must not be NULL: " + o.Field)
 			}
-		}()
+		}
+		raw, err := json.Marshal(v)
+		if err != nil {
+			return "", err
+		}
+		token.Order = append(token.Order, cursorKey{Field: o.Field, Desc: o.Desc})
+		token.Values = append(token.Values, raw)
 	}
-	var rows *sql.Rows
-	if ctx != nil {
-		rows, err = s.QueryContext(ctx, args...)
-	} else {
-		rows, err = s.Query(args...)
+	b, err := json.Marshal(token)
+	if err != nil {
+		return "", err
 	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// decodeCursor validates that cursor was issued for this table and ordering
+// and returns the typed values of the ordering fields.
+func decodeCursor(orders []Order, cursor string) ([]any, error) {
+	errInvalid := errors.New("invalid cursor")
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
 	if err != nil {
-		return nil, err
+		return nil, errInvalid
 	}
-	defer func() {
-		if cerr := rows.Close(); cerr != nil && err == nil {
-			err = cerr
+	var token cursorToken
+	if err = json.Unmarshal(b, &token); err != nil {
+		return nil, errInvalid
+	}
+	if token.Table != FQTN || len(token.Order) != len(orders) || len(token.Values) != len(orders) {
+		return nil, errInvalid
+	}
+	values := make([]any, 0, len(orders))
+	for i, o := range orders {
+		if token.Order[i].Field != o.Field || token.Order[i].Desc != o.Desc {
+			return nil, errors.New("cursor does not match params.OrderBy")
 		}
-	}()
-	if !rows.Next() {
-		if rerr := rows.Err(); rerr != nil {
-			return nil, rerr
+		v, err := decodeCursorValue(o.Field, token.Values[i])
+		if err != nil {
+			return nil, errInvalid
 		}
-		return nil, nil
-	}
-	ent, err := scanRow(fields, rows)
-	if err != nil {
-		return nil, err
+		values = append(values, v)
 	}
-	if rows.Next() {
-		return nil, errors.New("queryOneCore: expected one row, got multiple")
+	return values, nil
+}
+
+// seekCondition selects the rows strictly after values in the given ordering:
+// (a > ?) OR (a = ? AND b > ?) OR ...
+func seekCondition(orders []Order, values []any) *Condition {
+	branches := make([]*Condition, 0, len(orders))
+	for i, o := range orders {
+		terms := make([]*Condition, 0, i+1)
+		for j := 0; j < i; j++ {
+			terms = append(terms, Eq(orders[j].Field, values[j]))
+		}
+		if o.Desc {
+			terms = append(terms, Lt(o.Field, values[i]))
+		} else {
+			terms = append(terms, Gt(o.Field, values[i]))
+		}
+		branches = append(branches, And(terms...))
 	}
-	if rerr := rows.Err(); rerr != nil {
-		return nil, rerr
+	return Or(branches...)
+}
+
+func decodeCursorValue(field string, raw json.RawMessage) (any, error) {
+	switch field {
+	case FieldUuid:
+		var v string
+		err := json.Unmarshal(raw, &v)
+		return v, err
+	case FieldFirstInsert:
+		var v string
+		err := json.Unmarshal(raw, &v)
+		return v, err
+	case FieldLastUpdate:
+		var v string
+		err := json.Unmarshal(raw, &v)
+		return v, err
+	case FieldAnimal:
+		var v string
+		err := json.Unmarshal(raw, &v)
+		return v, err
+	case FieldBigNumber:
+		var v Null[string]
+		err := json.Unmarshal(raw, &v)
+		return v, err
+	case FieldTestField:
+		var v Null[string]
+		err := json.Unmarshal(raw, &v)
+		return v, err
 	}
-	return ent, nil
+	return nil, errors.New("unknown field: " + field)
 }
 
-func scalarCore(ctx context.Context, tx *sql.Tx, query string, args ...any) (_ int, err error) {
-	stmt, err := getPreparedStmt(query)
-	if err != nil {
-		return 0, err
+// alpha is referenced by fk_beta_alpha, and InnoDB only truncates a
+// referenced table while foreign_key_checks is off for the session.
+func (c *Client) dbTruncate(ctx context.Context, tx *sql.Tx) *QueryResult {
+	if tx == nil {
+		if c.primary.db == nil {
+			return &QueryResult{Error: errors.New("db not initialized")}
+		}
+		var err error
+		if ctx != nil {
+			tx, err = c.primary.db.BeginTx(ctx, nil)
+		} else {
+			tx, err = c.primary.db.Begin()
+		}
+		if err != nil {
+			return &QueryResult{Error: err}
+		}
+		defer tx.Rollback()
 	}
-	s, needClose := bindStmtCtxTx(stmt, ctx, tx)
-	if needClose {
-		defer func() {
-			if cerr := s.Close(); err == nil && cerr != nil {
-				err = cerr
-			}
-		}()
+	if _, err := c.primary.execCore(ctx, tx, "Truncate", "SET SESSION foreign_key_checks = 0"); err != nil {
+		return &QueryResult{Error: err}
 	}
-	var v int
-	if ctx != nil {
-		err = s.QueryRowContext(ctx, args...).Scan(&v)
-	} else {
-		err = s.QueryRow(args...).Scan(&v)
+	res, err := c.primary.execCore(ctx, tx, "Truncate", "TRUNCATE TABLE "+FQTN)
+	if _, rerr := c.primary.execCore(ctx, tx, "Truncate", "SET SESSION foreign_key_checks = 1"); err == nil {
+		err = rerr
 	}
-	return v, err
+	return &QueryResult{Result: res, Error: err}
 }
 
 func DBTruncate() *QueryResult {
-	res, err := execCore(nil, nil, "TRUNCATE TABLE "+FQTN)
-	return &QueryResult{Result: res, Error: err}
+	return defaultClient.dbTruncate(nil, nil)
 }
 func DBTruncateCtx(ctx context.Context) *QueryResult {
-	res, err := execCore(ctx, nil, "TRUNCATE TABLE "+FQTN)
-	return &QueryResult{Result: res, Error: err}
+	return defaultClient.dbTruncate(ctx, nil)
 }
 func DBTruncateTx(tx *sql.Tx) *QueryResult {
-	res, err := execCore(nil, tx, "TRUNCATE TABLE "+FQTN)
-	return &QueryResult{Result: res, Error: err}
+	return defaultClient.dbTruncate(nil, tx)
 }
 func DBTruncateCtxTx(ctx context.Context, tx *sql.Tx) *QueryResult {
-	res, err := execCore(ctx, tx, "TRUNCATE TABLE "+FQTN)
-	return &QueryResult{Result: res, Error: err}
+	return defaultClient.dbTruncate(ctx, tx)
+}
+func (c *Client) DBTruncate() *QueryResult {
+	return c.dbTruncate(nil, nil)
+}
+func (c *Client) DBTruncateCtx(ctx context.Context) *QueryResult {
+	return c.dbTruncate(ctx, nil)
+}
+func (c *Client) DBTruncateTx(tx *sql.Tx) *QueryResult {
+	return c.dbTruncate(nil, tx)
+}
+func (c *Client) DBTruncateCtxTx(ctx context.Context, tx *sql.Tx) *QueryResult {
+	return c.dbTruncate(ctx, tx)
 }
 
-func (x *Entity) DBInsert(params *QueryParams) *QueryResult {
+func (c *Client) dbInsert(ctx context.Context, tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult {
+	if err := runHook(ctx, tx, x, "BeforeInsert"); err != nil {
+		return &QueryResult{Error: err}
+	}
 	fieldsToInsert := Fields
 	if params != nil && len(params.Insert) > 0 {
 		fieldsToInsert = params.Insert
 	}
-	q := "INSERT INTO " + FQTN + " (" + strings.Join(GetQualifiedFields(fieldsToInsert), ", ") + ") VALUES (" + strings.Join(GetValuesPlaceholders(fieldsToInsert), ", ") + ")"
-	res, err := execCore(nil, nil, q, x.GetFieldsValues(fieldsToInsert)...)
-	return &QueryResult{Result: res, Error: err}
+	fieldsToInsert, bound := stampInsert(fieldsToInsert, params, x)
+	if ValidateOnWrite {
+		if err := x.ValidateFields(bound...); err != nil {
+			return &QueryResult{Error: err}
+		}
+	}
+	q := "INSERT INTO " + FQTN + " (" + strings.Join(GetQualifiedFields(fieldsToInsert), ", ") + ") VALUES (" + strings.Join(stampPlaceholders(fieldsToInsert, bound), ", ") + ")"
+	res, err := c.primary.execCore(ctx, tx, "Insert", q, x.GetFieldsValues(bound)...)
+	if err == nil && tokenInserted(fieldsToInsert, bound) {
+		x.LastUpdate, err = c.readToken(ctx, tx, x)
+	}
+	return afterHook(ctx, tx, x, "AfterInsert", &QueryResult{Result: res, Error: err})
 }
 
+func (x *Entity) DBInsert(params *QueryParams) *QueryResult {
+	return defaultClient.dbInsert(nil, nil, x, params)
+}
 func (x *Entity) DBInsertCtx(ctx context.Context, params *QueryParams) *QueryResult {
+	return defaultClient.dbInsert(ctx, nil, x, params)
+}
+func (x *Entity) DBInsertTx(tx *sql.Tx, params *QueryParams) *QueryResult {
+	return defaultClient.dbInsert(nil, tx, x, params)
+}
+func (x *Entity) DBInsertCtxTx(ctx context.Context, tx *sql.Tx, params *QueryParams) *QueryResult {
+	return defaultClient.dbInsert(ctx, tx, x, params)
+}
+func (c *Client) DBInsert(x *Entity, params *QueryParams) *QueryResult {
+	return c.dbInsert(nil, nil, x, params)
+}
+func (c *Client) DBInsertCtx(ctx context.Context, x *Entity, params *QueryParams) *QueryResult {
+	return c.dbInsert(ctx, nil, x, params)
+}
+func (c *Client) DBInsertTx(tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult {
+	return c.dbInsert(nil, tx, x, params)
+}
+func (c *Client) DBInsertCtxTx(ctx context.Context, tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult {
+	return c.dbInsert(ctx, tx, x, params)
+}
+
+// dbInsertReturning inserts x and reads back params.Select (defaults to all
+// fields) as computed by the server, including defaults and trigger changes.
+func (c *Client) dbInsertReturning(ctx context.Context, tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult {
+	if err := runHook(ctx, tx, x, "BeforeInsert"); err != nil {
+		return &QueryResult{Error: err}
+	}
 	fieldsToInsert := Fields
+	fieldsToReturn := Fields
 	if params != nil && len(params.Insert) > 0 {
 		fieldsToInsert = params.Insert
 	}
-	q := "INSERT INTO " + FQTN + " (" + strings.Join(GetQualifiedFields(fieldsToInsert), ", ") + ") VALUES (" + strings.Join(GetValuesPlaceholders(fieldsToInsert), ", ") + ")"
-	res, err := execCore(ctx, nil, q, x.GetFieldsValues(fieldsToInsert)...)
-	return &QueryResult{Result: res, Error: err}
+	fieldsToInsert, bound := stampInsert(fieldsToInsert, params, x)
+	if ValidateOnWrite {
+		if err := x.ValidateFields(bound...); err != nil {
+			return &QueryResult{Error: err}
+		}
+	}
+	if params != nil && len(params.Select) > 0 {
+		fieldsToReturn = params.Select
+	}
+	q := "INSERT INTO " + FQTN + " (" + strings.Join(GetQualifiedFields(fieldsToInsert), ", ") + ") VALUES (" + strings.Join(stampPlaceholders(fieldsToInsert, bound), ", ") + ") RETURNING " + strings.Join(GetQualifiedFields(fieldsToReturn), ", ")
+	entities, err := c.primary.queryCore(ctx, tx, "InsertReturning", fieldsToReturn, q, x.GetFieldsValues(bound)...)
+	track(params, fieldsToReturn, entities...)
+	result := &QueryResult{Entities: entities, Error: err}
+	if len(entities) > 0 {
+		result.Entity = entities[0]
+	}
+	return afterHook(ctx, tx, x, "AfterInsert", result)
 }
 
-func (x *Entity) DBInsertTx(tx *sql.Tx, params *QueryParams) *QueryResult {
+func (x *Entity) DBInsertReturning(params *QueryParams) *QueryResult {
+	return defaultClient.dbInsertReturning(nil, nil, x, params)
+}
+func (x *Entity) DBInsertReturningCtx(ctx context.Context, params *QueryParams) *QueryResult {
+	return defaultClient.dbInsertReturning(ctx, nil, x, params)
+}
+func (x *Entity) DBInsertReturningTx(tx *sql.Tx, params *QueryParams) *QueryResult {
+	return defaultClient.dbInsertReturning(nil, tx, x, params)
+}
+func (x *Entity) DBInsertReturningCtxTx(ctx context.Context, tx *sql.Tx, params *QueryParams) *QueryResult {
+	return defaultClient.dbInsertReturning(ctx, tx, x, params)
+}
+func (c *Client) DBInsertReturning(x *Entity, params *QueryParams) *QueryResult {
+	return c.dbInsertReturning(nil, nil, x, params)
+}
+func (c *Client) DBInsertReturningCtx(ctx context.Context, x *Entity, params *QueryParams) *QueryResult {
+	return c.dbInsertReturning(ctx, nil, x, params)
+}
+func (c *Client) DBInsertReturningTx(tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult {
+	return c.dbInsertReturning(nil, tx, x, params)
+}
+func (c *Client) DBInsertReturningCtxTx(ctx context.Context, tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult {
+	return c.dbInsertReturning(ctx, tx, x, params)
+}
+
+// maxPlaceholders is the protocol limit of bound arguments per prepared statement.
+const maxPlaceholders = 65535
+
+// estimatePacketSize approximates the bytes a bound argument takes in a
+// COM_STMT_EXECUTE packet: its type, length prefix and payload.
+func estimatePacketSize(v any) int {
+	switch v := v.(type) {
+	case nil:
+		return 2
+	case string:
+		return len(v) + 11
+	case []byte:
+		return len(v) + 11
+	case driver.Valuer:
+		if dv, err := v.Value(); err == nil {
+			if _, ok := dv.(driver.Valuer); !ok {
+				return estimatePacketSize(dv)
+			}
+		}
+	}
+	return 14
+}
+
+// dbInsertMany writes entities with multi-row INSERT statements. Each batch
+// stays under maxPlaceholders bound arguments and MaxAllowedPacket bytes;
+// params.BatchSize lowers the number of rows per batch further. Statements
+// are cached per batch size. A failed batch does not stop the remaining ones,
+// its error is reported in BatchErrors.
+func (c *Client) dbInsertMany(ctx context.Context, tx *sql.Tx, entities []*Entity, params *QueryParams) *InsertManyResult {
+	result := &InsertManyResult{}
+	if len(entities) == 0 {
+		return result
+	}
+	for _, x := range entities {
+		if x == nil {
+			result.Error = errors.New("DBInsertMany does not accept nil entities")
+			return result
+		}
+		if err := runHook(ctx, tx, x, "BeforeInsert"); err != nil {
+			result.Error = err
+			return result
+		}
+	}
+	fieldsToInsert := Fields
+	if params != nil && len(params.Insert) > 0 {
+		fieldsToInsert = params.Insert
+	}
+	fieldsToInsert, bound := stampInsert(fieldsToInsert, params, entities...)
+	if ValidateOnWrite {
+		for _, x := range entities {
+			if err := x.ValidateFields(bound...); err != nil {
+				result.Error = err
+				return result
+			}
+		}
+	}
+	rowsPerBatch := maxPlaceholders / max(len(bound), 1)
+	if params != nil && params.BatchSize > 0 && params.BatchSize < rowsPerBatch {
+		rowsPerBatch = params.BatchSize
+	}
+	head := "INSERT INTO " + FQTN + " (" + strings.Join(GetQualifiedFields(fieldsToInsert), ", ") + ") VALUES "
+	row := "(" + strings.Join(stampPlaceholders(fieldsToInsert, bound), ", ") + ")"
+	args := make([]any, 0, rowsPerBatch*len(fieldsToInsert))
+	start, size := 0, len(head)
+	var hookErr error
+	flush := func(end int) {
+		q := head + strings.Repeat(row+", ", end-start-1) + row
+		// Only full batches are prepared and cached, so stmtCache holds one
+		// statement per batch size instead of one per row count.
+		exec := c.primary.execOnce
+		if end-start == rowsPerBatch {
+			exec = c.primary.execCore
+		}
+		res, err := exec(ctx, tx, "InsertMany", q, args...)
+		if err == nil {
+			var n int64
+			if n, err = res.RowsAffected(); err == nil {
+				result.RowsAffected += n
+			}
+		}
+		if err == nil {
+			for _, x := range entities[start:end] {
+				if herr := runHook(ctx, tx, x, "AfterInsert"); herr != nil && hookErr == nil {
+					hookErr = herr
+				}
+			}
+		}
+		if err != nil {
+			result.BatchErrors = append(result.BatchErrors, &BatchError{Batch: result.Batches, Offset: start, Count: end - start, Err: err})
+		}
+		result.Batches++
+		start, size, args = end, len(head), args[:0]
+	}
+	for i, x := range entities {
+		values := x.GetFieldsValues(bound)
+		rowSize := len(row) + 2
+		for _, v := range values {
+			rowSize += estimatePacketSize(v)
+		}
+		if i > start && (i-start == rowsPerBatch || size+rowSize > MaxAllowedPacket) {
+			flush(i)
+		}
+		args = append(args, values...)
+		size += rowSize
+	}
+	flush(len(entities))
+	errs := make([]error, 0, len(result.BatchErrors)+1)
+	for _, be := range result.BatchErrors {
+		errs = append(errs, be)
+	}
+	if hookErr != nil {
+		errs = append(errs, hookErr)
+	}
+	result.Error = errors.Join(errs...)
+	return result
+}
+
+func DBInsertMany(entities []*Entity, params *QueryParams) *InsertManyResult {
+	return defaultClient.dbInsertMany(nil, nil, entities, params)
+}
+func DBInsertManyCtx(ctx context.Context, entities []*Entity, params *QueryParams) *InsertManyResult {
+	return defaultClient.dbInsertMany(ctx, nil, entities, params)
+}
+func DBInsertManyTx(tx *sql.Tx, entities []*Entity, params *QueryParams) *InsertManyResult {
+	return defaultClient.dbInsertMany(nil, tx, entities, params)
+}
+func DBInsertManyCtxTx(ctx context.Context, tx *sql.Tx, entities []*Entity, params *QueryParams) *InsertManyResult {
+	return defaultClient.dbInsertMany(ctx, tx, entities, params)
+}
+func (c *Client) DBInsertMany(entities []*Entity, params *QueryParams) *InsertManyResult {
+	return c.dbInsertMany(nil, nil, entities, params)
+}
+func (c *Client) DBInsertManyCtx(ctx context.Context, entities []*Entity, params *QueryParams) *InsertManyResult {
+	return c.dbInsertMany(ctx, nil, entities, params)
+}
+func (c *Client) DBInsertManyTx(tx *sql.Tx, entities []*Entity, params *QueryParams) *InsertManyResult {
+	return c.dbInsertMany(nil, tx, entities, params)
+}
+func (c *Client) DBInsertManyCtxTx(ctx context.Context, tx *sql.Tx, entities []*Entity, params *QueryParams) *InsertManyResult {
+	return c.dbInsertMany(ctx, tx, entities, params)
+}
+
+func (c *Client) dbUpsertCore(ctx context.Context, tx *sql.Tx, x *Entity, params *QueryParams, op, verb, suffix string) *QueryResult {
+	if err := runHook(ctx, tx, x, "BeforeInsert"); err != nil {
+		return &QueryResult{Error: err}
+	}
 	fieldsToInsert := Fields
 	if params != nil && len(params.Insert) > 0 {
 		fieldsToInsert = params.Insert
 	}
-	q := "INSERT INTO " + FQTN + " (" + strings.Join(GetQualifiedFields(fieldsToInsert), ", ") + ") VALUES (" + strings.Join(GetValuesPlaceholders(fieldsToInsert), ", ") + ")"
-	res, err := execCore(nil, tx, q, x.GetFieldsValues(fieldsToInsert)...)
-	return &QueryResult{Result: res, Error: err}
+	fieldsToInsert, bound := stampInsert(fieldsToInsert, params, x)
+	if ValidateOnWrite {
+		if err := x.ValidateFields(bound...); err != nil {
+			return &QueryResult{Error: err}
+		}
+	}
+	q := verb + " " + FQTN + " (" + strings.Join(GetQualifiedFields(fieldsToInsert), ", ") + ") VALUES (" + strings.Join(stampPlaceholders(fieldsToInsert, bound), ", ") + ")" + suffix
+	res, err := c.primary.execCore(ctx, tx, op, q, x.GetFieldsValues(bound)...)
+	if err != nil {
+		return &QueryResult{Result: res, Error: err}
+	}
+	n, err := res.RowsAffected()
+	if err == nil && tokenInserted(fieldsToInsert, bound) {
+		x.LastUpdate, err = c.readToken(ctx, tx, x)
+	}
+	if err != nil {
+		return &QueryResult{Result: res, Error: err}
+	}
+	outcome := UpsertUpdated
+	switch n {
+	case 0:
+		outcome = UpsertUnchanged
+	case 1:
+		outcome = UpsertInserted
+	}
+	return afterHook(ctx, tx, x, "AfterInsert", &QueryResult{Result: res, Outcome: outcome})
+}
+
+// dbUpsert inserts x or, when a primary or unique key already exists, updates
+// params.Update on the existing row (defaults to the inserted fields).
+func (c *Client) dbUpsert(ctx context.Context, tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult {
+	fieldsToUpdate := Fields
+	if params != nil && len(params.Update) > 0 {
+		fieldsToUpdate = params.Update
+	} else if params != nil && len(params.Insert) > 0 {
+		fieldsToUpdate = params.Insert
+	}
+	fieldsToUpdate, stamp := upsertUpdateFields(fieldsToUpdate, params)
+	assignments := upsertAssignments(fieldsToUpdate, stamp)
+	return c.dbUpsertCore(ctx, tx, x, params, "Upsert", "INSERT INTO", " ON DUPLICATE KEY UPDATE "+strings.Join(assignments, ", "))
+}
+
+func (x *Entity) DBUpsert(params *QueryParams) *QueryResult {
+	return defaultClient.dbUpsert(nil, nil, x, params)
+}
+func (x *Entity) DBUpsertCtx(ctx context.Context, params *QueryParams) *QueryResult {
+	return defaultClient.dbUpsert(ctx, nil, x, params)
+}
+func (x *Entity) DBUpsertTx(tx *sql.Tx, params *QueryParams) *QueryResult {
+	return defaultClient.dbUpsert(nil, tx, x, params)
+}
+func (x *Entity) DBUpsertCtxTx(ctx context.Context, tx *sql.Tx, params *QueryParams) *QueryResult {
+	return defaultClient.dbUpsert(ctx, tx, x, params)
+}
+func (c *Client) DBUpsert(x *Entity, params *QueryParams) *QueryResult {
+	return c.dbUpsert(nil, nil, x, params)
+}
+func (c *Client) DBUpsertCtx(ctx context.Context, x *Entity, params *QueryParams) *QueryResult {
+	return c.dbUpsert(ctx, nil, x, params)
+}
+func (c *Client) DBUpsertTx(tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult {
+	return c.dbUpsert(nil, tx, x, params)
+}
+func (c *Client) DBUpsertCtxTx(ctx context.Context, tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult {
+	return c.dbUpsert(ctx, tx, x, params)
+}
+
+func (c *Client) dbInsertIgnore(ctx context.Context, tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult {
+	return c.dbUpsertCore(ctx, tx, x, params, "InsertIgnore", "INSERT IGNORE INTO", "")
+}
+
+func (x *Entity) DBInsertIgnore(params *QueryParams) *QueryResult {
+	return defaultClient.dbInsertIgnore(nil, nil, x, params)
+}
+func (x *Entity) DBInsertIgnoreCtx(ctx context.Context, params *QueryParams) *QueryResult {
+	return defaultClient.dbInsertIgnore(ctx, nil, x, params)
+}
+func (x *Entity) DBInsertIgnoreTx(tx *sql.Tx, params *QueryParams) *QueryResult {
+	return defaultClient.dbInsertIgnore(nil, tx, x, params)
+}
+func (x *Entity) DBInsertIgnoreCtxTx(ctx context.Context, tx *sql.Tx, params *QueryParams) *QueryResult {
+	return defaultClient.dbInsertIgnore(ctx, tx, x, params)
+}
+func (c *Client) DBInsertIgnore(x *Entity, params *QueryParams) *QueryResult {
+	return c.dbInsertIgnore(nil, nil, x, params)
+}
+func (c *Client) DBInsertIgnoreCtx(ctx context.Context, x *Entity, params *QueryParams) *QueryResult {
+	return c.dbInsertIgnore(ctx, nil, x, params)
+}
+func (c *Client) DBInsertIgnoreTx(tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult {
+	return c.dbInsertIgnore(nil, tx, x, params)
+}
+func (c *Client) DBInsertIgnoreCtxTx(ctx context.Context, tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult {
+	return c.dbInsertIgnore(ctx, tx, x, params)
+}
+
+func (c *Client) dbReplace(ctx context.Context, tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult {
+	return c.dbUpsertCore(ctx, tx, x, params, "Replace", "REPLACE INTO", "")
+}
+
+func (x *Entity) DBReplace(params *QueryParams) *QueryResult {
+	return defaultClient.dbReplace(nil, nil, x, params)
+}
+func (x *Entity) DBReplaceCtx(ctx context.Context, params *QueryParams) *QueryResult {
+	return defaultClient.dbReplace(ctx, nil, x, params)
+}
+func (x *Entity) DBReplaceTx(tx *sql.Tx, params *QueryParams) *QueryResult {
+	return defaultClient.dbReplace(nil, tx, x, params)
+}
+func (x *Entity) DBReplaceCtxTx(ctx context.Context, tx *sql.Tx, params *QueryParams) *QueryResult {
+	return defaultClient.dbReplace(ctx, tx, x, params)
+}
+func (c *Client) DBReplace(x *Entity, params *QueryParams) *QueryResult {
+	return c.dbReplace(nil, nil, x, params)
+}
+func (c *Client) DBReplaceCtx(ctx context.Context, x *Entity, params *QueryParams) *QueryResult {
+	return c.dbReplace(ctx, nil, x, params)
+}
+func (c *Client) DBReplaceTx(tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult {
+	return c.dbReplace(nil, tx, x, params)
+}
+func (c *Client) DBReplaceCtxTx(ctx context.Context, tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult {
+	return c.dbReplace(ctx, tx, x, params)
+}
+
+// buildDeleteWhere defaults the WHERE clause of a delete to the primary key
+// of x when params specify neither Where nor Conditions.
+func buildDeleteWhere(x *Entity, params *QueryParams) (string, []any, error) {
+	whereFields := PrimaryKey
+	var conditions []*Condition
+	if params != nil {
+		conditions = params.Conditions
+		if len(params.Where) > 0 || len(conditions) > 0 {
+			whereFields = params.Where
+		}
+	}
+	return buildWhere(x, whereFields, conditions)
 }
 
-func (x *Entity) DBInsertCtxTx(ctx context.Context, tx *sql.Tx, params *QueryParams) *QueryResult {
-	fieldsToInsert := Fields
-	if params != nil && len(params.Insert) > 0 {
-		fieldsToInsert = params.Insert
+func (c *Client) dbDelete(ctx context.Context, tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult {
+	if err := runHook(ctx, tx, x, "BeforeDelete"); err != nil {
+		return &QueryResult{Error: err}
 	}
-	q := "INSERT INTO " + FQTN + " (" + strings.Join(GetQualifiedFields(fieldsToInsert), ", ") + ") VALUES (" + strings.Join(GetValuesPlaceholders(fieldsToInsert), ", ") + ")"
-	res, err := execCore(ctx, tx, q, x.GetFieldsValues(fieldsToInsert)...)
-	return &QueryResult{Result: res, Error: err}
+	where, args, err := buildDeleteWhere(x, params)
+	if err != nil {
+		return &QueryResult{Error: err}
+	}
+	q := "DELETE FROM " + FQTN + where
+	res, err := c.primary.execCore(ctx, tx, "Delete", q, args...)
+	return afterHook(ctx, tx, x, "AfterDelete", &QueryResult{Result: res, Error: err})
 }
 
 func (x *Entity) DBDelete(params *QueryParams) *QueryResult {
-	whereFields := Fields
-	if params != nil && len(params.Where) > 0 {
-		whereFields = params.Where
-	}
-	q := "DELETE FROM " + FQTN + " WHERE " + strings.Join(GetQualifiedFields(whereFields), " = ? AND ") + " = ?"
-	res, err := execCore(nil, nil, q, x.GetFieldsValues(whereFields)...)
-	return &QueryResult{Result: res, Error: err}
+	return defaultClient.dbDelete(nil, nil, x, params)
 }
-
 func (x *Entity) DBDeleteCtx(ctx context.Context, params *QueryParams) *QueryResult {
-	whereFields := Fields
-	if params != nil && len(params.Where) > 0 {
-		whereFields = params.Where
-	}
-	q := "DELETE FROM " + FQTN + " WHERE " + strings.Join(GetQualifiedFields(whereFields), " = ? AND ") + " = ?"
-	res, err := execCore(ctx, nil, q, x.GetFieldsValues(whereFields)...)
-	return &QueryResult{Result: res, Error: err}
+	return defaultClient.dbDelete(ctx, nil, x, params)
 }
-
 func (x *Entity) DBDeleteTx(tx *sql.Tx, params *QueryParams) *QueryResult {
-	whereFields := Fields
-	if params != nil && len(params.Where) > 0 {
-		whereFields = params.Where
-	}
-	q := "DELETE FROM " + FQTN + " WHERE " + strings.Join(GetQualifiedFields(whereFields), " = ? AND ") + " = ?"
-	res, err := execCore(nil, tx, q, x.GetFieldsValues(whereFields)...)
-	return &QueryResult{Result: res, Error: err}
+	return defaultClient.dbDelete(nil, tx, x, params)
 }
-
 func (x *Entity) DBDeleteCtxTx(ctx context.Context, tx *sql.Tx, params *QueryParams) *QueryResult {
-	whereFields := Fields
-	if params != nil && len(params.Where) > 0 {
-		whereFields = params.Where
-	}
-	q := "DELETE FROM " + FQTN + " WHERE " + strings.Join(GetQualifiedFields(whereFields), " = ? AND ") + " = ?"
-	res, err := execCore(ctx, tx, q, x.GetFieldsValues(whereFields)...)
-	return &QueryResult{Result: res, Error: err}
+	return defaultClient.dbDelete(ctx, tx, x, params)
+}
+func (c *Client) DBDelete(x *Entity, params *QueryParams) *QueryResult {
+	return c.dbDelete(nil, nil, x, params)
+}
+func (c *Client) DBDeleteCtx(ctx context.Context, x *Entity, params *QueryParams) *QueryResult {
+	return c.dbDelete(ctx, nil, x, params)
+}
+func (c *Client) DBDeleteTx(tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult {
+	return c.dbDelete(nil, tx, x, params)
+}
+func (c *Client) DBDeleteCtxTx(ctx context.Context, tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult {
+	return c.dbDelete(ctx, tx, x, params)
 }
 
-func (x *Entity) DBUpdate(params *QueryParams) *QueryResult {
-	if params == nil || len(params.Update) == 0 || len(params.Where) == 0 {
-		return &QueryResult{Error: errors.New("DBUpdate requires both params.Update and params.Where to be specified")}
+// dbDeleteReturning deletes the matching rows and returns params.Select
+// (defaults to all fields) of every removed row.
+func (c *Client) dbDeleteReturning(ctx context.Context, tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult {
+	if err := runHook(ctx, tx, x, "BeforeDelete"); err != nil {
+		return &QueryResult{Error: err}
 	}
-	q := "UPDATE " + FQTN + " SET " + strings.Join(GetQualifiedPlaceholders(params.Update), ", ") + " WHERE " + strings.Join(GetQualifiedFields(params.Where), " = ? AND ") + " = ?"
-	vals := append(x.GetFieldsValues(params.Update), x.GetFieldsValues(params.Where)...)
-	res, err := execCore(nil, nil, q, vals...)
-	return &QueryResult{Result: res, Error: err}
+	fieldsToReturn := Fields
+	if params != nil && len(params.Select) > 0 {
+		fieldsToReturn = params.Select
+	}
+	where, args, err := buildDeleteWhere(x, params)
+	if err != nil {
+		return &QueryResult{Error: err}
+	}
+	q := "DELETE FROM " + FQTN + where + " RETURNING " + strings.Join(GetQualifiedFields(fieldsToReturn), ", ")
+	entities, err := c.primary.queryCore(ctx, tx, "DeleteReturning", fieldsToReturn, q, args...)
+	track(params, fieldsToReturn, entities...)
+	return afterHook(ctx, tx, x, "AfterDelete", &QueryResult{Entities: entities, Error: err})
 }
 
-func (x *Entity) DBUpdateCtx(ctx context.Context, params *QueryParams) *QueryResult {
-	if params == nil || len(params.Update) == 0 || len(params.Where) == 0 {
-		return &QueryResult{Error: errors.New("DBUpdate requires both params.Update and params.Where to be specified")}
-	}
-	q := "UPDATE " + FQTN + " SET " + strings.Join(GetQualifiedPlaceholders(params.Update), ", ") + " WHERE " + strings.Join(GetQualifiedFields(params.Where), " = ? AND ") + " = ?"
-	vals := append(x.GetFieldsValues(params.Update), x.GetFieldsValues(params.Where)...)
-	res, err := execCore(ctx, nil, q, vals...)
-	return &QueryResult{Result: res, Error: err}
+func (x *Entity) DBDeleteReturning(params *QueryParams) *QueryResult {
+	return defaultClient.dbDeleteReturning(nil, nil, x, params)
+}
+func (x *Entity) DBDeleteReturningCtx(ctx context.Context, params *QueryParams) *QueryResult {
+	return defaultClient.dbDeleteReturning(ctx, nil, x, params)
+}
+func (x *Entity) DBDeleteReturningTx(tx *sql.Tx, params *QueryParams) *QueryResult {
+	return defaultClient.dbDeleteReturning(nil, tx, x, params)
+}
+func (x *Entity) DBDeleteReturningCtxTx(ctx context.Context, tx *sql.Tx, params *QueryParams) *QueryResult {
+	return defaultClient.dbDeleteReturning(ctx, tx, x, params)
+}
+func (c *Client) DBDeleteReturning(x *Entity, params *QueryParams) *QueryResult {
+	return c.dbDeleteReturning(nil, nil, x, params)
+}
+func (c *Client) DBDeleteReturningCtx(ctx context.Context, x *Entity, params *QueryParams) *QueryResult {
+	return c.dbDeleteReturning(ctx, nil, x, params)
+}
+func (c *Client) DBDeleteReturningTx(tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult {
+	return c.dbDeleteReturning(nil, tx, x, params)
+}
+func (c *Client) DBDeleteReturningCtxTx(ctx context.Context, tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult {
+	return c.dbDeleteReturning(ctx, tx, x, params)
 }
 
-func (x *Entity) DBUpdateTx(tx *sql.Tx, params *QueryParams) *QueryResult {
-	if params == nil || len(params.Update) == 0 || len(params.Where) == 0 {
-		return &QueryResult{Error: errors.New("DBUpdate requires both params.Update and params.Where to be specified")}
+// dbUpdate writes params.Update to the rows matching params.Where and
+// params.Conditions. It checks ConcurrencyToken only for DBUpdateByPK and
+// DBUpdateChanged, a plain DBUpdate may match many rows and ignores it.
+func (c *Client) dbUpdate(ctx context.Context, tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult {
+	if params == nil || !params.hooked {
+		if err := runHook(ctx, tx, x, "BeforeUpdate"); err != nil {
+			return &QueryResult{Error: err}
+		}
 	}
-	q := "UPDATE " + FQTN + " SET " + strings.Join(GetQualifiedPlaceholders(params.Update), ", ") + " WHERE " + strings.Join(GetQualifiedFields(params.Where), " = ? AND ") + " = ?"
-	vals := append(x.GetFieldsValues(params.Update), x.GetFieldsValues(params.Where)...)
-	res, err := execCore(nil, tx, q, vals...)
-	return &QueryResult{Result: res, Error: err}
+	if params == nil || len(params.Update) == 0 || (len(params.Where) == 0 && len(params.Conditions) == 0) {
+		return &QueryResult{Error: errors.New("DBUpdate requires params.Update and either params.Where or params.Conditions to be specified")}
+	}
+	if ValidateOnWrite {
+		if err := x.ValidateFields(params.Update...); err != nil {
+			return &QueryResult{Error: err}
+		}
+	}
+	where, whereArgs, err := buildWhere(x, params.Where, params.Conditions)
+	if err != nil {
+		return &QueryResult{Error: err}
+	}
+	set := GetQualifiedPlaceholders(params.Update)
+	vals := x.GetFieldsValues(params.Update)
+	var old, next string
+	if params.token {
+		old, next = tokenUpdate(x, params.Update)
+	}
+	if old != "" {
+		set, vals = stampSet(set, vals, next)
+		where += " AND " + GetQualifiedCondition(ConcurrencyToken)
+		whereArgs = append(whereArgs, old)
+	} else if stamp, now := updateStamp(x, params.Update); stamp {
+		set, vals = stampSet(set, vals, now)
+	}
+	q := "UPDATE " + FQTN + " SET " + strings.Join(set, ", ") + where
+	vals = append(vals, whereArgs...)
+	res, err := c.primary.execCore(ctx, tx, "Update", q, vals...)
+	if err == nil && old != "" {
+		var n int64
+		if n, err = res.RowsAffected(); err == nil && n == 0 {
+			err = ErrStaleEntity
+		} else if err == nil && next == "" {
+			next, err = c.readToken(ctx, tx, x)
+		}
+		if err == nil {
+			x.LastUpdate = next
+		}
+	}
+	return afterHook(ctx, tx, x, "AfterUpdate", &QueryResult{Result: res, Error: err})
 }
 
+func (x *Entity) DBUpdate(params *QueryParams) *QueryResult {
+	return defaultClient.dbUpdate(nil, nil, x, params)
+}
+func (x *Entity) DBUpdateCtx(ctx context.Context, params *QueryParams) *QueryResult {
+	return defaultClient.dbUpdate(ctx, nil, x, params)
+}
+func (x *Entity) DBUpdateTx(tx *sql.Tx, params *QueryParams) *QueryResult {
+	return defaultClient.dbUpdate(nil, tx, x, params)
+}
 func (x *Entity) DBUpdateCtxTx(ctx context.Context, tx *sql.Tx, params *QueryParams) *QueryResult {
-	if params == nil || len(params.Update) == 0 || len(params.Where) == 0 {
-		return &QueryResult{Error: errors.New("DBUpdate requires both params.Update and params.Where to be specified")}
-	}
-	q := "UPDATE " + FQTN + " SET " + strings.Join(GetQualifiedPlaceholders(params.Update), ", ") + " WHERE " + strings.Join(GetQualifiedFields(params.Where), " = ? AND ") + " = ?"
-	vals := append(x.GetFieldsValues(params.Update), x.GetFieldsValues(params.Where)...)
-	res, err := execCore(ctx, tx, q, vals...)
-	return &QueryResult{Result: res, Error: err}
+	return defaultClient.dbUpdate(ctx, tx, x, params)
+}
+func (c *Client) DBUpdate(x *Entity, params *QueryParams) *QueryResult {
+	return c.dbUpdate(nil, nil, x, params)
+}
+func (c *Client) DBUpdateCtx(ctx context.Context, x *Entity, params *QueryParams) *QueryResult {
+	return c.dbUpdate(ctx, nil, x, params)
+}
+func (c *Client) DBUpdateTx(tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult {
+	return c.dbUpdate(nil, tx, x, params)
+}
+func (c *Client) DBUpdateCtxTx(ctx context.Context, tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult {
+	return c.dbUpdate(ctx, tx, x, params)
 }
 
-func (x *Entity) DBSelect(params *QueryParams) *QueryResult {
+func (c *Client) dbSelect(ctx context.Context, tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult {
 	fieldsToSelect := Fields
-	if params != nil && len(params.Select) > 0 {
-		fieldsToSelect = params.Select
+	var whereFields []string
+	var conditions []*Condition
+	if params != nil {
+		if len(params.Select) > 0 {
+			fieldsToSelect = params.Select
+		}
+		whereFields = params.Where
+		conditions = params.Conditions
 	}
-	q := "SELECT " + strings.Join(GetQualifiedFields(fieldsToSelect), ", ") + " FROM " + FQTN
-	var args []any
-	if params != nil && len(params.Where) > 0 {
-		q += " WHERE " + strings.Join(GetQualifiedFields(params.Where), " = ? AND ") + " = ?"
-		args = x.GetFieldsValues(params.Where)
+	where, args, err := buildWhere(x, whereFields, conditions)
+	if err != nil {
+		return &QueryResult{Error: err}
 	}
-	entities, err := queryCore(nil, nil, fieldsToSelect, q, args...)
+	tail, tailArgs, err := buildOrderLimit(params)
+	if err != nil {
+		return &QueryResult{Error: err}
+	}
+	q := "SELECT " + strings.Join(GetQualifiedFields(fieldsToSelect), ", ") + " FROM " + FQTN + where + tail
+	entities, err := c.reader(ctx, tx).queryCore(ctx, tx, "Select", fieldsToSelect, q, append(args, tailArgs...)...)
+	track(params, fieldsToSelect, entities...)
 	return &QueryResult{Entities: entities, Error: err}
 }
 
+func (x *Entity) DBSelect(params *QueryParams) *QueryResult {
+	return defaultClient.dbSelect(nil, nil, x, params)
+}
 func (x *Entity) DBSelectCtx(ctx context.Context, params *QueryParams) *QueryResult {
-	fieldsToSelect := Fields
-	if params != nil && len(params.Select) > 0 {
-		fieldsToSelect = params.Select
-	}
-	q := "SELECT " + strings.Join(GetQualifiedFields(fieldsToSelect), ", ") + " FROM " + FQTN
-	var args []any
-	if params != nil && len(params.Where) > 0 {
-		q += " WHERE " + strings.Join(GetQualifiedFields(params.Where), " = ? AND ") + " = ?"
-		args = x.GetFieldsValues(params.Where)
-	}
-	entities, err := queryCore(ctx, nil, fieldsToSelect, q, args...)
-	return &QueryResult{Entities: entities, Error: err}
+	return defaultClient.dbSelect(ctx, nil, x, params)
 }
-
 func (x *Entity) DBSelectTx(tx *sql.Tx, params *QueryParams) *QueryResult {
-	fieldsToSelect := Fields
-	if params != nil && len(params.Select) > 0 {
-		fieldsToSelect = params.Select
-	}
-	q := "SELECT " + strings.Join(GetQualifiedFields(fieldsToSelect), ", ") + " FROM " + FQTN
-	var args []any
-	if params != nil && len(params.Where) > 0 {
-		q += " WHERE " + strings.Join(GetQualifiedFields(params.Where), " = ? AND ") + " = ?"
-		args = x.GetFieldsValues(params.Where)
-	}
-	entities, err := queryCore(nil, tx, fieldsToSelect, q, args...)
-	return &QueryResult{Entities: entities, Error: err}
+	return defaultClient.dbSelect(nil, tx, x, params)
 }
-
 func (x *Entity) DBSelectCtxTx(ctx context.Context, tx *sql.Tx, params *QueryParams) *QueryResult {
+	return defaultClient.dbSelect(ctx, tx, x, params)
+}
+func (c *Client) DBSelect(x *Entity, params *QueryParams) *QueryResult {
+	return c.dbSelect(nil, nil, x, params)
+}
+func (c *Client) DBSelectCtx(ctx context.Context, x *Entity, params *QueryParams) *QueryResult {
+	return c.dbSelect(ctx, nil, x, params)
+}
+func (c *Client) DBSelectTx(tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult {
+	return c.dbSelect(nil, tx, x, params)
+}
+func (c *Client) DBSelectCtxTx(ctx context.Context, tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult {
+	return c.dbSelect(ctx, tx, x, params)
+}
+
+// dbSelectAll reads every row of the table. Only the Select, OrderBy, Limit
+// and Offset parts of params are used.
+func (c *Client) dbSelectAll(ctx context.Context, tx *sql.Tx, params *QueryParams) *QueryResult {
 	fieldsToSelect := Fields
 	if params != nil && len(params.Select) > 0 {
 		fieldsToSelect = params.Select
 	}
-	q := "SELECT " + strings.Join(GetQualifiedFields(fieldsToSelect), ", ") + " FROM " + FQTN
-	var args []any
-	if params != nil && len(params.Where) > 0 {
-		q += " WHERE " + strings.Join(GetQualifiedFields(params.Where), " = ? AND ") + " = ?"
-		args = x.GetFieldsValues(params.Where)
+	tail, args, err := buildOrderLimit(params)
+	if err != nil {
+		return &QueryResult{Error: err}
 	}
-	entities, err := queryCore(ctx, tx, fieldsToSelect, q, args...)
+	q := "SELECT " + strings.Join(GetQualifiedFields(fieldsToSelect), ", ") + " FROM " + FQTN + tail
+	entities, err := c.reader(ctx, tx).queryCore(ctx, tx, "SelectAll", fieldsToSelect, q, args...)
+	track(params, fieldsToSelect, entities...)
 	return &QueryResult{Entities: entities, Error: err}
 }
 
-func DBSelectAll() *QueryResult {
-	q := "SELECT " + strings.Join(GetQualifiedFields(Fields), ", ") + " FROM " + FQTN
-	entities, err := queryCore(nil, nil, Fields, q)
-	return &QueryResult{Entities: entities, Error: err}
+func DBSelectAll(params *QueryParams) *QueryResult {
+	return defaultClient.dbSelectAll(nil, nil, params)
 }
-
-func DBSelectAllCtx(ctx context.Context) *QueryResult {
-	q := "SELECT " + strings.Join(GetQualifiedFields(Fields), ", ") + " FROM " + FQTN
-	entities, err := queryCore(ctx, nil, Fields, q)
-	return &QueryResult{Entities: entities, Error: err}
+func DBSelectAllCtx(ctx context.Context, params *QueryParams) *QueryResult {
+	return defaultClient.dbSelectAll(ctx, nil, params)
 }
-
-func DBSelectAllTx(tx *sql.Tx) *QueryResult {
-	q := "SELECT " + strings.Join(GetQualifiedFields(Fields), ", ") + " FROM " + FQTN
-	entities, err := queryCore(nil, tx, Fields, q)
-	return &QueryResult{Entities: entities, Error: err}
+func DBSelectAllTx(tx *sql.Tx, params *QueryParams) *QueryResult {
+	return defaultClient.dbSelectAll(nil, tx, params)
 }
-
-func DBSelectAllCtxTx(ctx context.Context, tx *sql.Tx) *QueryResult {
-	q := "SELECT " + strings.Join(GetQualifiedFields(Fields), ", ") + " FROM " + FQTN
-	entities, err := queryCore(ctx, tx, Fields, q)
-	return &QueryResult{Entities: entities, Error: err}
+func DBSelectAllCtxTx(ctx context.Context, tx *sql.Tx, params *QueryParams) *QueryResult {
+	return defaultClient.dbSelectAll(ctx, tx, params)
+}
+func (c *Client) DBSelectAll(params *QueryParams) *QueryResult {
+	return c.dbSelectAll(nil, nil, params)
+}
+func (c *Client) DBSelectAllCtx(ctx context.Context, params *QueryParams) *QueryResult {
+	return c.dbSelectAll(ctx, nil, params)
+}
+func (c *Client) DBSelectAllTx(tx *sql.Tx, params *QueryParams) *QueryResult {
+	return c.dbSelectAll(nil, tx, params)
+}
+func (c *Client) DBSelectAllCtxTx(ctx context.Context, tx *sql.Tx, params *QueryParams) *QueryResult {
+	return c.dbSelectAll(ctx, tx, params)
 }
 
-func (x *Entity) DBExists(params *QueryParams) *QueryResult {
+func (c *Client) dbExists(ctx context.Context, tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult {
 	if params == nil {
 		return &QueryResult{Error: errors.New("DBExists requires params to be specified"), Exists: false}
 	}
@@ -669,35 +2564,16 @@ func (x *Entity) DBExists(params *QueryParams) *QueryResult {
 		fieldsToSelect = Fields
 	}
 	whereFields := params.Where
-	if len(whereFields) == 0 {
+	if len(whereFields) == 0 && len(params.Conditions) == 0 {
 		whereFields = Fields
 	}
-	q := "SELECT " + strings.Join(GetQualifiedFields(fieldsToSelect), ", ") + " FROM " + FQTN + " WHERE " + strings.Join(GetQualifiedFields(whereFields), " = ? AND ") + " = ? LIMIT 1"
-	entities, err := queryCore(nil, nil, fieldsToSelect, q, x.GetFieldsValues(whereFields)...)
+	where, args, err := buildWhere(x, whereFields, params.Conditions)
 	if err != nil {
 		return &QueryResult{Error: err, Exists: false}
 	}
-	if len(entities) == 0 {
-		return &QueryResult{Exists: false}
-	}
-	*x = *entities[0]
-	return &QueryResult{Exists: true}
-}
-
-func (x *Entity) DBExistsCtx(ctx context.Context, params *QueryParams) *QueryResult {
-	if params == nil {
-		return &QueryResult{Error: errors.New("DBExists requires params to be specified"), Exists: false}
-	}
-	fieldsToSelect := params.Select
-	if len(fieldsToSelect) == 0 {
-		fieldsToSelect = Fields
-	}
-	whereFields := params.Where
-	if len(whereFields) == 0 {
-		whereFields = Fields
-	}
-	q := "SELECT " + strings.Join(GetQualifiedFields(fieldsToSelect), ", ") + " FROM " + FQTN + " WHERE " + strings.Join(GetQualifiedFields(whereFields), " = ? AND ") + " = ? LIMIT 1"
-	entities, err := queryCore(ctx, nil, fieldsToSelect, q, x.GetFieldsValues(whereFields)...)
+	q := "SELECT " + strings.Join(GetQualifiedFields(fieldsToSelect), ", ") + " FROM " + FQTN + where + " LIMIT 1"
+	entities, err := c.reader(ctx, tx).queryCore(ctx, tx, "Exists", fieldsToSelect, q, args...)
+	track(params, fieldsToSelect, entities...)
 	if err != nil {
 		return &QueryResult{Error: err, Exists: false}
 	}
@@ -708,71 +2584,318 @@ func (x *Entity) DBExistsCtx(ctx context.Context, params *QueryParams) *QueryRes
 	return &QueryResult{Exists: true}
 }
 
+func (x *Entity) DBExists(params *QueryParams) *QueryResult {
+	return defaultClient.dbExists(nil, nil, x, params)
+}
+func (x *Entity) DBExistsCtx(ctx context.Context, params *QueryParams) *QueryResult {
+	return defaultClient.dbExists(ctx, nil, x, params)
+}
 func (x *Entity) DBExistsTx(tx *sql.Tx, params *QueryParams) *QueryResult {
-	if params == nil {
-		return &QueryResult{Error: errors.New("DBExists requires params to be specified"), Exists: false}
-	}
-	fieldsToSelect := params.Select
-	if len(fieldsToSelect) == 0 {
-		fieldsToSelect = Fields
+	return defaultClient.dbExists(nil, tx, x, params)
+}
+func (x *Entity) DBExistsCtxTx(ctx context.Context, tx *sql.Tx, params *QueryParams) *QueryResult {
+	return defaultClient.dbExists(ctx, tx, x, params)
+}
+func (c *Client) DBExists(x *Entity, params *QueryParams) *QueryResult {
+	return c.dbExists(nil, nil, x, params)
+}
+func (c *Client) DBExistsCtx(ctx context.Context, x *Entity, params *QueryParams) *QueryResult {
+	return c.dbExists(ctx, nil, x, params)
+}
+func (c *Client) DBExistsTx(tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult {
+	return c.dbExists(nil, tx, x, params)
+}
+func (c *Client) DBExistsCtxTx(ctx context.Context, tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult {
+	return c.dbExists(ctx, tx, x, params)
+}
+
+// dbSelectPage reads one page of at most params.Limit rows ordered by
+// params.OrderBy, which must include a unique key. Pass the NextCursor of the
+// previous page to continue; an empty NextCursor means there are no more rows.
+func (c *Client) dbSelectPage(ctx context.Context, tx *sql.Tx, x *Entity, params *QueryParams, cursor string) *QueryResult {
+	if params == nil || len(params.OrderBy) == 0 || params.Limit <= 0 {
+		return &QueryResult{Error: errors.New("DBSelectPage requires params.OrderBy and a positive params.Limit to be specified")}
+	}
+	if params.Offset != 0 {
+		return &QueryResult{Error: errors.New("DBSelectPage does not support params.Offset")}
+	}
+	if err := checkPageOrder(params.OrderBy); err != nil {
+		return &QueryResult{Error: err}
+	}
+	if len(params.Select) > 0 {
+		for _, o := range params.OrderBy {
+			found := false
+			for _, field := range params.Select {
+				if field == o.Field {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return &QueryResult{Error: errors.New("DBSelectPage requires params.Select to include OrderBy field: " + o.Field)}
+			}
+		}
 	}
-	whereFields := params.Where
-	if len(whereFields) == 0 {
-		whereFields = Fields
+	page := *params
+	page.Limit = params.Limit + 1
+	if cursor != "" {
+		values, err := decodeCursor(params.OrderBy, cursor)
+		if err != nil {
+			return &QueryResult{Error: err}
+		}
+		page.Conditions = append(append(make([]*Condition, 0, len(params.Conditions)+1), params.Conditions...), seekCondition(params.OrderBy, values))
 	}
-	q := "SELECT " + strings.Join(GetQualifiedFields(fieldsToSelect), ", ") + " FROM " + FQTN + " WHERE " + strings.Join(GetQualifiedFields(whereFields), " = ? AND ") + " = ? LIMIT 1"
-	entities, err := queryCore(nil, tx, fieldsToSelect, q, x.GetFieldsValues(whereFields)...)
-	if err != nil {
-		return &QueryResult{Error: err, Exists: false}
+	result := c.dbSelect(ctx, tx, x, &page)
+	if result.Error != nil || len(result.Entities) <= params.Limit {
+		return result
 	}
-	if len(entities) == 0 {
-		return &QueryResult{Exists: false}
+	result.Entities = result.Entities[:params.Limit]
+	result.NextCursor, result.Error = encodeCursor(params.OrderBy, result.Entities[params.Limit-1])
+	return result
+}
+
+func (x *Entity) DBSelectPage(params *QueryParams, cursor string) *QueryResult {
+	return defaultClient.dbSelectPage(nil, nil, x, params, cursor)
+}
+func (x *Entity) DBSelectPageCtx(ctx context.Context, params *QueryParams, cursor string) *QueryResult {
+	return defaultClient.dbSelectPage(ctx, nil, x, params, cursor)
+}
+func (x *Entity) DBSelectPageTx(tx *sql.Tx, params *QueryParams, cursor string) *QueryResult {
+	return defaultClient.dbSelectPage(nil, tx, x, params, cursor)
+}
+func (x *Entity) DBSelectPageCtxTx(ctx context.Context, tx *sql.Tx, params *QueryParams, cursor string) *QueryResult {
+	return defaultClient.dbSelectPage(ctx, tx, x, params, cursor)
+}
+func (c *Client) DBSelectPage(x *Entity, params *QueryParams, cursor string) *QueryResult {
+	return c.dbSelectPage(nil, nil, x, params, cursor)
+}
+func (c *Client) DBSelectPageCtx(ctx context.Context, x *Entity, params *QueryParams, cursor string) *QueryResult {
+	return c.dbSelectPage(ctx, nil, x, params, cursor)
+}
+func (c *Client) DBSelectPageTx(tx *sql.Tx, x *Entity, params *QueryParams, cursor string) *QueryResult {
+	return c.dbSelectPage(nil, tx, x, params, cursor)
+}
+func (c *Client) DBSelectPageCtxTx(ctx context.Context, tx *sql.Tx, x *Entity, params *QueryParams, cursor string) *QueryResult {
+	return c.dbSelectPage(ctx, tx, x, params, cursor)
+}
+
+func (c *Client) dbGetByKey(ctx context.Context, tx *sql.Tx, key []string, values ...any) *QueryResult {
+	q := "SELECT " + strings.Join(GetQualifiedFields(Fields), ", ") + " FROM " + FQTN + " WHERE " + strings.Join(GetQualifiedConditions(key), " AND ")
+	entity, err := c.reader(ctx, tx).queryOneCore(ctx, tx, "GetByKey", Fields, q, values...)
+	return &QueryResult{Entity: entity, Exists: entity != nil, Error: err}
+}
+
+func (c *Client) dbGetByPK(ctx context.Context, tx *sql.Tx, uuid string) *QueryResult {
+	return c.dbGetByKey(ctx, tx, PrimaryKey, uuid)
+}
+
+func DBGetByPK(uuid string) *QueryResult {
+	return defaultClient.dbGetByPK(nil, nil, uuid)
+}
+func DBGetByPKCtx(ctx context.Context, uuid string) *QueryResult {
+	return defaultClient.dbGetByPK(ctx, nil, uuid)
+}
+func DBGetByPKTx(tx *sql.Tx, uuid string) *QueryResult {
+	return defaultClient.dbGetByPK(nil, tx, uuid)
+}
+func DBGetByPKCtxTx(ctx context.Context, tx *sql.Tx, uuid string) *QueryResult {
+	return defaultClient.dbGetByPK(ctx, tx, uuid)
+}
+func (c *Client) DBGetByPK(uuid string) *QueryResult {
+	return c.dbGetByPK(nil, nil, uuid)
+}
+func (c *Client) DBGetByPKCtx(ctx context.Context, uuid string) *QueryResult {
+	return c.dbGetByPK(ctx, nil, uuid)
+}
+func (c *Client) DBGetByPKTx(tx *sql.Tx, uuid string) *QueryResult {
+	return c.dbGetByPK(nil, tx, uuid)
+}
+func (c *Client) DBGetByPKCtxTx(ctx context.Context, tx *sql.Tx, uuid string) *QueryResult {
+	return c.dbGetByPK(ctx, tx, uuid)
+}
+
+// dbUpdateByPK updates params.Update (defaults to every non-key field but the
+// timestamp, token and soft delete columns) of the row identified by the
+// primary key of x.
+func (c *Client) dbUpdateByPK(ctx context.Context, tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult {
+	p := &QueryParams{Update: nonPrimaryKeyFields, Where: PrimaryKey, token: true}
+	if params != nil {
+		if len(params.Update) > 0 {
+			p.Update = params.Update
+		}
+		p.Conditions = params.Conditions
 	}
-	*x = *entities[0]
-	return &QueryResult{Exists: true}
+	return c.dbUpdate(ctx, tx, x, p)
 }
 
-func (x *Entity) DBExistsCtxTx(ctx context.Context, tx *sql.Tx, params *QueryParams) *QueryResult {
-	if params == nil {
-		return &QueryResult{Error: errors.New("DBExists requires params to be specified"), Exists: false}
+func (x *Entity) DBUpdateByPK(params *QueryParams) *QueryResult {
+	return defaultClient.dbUpdateByPK(nil, nil, x, params)
+}
+func (x *Entity) DBUpdateByPKCtx(ctx context.Context, params *QueryParams) *QueryResult {
+	return defaultClient.dbUpdateByPK(ctx, nil, x, params)
+}
+func (x *Entity) DBUpdateByPKTx(tx *sql.Tx, params *QueryParams) *QueryResult {
+	return defaultClient.dbUpdateByPK(nil, tx, x, params)
+}
+func (x *Entity) DBUpdateByPKCtxTx(ctx context.Context, tx *sql.Tx, params *QueryParams) *QueryResult {
+	return defaultClient.dbUpdateByPK(ctx, tx, x, params)
+}
+func (c *Client) DBUpdateByPK(x *Entity, params *QueryParams) *QueryResult {
+	return c.dbUpdateByPK(nil, nil, x, params)
+}
+func (c *Client) DBUpdateByPKCtx(ctx context.Context, x *Entity, params *QueryParams) *QueryResult {
+	return c.dbUpdateByPK(ctx, nil, x, params)
+}
+func (c *Client) DBUpdateByPKTx(tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult {
+	return c.dbUpdateByPK(nil, tx, x, params)
+}
+func (c *Client) DBUpdateByPKCtxTx(ctx context.Context, tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult {
+	return c.dbUpdateByPK(ctx, tx, x, params)
+}
+
+// dbUpdateChanged updates only the fields of x that changed since it was
+// loaded, locating the row by the primary key it was loaded with, and sends
+// nothing when no field changed. params may add Conditions.
+func (c *Client) dbUpdateChanged(ctx context.Context, tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult {
+	if x.loaded == nil {
+		return &QueryResult{Error: errors.New("DBUpdateChanged requires an entity loaded from the database")}
 	}
-	fieldsToSelect := params.Select
-	if len(fieldsToSelect) == 0 {
-		fieldsToSelect = Fields
+	if !x.loaded.hasPrimaryKey() {
+		return &QueryResult{Error: errors.New("DBUpdateChanged requires the primary key to have been loaded")}
 	}
-	whereFields := params.Where
-	if len(whereFields) == 0 {
-		whereFields = Fields
+	// The hook runs first so that the fields it sets are part of changed.
+	if err := runHook(ctx, tx, x, "BeforeUpdate"); err != nil {
+		return &QueryResult{Error: err}
 	}
-	q := "SELECT " + strings.Join(GetQualifiedFields(fieldsToSelect), ", ") + " FROM " + FQTN + " WHERE " + strings.Join(GetQualifiedFields(whereFields), " = ? AND ") + " = ? LIMIT 1"
-	entities, err := queryCore(ctx, tx, fieldsToSelect, q, x.GetFieldsValues(whereFields)...)
-	if err != nil {
-		return &QueryResult{Error: err, Exists: false}
+	changed := x.ChangedFields()
+	if len(changed) == 0 {
+		return &QueryResult{Result: driver.RowsAffected(0)}
 	}
-	if len(entities) == 0 {
-		return &QueryResult{Exists: false}
+	p := &QueryParams{Update: changed, token: true, hooked: true}
+	for _, field := range PrimaryKey {
+		p.Conditions = append(p.Conditions, Eq(field, x.loaded.entity.GetFieldValue(field)))
 	}
-	*x = *entities[0]
-	return &QueryResult{Exists: true}
+	if params != nil {
+		p.Conditions = append(p.Conditions, params.Conditions...)
+	}
+	result := c.dbUpdate(ctx, tx, x, p)
+	if result.Error == nil {
+		x.takeSnapshot(x.loaded.fields)
+	}
+	return result
 }
 
-func QueryGetAllAnimals() *QueryResult {
+func (x *Entity) DBUpdateChanged(params *QueryParams) *QueryResult {
+	return defaultClient.dbUpdateChanged(nil, nil, x, params)
+}
+func (x *Entity) DBUpdateChangedCtx(ctx context.Context, params *QueryParams) *QueryResult {
+	return defaultClient.dbUpdateChanged(ctx, nil, x, params)
+}
+func (x *Entity) DBUpdateChangedTx(tx *sql.Tx, params *QueryParams) *QueryResult {
+	return defaultClient.dbUpdateChanged(nil, tx, x, params)
+}
+func (x *Entity) DBUpdateChangedCtxTx(ctx context.Context, tx *sql.Tx, params *QueryParams) *QueryResult {
+	return defaultClient.dbUpdateChanged(ctx, tx, x, params)
+}
+func (c *Client) DBUpdateChanged(x *Entity, params *QueryParams) *QueryResult {
+	return c.dbUpdateChanged(nil, nil, x, params)
+}
+func (c *Client) DBUpdateChangedCtx(ctx context.Context, x *Entity, params *QueryParams) *QueryResult {
+	return c.dbUpdateChanged(ctx, nil, x, params)
+}
+func (c *Client) DBUpdateChangedTx(tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult {
+	return c.dbUpdateChanged(nil, tx, x, params)
+}
+func (c *Client) DBUpdateChangedCtxTx(ctx context.Context, tx *sql.Tx, x *Entity, params *QueryParams) *QueryResult {
+	return c.dbUpdateChanged(ctx, tx, x, params)
+}
+
+func (c *Client) dbDeleteByPK(ctx context.Context, tx *sql.Tx, x *Entity) *QueryResult {
+	return c.dbDelete(ctx, tx, x, &QueryParams{Where: PrimaryKey})
+}
+
+func (x *Entity) DBDeleteByPK() *QueryResult {
+	return defaultClient.dbDeleteByPK(nil, nil, x)
+}
+func (x *Entity) DBDeleteByPKCtx(ctx context.Context) *QueryResult {
+	return defaultClient.dbDeleteByPK(ctx, nil, x)
+}
+func (x *Entity) DBDeleteByPKTx(tx *sql.Tx) *QueryResult {
+	return defaultClient.dbDeleteByPK(nil, tx, x)
+}
+func (x *Entity) DBDeleteByPKCtxTx(ctx context.Context, tx *sql.Tx) *QueryResult {
+	return defaultClient.dbDeleteByPK(ctx, tx, x)
+}
+func (c *Client) DBDeleteByPK(x *Entity) *QueryResult {
+	return c.dbDeleteByPK(nil, nil, x)
+}
+func (c *Client) DBDeleteByPKCtx(ctx context.Context, x *Entity) *QueryResult {
+	return c.dbDeleteByPK(ctx, nil, x)
+}
+func (c *Client) DBDeleteByPKTx(tx *sql.Tx, x *Entity) *QueryResult {
+	return c.dbDeleteByPK(nil, tx, x)
+}
+func (c *Client) DBDeleteByPKCtxTx(ctx context.Context, tx *sql.Tx, x *Entity) *QueryResult {
+	return c.dbDeleteByPK(ctx, tx, x)
+}
+
+// dbReload refreshes every field of x from the row identified by its primary key.
+func (c *Client) dbReload(ctx context.Context, tx *sql.Tx, x *Entity) *QueryResult {
+	return c.dbExists(ctx, tx, x, &QueryParams{Select: Fields, Where: PrimaryKey, Track: x.loaded != nil})
+}
+
+func (x *Entity) DBReload() *QueryResult {
+	return defaultClient.dbReload(nil, nil, x)
+}
+func (x *Entity) DBReloadCtx(ctx context.Context) *QueryResult {
+	return defaultClient.dbReload(ctx, nil, x)
+}
+func (x *Entity) DBReloadTx(tx *sql.Tx) *QueryResult {
+	return defaultClient.dbReload(nil, tx, x)
+}
+func (x *Entity) DBReloadCtxTx(ctx context.Context, tx *sql.Tx) *QueryResult {
+	return defaultClient.dbReload(ctx, tx, x)
+}
+func (c *Client) DBReload(x *Entity) *QueryResult {
+	return c.dbReload(nil, nil, x)
+}
+func (c *Client) DBReloadCtx(ctx context.Context, x *Entity) *QueryResult {
+	return c.dbReload(ctx, nil, x)
+}
+func (c *Client) DBReloadTx(tx *sql.Tx, x *Entity) *QueryResult {
+	return c.dbReload(nil, tx, x)
+}
+func (c *Client) DBReloadCtxTx(ctx context.Context, tx *sql.Tx, x *Entity) *QueryResult {
+	return c.dbReload(ctx, tx, x)
+}
+
+func (c *Client) queryGetAllAnimals(ctx context.Context, tx *sql.Tx) *QueryResult {
 	q := queries["GetAllAnimals"]
-	entities, err := queryCore(nil, nil, []string{FieldAnimal, FieldBigNumber}, q.Query)
+	entities, err := c.reader(ctx, tx).queryCore(ctx, tx, "GetAllAnimals", []string{FieldAnimal, FieldBigNumber}, q.Query)
 	return &QueryResult{Entities: entities, Error: err}
 }
+
+func QueryGetAllAnimals() *QueryResult {
+	return defaultClient.queryGetAllAnimals(nil, nil)
+}
 func QueryGetAllAnimalsCtx(ctx context.Context) *QueryResult {
-	q := queries["GetAllAnimals"]
-	entities, err := queryCore(ctx, nil, []string{FieldAnimal, FieldBigNumber}, q.Query)
-	return &QueryResult{Entities: entities, Error: err}
+	return defaultClient.queryGetAllAnimals(ctx, nil)
 }
 func QueryGetAllAnimalsTx(tx *sql.Tx) *QueryResult {
-	q := queries["GetAllAnimals"]
-	entities, err := queryCore(nil, tx, []string{FieldAnimal, FieldBigNumber}, q.Query)
-	return &QueryResult{Entities: entities, Error: err}
+	return defaultClient.queryGetAllAnimals(nil, tx)
 }
 func QueryGetAllAnimalsCtxTx(ctx context.Context, tx *sql.Tx) *QueryResult {
-	q := queries["GetAllAnimals"]
-	entities, err := queryCore(ctx, tx, []string{FieldAnimal, FieldBigNumber}, q.Query)
-	return &QueryResult{Entities: entities, Error: err}
+	return defaultClient.queryGetAllAnimals(ctx, tx)
+}
+func (c *Client) QueryGetAllAnimals() *QueryResult {
+	return c.queryGetAllAnimals(nil, nil)
+}
+func (c *Client) QueryGetAllAnimalsCtx(ctx context.Context) *QueryResult {
+	return c.queryGetAllAnimals(ctx, nil)
+}
+func (c *Client) QueryGetAllAnimalsTx(tx *sql.Tx) *QueryResult {
+	return c.queryGetAllAnimals(nil, tx)
+}
+func (c *Client) QueryGetAllAnimalsCtxTx(ctx context.Context, tx *sql.Tx) *QueryResult {
+	return c.queryGetAllAnimals(ctx, tx)
 }
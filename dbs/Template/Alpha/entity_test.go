@@ -1,8 +1,13 @@
 package Alpha
 
 import (
+	"context"
 	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/rah-0/testmark/testutil"
@@ -15,6 +20,7 @@ import (
 )
 
 var (
+	dsn   string
 	c     *sql.DB
 	cXorm *xorm.Engine
 	cEnt  *ent.Client
@@ -24,7 +30,7 @@ func TestMain(m *testing.M) {
 	testutil.TestMainWrapper(testutil.TestConfig{
 		M: m,
 		LoadResources: func() error {
-			dsn := util.GetDsn()
+			dsn = util.GetDsn()
 			var err error
 
 			c, err = sql.Open("mysql", dsn)
@@ -107,7 +113,7 @@ func TestEntityDBSelectAll(t *testing.T) {
 		t.Fatal(result.Error)
 	}
 
-	result = DBSelectAll()
+	result = DBSelectAll(nil)
 	if result.Error != nil {
 		t.Fatal(result.Error)
 	}
@@ -124,3 +130,550 @@ func TestEntityDBSelectAll(t *testing.T) {
 		t.Fatalf("inserted entity not found in DBSelectAll results")
 	}
 }
+
+func TestEntityNullDistinctFromEmpty(t *testing.T) {
+	withNull := Entity{Uuid: uuid.New().String(), Animal: "Owl"}
+	withEmpty := Entity{Uuid: uuid.New().String(), Animal: "Owl", TestField: NewNull("")}
+
+	for _, e := range []*Entity{&withNull, &withEmpty} {
+		result := e.DBInsert(NewQueryParams().WithInsert(FieldUuid, FieldAnimal, FieldTestField))
+		if result.Error != nil {
+			t.Fatal(result.Error)
+		}
+	}
+
+	check := Entity{Uuid: withNull.Uuid}
+	result := check.DBExists(NewQueryParams().WithWhere(FieldUuid))
+	if result.Error != nil || !result.Exists {
+		t.Fatal("entity not found:", result.Error)
+	}
+	if check.TestField.Valid || check.BigNumber.Valid {
+		t.Fatalf("expected NULL columns, got %+v", check)
+	}
+
+	check = Entity{Uuid: withEmpty.Uuid}
+	result = check.DBExists(NewQueryParams().WithWhere(FieldUuid))
+	if result.Error != nil || !result.Exists {
+		t.Fatal("entity not found:", result.Error)
+	}
+	if !check.TestField.Valid || check.TestField.V != "" {
+		t.Fatalf("expected empty test_field, got %+v", check.TestField)
+	}
+
+	// Writing the NULL back must keep it NULL, and NULLs must still match in WHERE.
+	check.TestField = Null[string]{}
+	result = check.DBUpdate(NewQueryParams().WithUpdate(FieldTestField).WithWhere(FieldUuid))
+	if result.Error != nil {
+		t.Fatal(result.Error)
+	}
+	result = check.DBExists(NewQueryParams().WithWhere(FieldUuid, FieldTestField))
+	if result.Error != nil || !result.Exists {
+		t.Fatal("entity with NULL test_field not matched:", result.Error)
+	}
+}
+
+func TestConditionBuild(t *testing.T) {
+	x := &Entity{Animal: "Cat"}
+	where, args, err := buildWhere(x, []string{FieldAnimal}, []*Condition{
+		Or(Gt(FieldBigNumber, 10), IsNull(FieldBigNumber)),
+		In(FieldUuid, "a", "b"),
+		And(Between(FieldLastUpdate, "2024-01-01", "2025-01-01"), Like(FieldTestField, "x%")),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := " WHERE " + FQTN + ".`Animal` = ? AND (" + FQTN + ".`BigNumber` > ? OR " + FQTN + ".`BigNumber` IS NULL) AND " +
+		FQTN + ".`Uuid` IN (?, ?) AND (" + FQTN + ".`LastUpdate` BETWEEN ? AND ? AND " + FQTN + ".`test_field` LIKE ?)"
+	if where != expected {
+		t.Fatalf("unexpected WHERE clause:\n got: %s\nwant: %s", where, expected)
+	}
+	if len(args) != 7 || args[0] != "Cat" || args[1] != 10 || args[6] != "x%" {
+		t.Fatalf("unexpected args: %v", args)
+	}
+
+	if _, _, err = buildWhere(x, nil, []*Condition{Eq("missing", 1)}); err == nil {
+		t.Fatal("expected an error for an unknown field")
+	}
+}
+
+func TestEntityDBSelectConditions(t *testing.T) {
+	animal := "Lynx-" + uuid.New().String()
+	for _, n := range []string{"5", "50", "500"} {
+		e := Entity{Uuid: uuid.New().String(), Animal: animal, BigNumber: NewNull(n)}
+		result := e.DBInsert(NewQueryParams().WithInsert(FieldUuid, FieldAnimal, FieldBigNumber))
+		if result.Error != nil {
+			t.Fatal(result.Error)
+		}
+	}
+
+	filter := Entity{Animal: animal}
+	result := filter.DBSelect(NewQueryParams().WithWhere(FieldAnimal).WithConditions(
+		Or(Lt(FieldBigNumber, 10), Ge(FieldBigNumber, 500)),
+	))
+	if result.Error != nil {
+		t.Fatal(result.Error)
+	}
+	if len(result.Entities) != 2 {
+		t.Fatalf("expected 2 entities, got %d", len(result.Entities))
+	}
+
+	result = filter.DBDelete(NewQueryParams().WithConditions(Eq(FieldAnimal, animal), NotIn(FieldBigNumber, "5", "500")))
+	if result.Error != nil {
+		t.Fatal(result.Error)
+	}
+	if n, _ := result.Result.RowsAffected(); n != 1 {
+		t.Fatalf("expected 1 deleted row, got %d", n)
+	}
+}
+
+func TestBuildOrderLimit(t *testing.T) {
+	tail, args, err := buildOrderLimit(NewQueryParams().WithOrderBy(Desc(FieldLastUpdate), Asc(FieldUuid)).WithLimit(10).WithOffset(20))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := " ORDER BY " + FQTN + ".`LastUpdate` DESC, " + FQTN + ".`Uuid` ASC LIMIT ? OFFSET ?"
+	if tail != expected {
+		t.Fatalf("unexpected tail:\n got: %s\nwant: %s", tail, expected)
+	}
+	if len(args) != 2 || args[0] != 10 || args[1] != 20 {
+		t.Fatalf("unexpected args: %v", args)
+	}
+
+	if _, _, err = buildOrderLimit(NewQueryParams().WithOrderBy(Asc("missing"))); err == nil {
+		t.Fatal("expected an error for an unknown field")
+	}
+}
+
+func TestEntityDBSelectOrderLimitOffset(t *testing.T) {
+	animal := "Heron-" + uuid.New().String()
+	for _, n := range []string{"3", "1", "2"} {
+		e := Entity{Uuid: uuid.New().String(), Animal: animal, BigNumber: NewNull(n)}
+		result := e.DBInsert(NewQueryParams().WithInsert(FieldUuid, FieldAnimal, FieldBigNumber))
+		if result.Error != nil {
+			t.Fatal(result.Error)
+		}
+	}
+
+	filter := Entity{Animal: animal}
+	result := filter.DBSelect(NewQueryParams().WithWhere(FieldAnimal).WithOrderBy(Desc(FieldBigNumber)).WithLimit(2).WithOffset(1))
+	if result.Error != nil {
+		t.Fatal(result.Error)
+	}
+	if len(result.Entities) != 2 || result.Entities[0].BigNumber.V != "2" || result.Entities[1].BigNumber.V != "1" {
+		t.Fatalf("unexpected page: %+v", result.Entities)
+	}
+}
+
+func TestCursorRoundtrip(t *testing.T) {
+	orders := []Order{Desc(FieldLastUpdate), Asc(FieldUuid)}
+	x := &Entity{Uuid: "u-1", LastUpdate: "2025-01-01 00:00:00.000000"}
+	cursor, err := encodeCursor(orders, x)
+	if err != nil {
+		t.Fatal(err)
+	}
+	values, err := decodeCursor(orders, cursor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(values) != 2 || values[0] != x.LastUpdate || values[1] != x.Uuid {
+		t.Fatalf("unexpected values: %v", values)
+	}
+
+	if _, err = decodeCursor([]Order{Asc(FieldLastUpdate), Asc(FieldUuid)}, cursor); err == nil {
+		t.Fatal("expected an error for a cursor issued with a different ordering")
+	}
+	if _, err = decodeCursor(orders, "not-a-cursor"); err == nil {
+		t.Fatal("expected an error for a malformed cursor")
+	}
+	if _, err = encodeCursor([]Order{Asc(FieldTestField)}, x); err == nil {
+		t.Fatal("expected an error for a NULL cursor field")
+	}
+}
+
+func TestEntityDBSelectPage(t *testing.T) {
+	animal := "Mole-" + uuid.New().String()
+	inserted := make(map[string]bool)
+	for i := 0; i < 5; i++ {
+		e := Entity{Uuid: uuid.New().String(), Animal: animal}
+		result := e.DBInsert(NewQueryParams().WithInsert(FieldUuid, FieldAnimal))
+		if result.Error != nil {
+			t.Fatal(result.Error)
+		}
+		inserted[e.Uuid] = true
+	}
+
+	filter := Entity{Animal: animal}
+	params := NewQueryParams().WithWhere(FieldAnimal).WithOrderBy(Asc(FieldUuid)).WithLimit(2)
+	seen := make(map[string]bool)
+	cursor, pages := "", 0
+	for {
+		result := filter.DBSelectPage(params, cursor)
+		if result.Error != nil {
+			t.Fatal(result.Error)
+		}
+		pages++
+		for _, e := range result.Entities {
+			if seen[e.Uuid] {
+				t.Fatalf("entity %s returned twice", e.Uuid)
+			}
+			seen[e.Uuid] = true
+		}
+		if result.NextCursor == "" {
+			break
+		}
+		cursor = result.NextCursor
+	}
+	if pages != 3 || len(seen) != len(inserted) {
+		t.Fatalf("expected 3 pages covering %d entities, got %d pages and %d entities", len(inserted), pages, len(seen))
+	}
+}
+
+func TestEntityDBSelectPageRequiresUniqueOrder(t *testing.T) {
+	filter := Entity{Animal: "Mole"}
+	for _, orders := range [][]Order{
+		{Asc(FieldAnimal)},
+		{Asc(FieldBigNumber), Asc(FieldUuid)},
+	} {
+		params := NewQueryParams().WithOrderBy(orders...).WithLimit(2)
+		if result := filter.DBSelectPage(params, ""); result.Error == nil {
+			t.Fatalf("expected OrderBy %v to be rejected", orders)
+		}
+	}
+	params := NewQueryParams().WithOrderBy(Asc(FieldAnimal), Desc(FieldUuid)).WithLimit(2)
+	if result := filter.DBSelectPage(params, ""); result.Error != nil {
+		t.Fatalf("expected OrderBy ending with the primary key to be accepted, got %v", result.Error)
+	}
+}
+
+func TestEntityDBInsertMany(t *testing.T) {
+	animal := "Ant-" + uuid.New().String()
+	entities := make([]*Entity, 25)
+	for i := range entities {
+		entities[i] = &Entity{Uuid: uuid.New().String(), Animal: animal}
+	}
+
+	result := DBInsertMany(entities, NewQueryParams().WithInsert(FieldUuid, FieldAnimal).WithBatchSize(10))
+	if result.Error != nil {
+		t.Fatal(result.Error)
+	}
+	if result.Batches != 3 || result.RowsAffected != 25 {
+		t.Fatalf("expected 3 batches and 25 rows, got %d batches and %d rows", result.Batches, result.RowsAffected)
+	}
+
+	// The second batch collides with an existing primary key, the others still succeed.
+	retry := []*Entity{
+		{Uuid: uuid.New().String(), Animal: animal},
+		{Uuid: entities[0].Uuid, Animal: animal},
+		{Uuid: uuid.New().String(), Animal: animal},
+	}
+	result = DBInsertMany(retry, NewQueryParams().WithInsert(FieldUuid, FieldAnimal).WithBatchSize(1))
+	if result.Error == nil || len(result.BatchErrors) != 1 {
+		t.Fatalf("expected exactly one failed batch, got %v", result.BatchErrors)
+	}
+	if be := result.BatchErrors[0]; be.Batch != 1 || be.Offset != 1 || be.Count != 1 {
+		t.Fatalf("unexpected batch error: %+v", be)
+	}
+	if result.RowsAffected != 2 {
+		t.Fatalf("expected 2 rows from the successful batches, got %d", result.RowsAffected)
+	}
+}
+
+func TestEntityDBInsertManyCachesFullBatches(t *testing.T) {
+	client, err := NewClient(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	animal := "Ant-" + uuid.New().String()
+	for _, n := range []int{25, 27, 13} {
+		entities := make([]*Entity, n)
+		for i := range entities {
+			entities[i] = &Entity{Uuid: uuid.New().String(), Animal: animal}
+		}
+		if result := client.DBInsertMany(entities, NewQueryParams().WithInsert(FieldUuid, FieldAnimal).WithBatchSize(10)); result.Error != nil {
+			t.Fatal(result.Error)
+		}
+	}
+	inserts := 0
+	for query := range client.primary.stmtCache {
+		if strings.HasPrefix(query, "INSERT") {
+			inserts++
+		}
+	}
+	if inserts != 1 {
+		t.Fatalf("expected only the full batch statement to be cached, got %d", inserts)
+	}
+}
+
+func TestEntityDBUpsert(t *testing.T) {
+	e := Entity{Uuid: uuid.New().String(), Animal: "Badger"}
+	params := NewQueryParams().WithInsert(FieldUuid, FieldAnimal).WithUpdate(FieldAnimal)
+
+	result := e.DBUpsert(params)
+	if result.Error != nil {
+		t.Fatal(result.Error)
+	}
+	if result.Outcome != UpsertInserted {
+		t.Fatalf("expected inserted, got %s", result.Outcome)
+	}
+
+	result = e.DBUpsert(params)
+	if result.Error != nil {
+		t.Fatal(result.Error)
+	}
+	if result.Outcome != UpsertUnchanged {
+		t.Fatalf("expected unchanged, got %s", result.Outcome)
+	}
+	stamp := DBGetByPK(e.Uuid).Entity.LastUpdate
+
+	e.Animal = "Wolverine"
+	result = e.DBUpsert(params)
+	if result.Error != nil {
+		t.Fatal(result.Error)
+	}
+	if result.Outcome != UpsertUpdated {
+		t.Fatalf("expected updated, got %s", result.Outcome)
+	}
+	if got := DBGetByPK(e.Uuid).Entity.LastUpdate; got == stamp {
+		t.Fatalf("expected the update to refresh LastUpdate, still %q", got)
+	}
+
+	e.Animal = "Ignored"
+	result = e.DBInsertIgnore(params)
+	if result.Error != nil {
+		t.Fatal(result.Error)
+	}
+	if result.Outcome != UpsertUnchanged {
+		t.Fatalf("expected INSERT IGNORE to leave the row unchanged, got %s", result.Outcome)
+	}
+
+	e.Animal = "Replaced"
+	result = e.DBReplace(params)
+	if result.Error != nil {
+		t.Fatal(result.Error)
+	}
+	if result.Outcome != UpsertUpdated {
+		t.Fatalf("expected REPLACE to report updated, got %s", result.Outcome)
+	}
+
+	check := Entity{Uuid: e.Uuid}
+	if result = check.DBExists(NewQueryParams().WithWhere(FieldUuid)); result.Error != nil || !result.Exists {
+		t.Fatal("entity not found:", result.Error)
+	}
+	if check.Animal != "Replaced" {
+		t.Fatalf("expected Animal=Replaced, got %s", check.Animal)
+	}
+}
+
+func TestEntityDBInsertDeleteReturning(t *testing.T) {
+	e := Entity{Uuid: uuid.New().String(), Animal: "Seal"}
+	result := e.DBInsertReturning(NewQueryParams().WithInsert(FieldUuid, FieldAnimal))
+	if result.Error != nil {
+		t.Fatal(result.Error)
+	}
+	if result.Entity == nil || result.Entity.Uuid != e.Uuid {
+		t.Fatalf("expected the inserted row to be returned, got %+v", result.Entity)
+	}
+	if result.Entity.FirstInsert == "" {
+		t.Fatal("expected the server default of FirstInsert to be returned")
+	}
+
+	result = e.DBDeleteReturning(NewQueryParams().WithWhere(FieldUuid).WithSelect(FieldUuid, FieldAnimal))
+	if result.Error != nil {
+		t.Fatal(result.Error)
+	}
+	if len(result.Entities) != 1 || result.Entities[0].Animal != "Seal" {
+		t.Fatalf("expected the deleted row to be returned, got %+v", result.Entities)
+	}
+}
+
+func TestEntityPrimaryKeyOperations(t *testing.T) {
+	e := Entity{Uuid: uuid.New().String(), Animal: "Crane"}
+	result := e.DBInsert(NewQueryParams().WithInsert(FieldUuid, FieldAnimal))
+	if result.Error != nil {
+		t.Fatal(result.Error)
+	}
+
+	result = DBGetByPK(e.Uuid)
+	if result.Error != nil || !result.Exists || result.Entity.Animal != "Crane" {
+		t.Fatalf("DBGetByPK: unexpected result %+v", result)
+	}
+
+	e.Animal = "Stork"
+	if result = e.DBUpdateByPK(NewQueryParams().WithUpdate(FieldAnimal)); result.Error != nil {
+		t.Fatal(result.Error)
+	}
+
+	reloaded := Entity{Uuid: e.Uuid}
+	if result = reloaded.DBReload(); result.Error != nil || !result.Exists {
+		t.Fatal("DBReload failed:", result.Error)
+	}
+	if reloaded.Animal != "Stork" || reloaded.FirstInsert == "" {
+		t.Fatalf("expected a fully reloaded entity, got %+v", reloaded)
+	}
+
+	if result = reloaded.DBDeleteByPK(); result.Error != nil {
+		t.Fatal(result.Error)
+	}
+	if result = DBGetByPK(e.Uuid); result.Error != nil || result.Exists {
+		t.Fatalf("expected the entity to be deleted, got %+v", result)
+	}
+}
+
+func TestEntityDuplicateKeyError(t *testing.T) {
+	e := Entity{Uuid: uuid.New().String(), Animal: "Stork"}
+	if result := e.DBInsert(NewQueryParams().WithInsert(FieldUuid, FieldAnimal)); result.Error != nil {
+		t.Fatal(result.Error)
+	}
+
+	result := e.DBInsert(NewQueryParams().WithInsert(FieldUuid, FieldAnimal))
+	var dbErr *DBError
+	if !errors.Is(result.Error, ErrDuplicateKey) || !errors.As(result.Error, &dbErr) {
+		t.Fatalf("expected a duplicate key error, got %v", result.Error)
+	}
+	if dbErr.Code != 1062 || dbErr.Key != "PRIMARY" {
+		t.Fatalf("unexpected error details %+v", dbErr)
+	}
+}
+
+func TestEntityStaleUpdate(t *testing.T) {
+	e := Entity{Uuid: uuid.New().String(), Animal: "Ibis"}
+	if result := e.DBInsert(NewQueryParams().WithInsert(FieldUuid, FieldAnimal)); result.Error != nil {
+		t.Fatal(result.Error)
+	}
+	first, second := DBGetByPK(e.Uuid).Entity, DBGetByPK(e.Uuid).Entity
+	if first == nil || first.LastUpdate == "" {
+		t.Fatalf("expected the row with its LastUpdate, got %+v", first)
+	}
+
+	token := first.LastUpdate
+	first.Animal = "Egret"
+	if result := first.DBUpdateByPK(NewQueryParams().WithUpdate(FieldAnimal)); result.Error != nil {
+		t.Fatal(result.Error)
+	}
+	if first.LastUpdate == token {
+		t.Fatal("expected the concurrency token to be refreshed")
+	}
+
+	second.Animal = "Heron"
+	if result := second.DBUpdateByPK(NewQueryParams().WithUpdate(FieldAnimal)); !errors.Is(result.Error, ErrStaleEntity) {
+		t.Fatalf("expected ErrStaleEntity, got %v", result.Error)
+	}
+	if result := DBGetByPK(e.Uuid); result.Entity.Animal != "Egret" || result.Entity.LastUpdate != first.LastUpdate {
+		t.Fatalf("expected the first update to stick, got %+v", result.Entity)
+	}
+
+	first.Animal = "Crane"
+	if result := first.DBUpdateByPK(NewQueryParams().WithUpdate(FieldAnimal)); result.Error != nil {
+		t.Fatal("expected the refreshed token to allow another update:", result.Error)
+	}
+
+	if result := second.DBUpdate(NewQueryParams().WithUpdate(FieldAnimal).WithWhere(FieldUuid)); result.Error != nil {
+		t.Fatal("expected DBUpdate to ignore the token:", result.Error)
+	}
+}
+
+func TestEntityStaleUpdateServerClock(t *testing.T) {
+	defer func(clock func() time.Time) { Clock = clock }(Clock)
+	Clock = nil
+
+	e := Entity{Uuid: uuid.New().String(), Animal: "Kite"}
+	if result := e.DBInsert(NewQueryParams().WithInsert(FieldUuid, FieldAnimal)); result.Error != nil {
+		t.Fatal(result.Error)
+	}
+	if e.LastUpdate == "" || e.LastUpdate != DBGetByPK(e.Uuid).Entity.LastUpdate {
+		t.Fatalf("expected the token written by NOW(6) to be read back, got %q", e.LastUpdate)
+	}
+	stale := e
+
+	e.Animal = "Hawk"
+	if result := e.DBUpdateByPK(nil); result.Error != nil {
+		t.Fatal(result.Error)
+	}
+	if e.LastUpdate == stale.LastUpdate || e.LastUpdate != DBGetByPK(e.Uuid).Entity.LastUpdate {
+		t.Fatalf("expected the refreshed token to be read back, got %q", e.LastUpdate)
+	}
+	if result := stale.DBUpdateByPK(nil); !errors.Is(result.Error, ErrStaleEntity) {
+		t.Fatalf("expected ErrStaleEntity, got %v", result.Error)
+	}
+}
+
+func TestClientIsolation(t *testing.T) {
+	client, err := NewClient(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e := Entity{Uuid: uuid.New().String(), Animal: "Heron"}
+	result := client.DBInsert(&e, NewQueryParams().WithInsert(FieldUuid, FieldAnimal))
+	if result.Error != nil {
+		t.Fatal(result.Error)
+	}
+	if result = client.DBGetByPK(e.Uuid); result.Error != nil || !result.Exists {
+		t.Fatalf("expected the client to read its own insert, got %+v", result)
+	}
+	if len(client.primary.stmtCache) == 0 {
+		t.Fatal("expected statements to be cached on the client")
+	}
+	if result = DBGetByPK(e.Uuid); result.Error != nil || !result.Exists {
+		t.Fatalf("expected the default client to see the row too, got %+v", result)
+	}
+
+	unset, err := NewClient(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result = unset.DBGetByPK(e.Uuid); result.Error == nil {
+		t.Fatal("expected an error from a client without a db")
+	}
+}
+
+func TestClientReadRouting(t *testing.T) {
+	replica, err := sql.Open("mysql", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer replica.Close()
+
+	client, err := NewClient(c, replica)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := client.reader(nil, nil); n != client.replicas[0] {
+		t.Fatal("expected reads to go to the replica")
+	}
+	if n := client.reader(UsePrimary(context.Background()), nil); n != client.primary {
+		t.Fatal("expected UsePrimary to force the primary")
+	}
+
+	e := Entity{Uuid: uuid.New().String(), Animal: "Ibis"}
+	if result := client.DBInsert(&e, NewQueryParams().WithInsert(FieldUuid, FieldAnimal)); result.Error != nil {
+		t.Fatal(result.Error)
+	}
+	if len(client.replicas[0].stmtCache) != 0 {
+		t.Fatal("expected the insert to be prepared on the primary only")
+	}
+	if result := client.DBGetByPK(e.Uuid); result.Error != nil || !result.Exists {
+		t.Fatalf("expected the replica to return the row, got %+v", result)
+	}
+	if len(client.replicas[0].stmtCache) == 0 {
+		t.Fatal("expected the read to be prepared on the replica")
+	}
+
+	tx, err := c.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+	if n := client.reader(nil, tx); n != client.primary {
+		t.Fatal("expected reads inside a transaction to go to the primary")
+	}
+
+	client.replicas[0].report(driver.ErrBadConn)
+	if n := client.reader(nil, nil); n != client.primary {
+		t.Fatal("expected an unhealthy replica to be skipped")
+	}
+	client.CheckReplicas(context.Background())
+	if n := client.reader(nil, nil); n != client.replicas[0] {
+		t.Fatal("expected CheckReplicas to restore the replica")
+	}
+}
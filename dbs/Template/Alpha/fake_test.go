@@ -0,0 +1,353 @@
+package Alpha
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFakeQueryParams(t *testing.T) {
+	var q Querier = NewFake(
+		&Entity{Uuid: "a", Animal: "cat", BigNumber: NewNull("10")},
+		&Entity{Uuid: "b", Animal: "dog", BigNumber: NewNull("200")},
+	)
+
+	result := q.DBInsert(&Entity{Uuid: "c", Animal: "cat", TestField: NewNull("x")}, NewQueryParams().WithInsert(FieldUuid, FieldAnimal, FieldTestField))
+	if result.Error != nil {
+		t.Fatal(result.Error)
+	}
+	var dbErr *DBError
+	if result = q.DBInsert(&Entity{Uuid: "a"}, nil); !errors.Is(result.Error, ErrDuplicateKey) || !errors.As(result.Error, &dbErr) || dbErr.Key != "PRIMARY" {
+		t.Fatalf("expected a duplicate key error on PRIMARY, got %v", result.Error)
+	}
+
+	result = q.DBSelect(&Entity{Animal: "cat"}, NewQueryParams().WithSelect(FieldUuid).WithWhere(FieldAnimal).WithOrderBy(Desc(FieldUuid)))
+	if result.Error != nil || len(result.Entities) != 2 || result.Entities[0].Uuid != "c" || result.Entities[0].Animal != "" {
+		t.Fatalf("unexpected select result %+v", result)
+	}
+
+	result = q.DBSelect(&Entity{}, NewQueryParams().WithConditions(Or(Gt(FieldBigNumber, 100), IsNotNull(FieldTestField))).WithOrderBy(Asc(FieldUuid)))
+	if result.Error != nil || len(result.Entities) != 2 || result.Entities[0].Uuid != "b" || result.Entities[1].Uuid != "c" {
+		t.Fatalf("unexpected conditions result %+v", result)
+	}
+
+	result = q.DBUpdate(&Entity{Animal: "cat", TestField: NewNull("fed")}, NewQueryParams().WithUpdate(FieldTestField).WithWhere(FieldAnimal))
+	if n, _ := result.Result.RowsAffected(); result.Error != nil || n != 2 {
+		t.Fatalf("expected two updated rows, got %v %v", n, result.Error)
+	}
+	result = q.DBGetByPK("a")
+	if !result.Exists || result.Entity.TestField.V != "fed" || result.Entity.BigNumber.V != "10" {
+		t.Fatalf("expected only TestField to change, got %+v", result.Entity)
+	}
+
+	result = q.DBUpsert(&Entity{Uuid: "b", Animal: "wolf"}, NewQueryParams().WithInsert(FieldUuid, FieldAnimal))
+	if result.Error != nil || result.Outcome != UpsertUpdated {
+		t.Fatalf("expected an update, got %+v", result)
+	}
+	result = q.DBInsertIgnore(&Entity{Uuid: "b", Animal: "fox"}, nil)
+	if result.Error != nil || result.Outcome != UpsertUnchanged {
+		t.Fatalf("expected the row to be kept, got %+v", result)
+	}
+
+	result = q.DBSelectPage(&Entity{}, NewQueryParams().WithOrderBy(Asc(FieldUuid)).WithLimit(2), "")
+	if result.Error != nil || len(result.Entities) != 2 || result.NextCursor == "" {
+		t.Fatalf("unexpected first page %+v", result)
+	}
+	result = q.DBSelectPage(&Entity{}, NewQueryParams().WithOrderBy(Asc(FieldUuid)).WithLimit(2), result.NextCursor)
+	if result.Error != nil || len(result.Entities) != 1 || result.Entities[0].Uuid != "c" || result.NextCursor != "" {
+		t.Fatalf("unexpected last page %+v", result)
+	}
+	if result = q.DBSelectPage(&Entity{}, NewQueryParams().WithOrderBy(Asc(FieldAnimal)).WithLimit(2), ""); result.Error == nil {
+		t.Fatal("expected an OrderBy without a unique key to be rejected")
+	}
+	if result = q.DBSelectPage(&Entity{}, NewQueryParams().WithOrderBy(Asc(FieldBigNumber), Asc(FieldUuid)).WithLimit(2), ""); result.Error == nil {
+		t.Fatal("expected a nullable OrderBy field to be rejected")
+	}
+
+	result = q.DBDeleteReturning(&Entity{}, NewQueryParams().WithConditions(Like(FieldAnimal, "c%")))
+	if result.Error != nil || len(result.Entities) != 2 {
+		t.Fatalf("expected two deleted cats, got %+v", result)
+	}
+	if result = q.DBSelectAll(nil); len(result.Entities) != 1 || result.Entities[0].Animal != "wolf" {
+		t.Fatalf("unexpected remaining rows %+v", result.Entities)
+	}
+
+	if result = q.QueryGetAllAnimals(); result.Error == nil {
+		t.Fatal("expected an error for an unset named query")
+	}
+}
+
+// hookCalls records the lifecycle hooks below while it is non-nil, so the
+// other tests of the package are not affected by them.
+var hookCalls []string
+
+func (x *Entity) recordHook(name string) error {
+	if hookCalls == nil {
+		return nil
+	}
+	hookCalls = append(hookCalls, name+" "+x.Uuid)
+	if x.Animal == "forbidden" && strings.HasPrefix(name, "Before") {
+		return errors.New(name + " rejected " + x.Uuid)
+	}
+	return nil
+}
+
+func (x *Entity) BeforeInsert(ctx context.Context, tx *sql.Tx) error {
+	x.Animal = strings.TrimSpace(x.Animal)
+	return x.recordHook("BeforeInsert")
+}
+
+func (x *Entity) AfterInsert(ctx context.Context, tx *sql.Tx) error {
+	return x.recordHook("AfterInsert")
+}
+
+func (x *Entity) BeforeUpdate(ctx context.Context, tx *sql.Tx) error {
+	if hookCalls != nil {
+		x.TestField = NewNull("hooked")
+	}
+	return x.recordHook("BeforeUpdate")
+}
+
+func (x *Entity) AfterUpdate(ctx context.Context, tx *sql.Tx) error {
+	return x.recordHook("AfterUpdate")
+}
+
+func (x *Entity) BeforeDelete(ctx context.Context, tx *sql.Tx) error {
+	return x.recordHook("BeforeDelete")
+}
+
+func (x *Entity) AfterDelete(ctx context.Context, tx *sql.Tx) error {
+	return x.recordHook("AfterDelete")
+}
+
+func (x *Entity) AfterLoad(ctx context.Context, tx *sql.Tx) error {
+	return x.recordHook("AfterLoad")
+}
+
+func TestFakeLifecycleHooks(t *testing.T) {
+	hookCalls = []string{}
+	defer func() { hookCalls = nil }()
+	f := NewFake()
+
+	x := &Entity{Uuid: "a", Animal: "  cat "}
+	if result := f.DBInsert(x, nil); result.Error != nil || f.Rows()[0].Animal != "cat" {
+		t.Fatalf("expected BeforeInsert to trim the animal, got %+v", f.Rows())
+	}
+	if result := f.DBInsert(&Entity{Uuid: "b", Animal: "forbidden"}, nil); result.Error == nil {
+		t.Fatal("expected BeforeInsert to abort the insert")
+	}
+	x.Animal = "dog"
+	if result := f.DBUpdateByPK(x, nil); result.Error != nil {
+		t.Fatal(result.Error)
+	}
+	if result := f.DBGetByPK("a"); result.Error != nil || result.Entity.Animal != "dog" {
+		t.Fatalf("unexpected row %+v", result)
+	}
+	if result := f.DBDeleteByPK(x); result.Error != nil {
+		t.Fatal(result.Error)
+	}
+	if rows := f.Rows(); len(rows) != 0 {
+		t.Fatalf("expected the rejected insert to be skipped, got %d rows", len(rows))
+	}
+
+	want := []string{"BeforeInsert a", "AfterInsert a", "BeforeInsert b", "BeforeUpdate a", "AfterUpdate a", "AfterLoad a", "BeforeDelete a", "AfterDelete a"}
+	if strings.Join(hookCalls, ", ") != strings.Join(want, ", ") {
+		t.Errorf("unexpected hook calls\n got: %v\nwant: %v", hookCalls, want)
+	}
+}
+
+func TestFakeUpdateChangedHookFields(t *testing.T) {
+	f := NewFake(&Entity{Uuid: "a", Animal: "cat"})
+	x := f.DBSelect(&Entity{}, NewQueryParams().WithTracking()).Entities[0]
+	hookCalls = []string{}
+	defer func() { hookCalls = nil }()
+
+	x.Animal = "dog"
+	if result := f.DBUpdateChanged(x, nil); result.Error != nil {
+		t.Fatal(result.Error)
+	}
+	if row := f.Rows()[0]; row.Animal != "dog" || row.TestField != NewNull("hooked") {
+		t.Fatalf("expected the field set by BeforeUpdate to be written, got %+v", row)
+	}
+	if changed := x.ChangedFields(); len(changed) != 0 {
+		t.Fatalf("expected a clean snapshot, got %v", changed)
+	}
+	want := []string{"BeforeUpdate a", "AfterUpdate a"}
+	if strings.Join(hookCalls, ", ") != strings.Join(want, ", ") {
+		t.Errorf("expected BeforeUpdate to run once\n got: %v\nwant: %v", hookCalls, want)
+	}
+}
+
+func TestFakeValidateOnWrite(t *testing.T) {
+	ValidateOnWrite = true
+	defer func() { ValidateOnWrite = false }()
+	f := NewFake()
+
+	long := strings.Repeat("x", 256)
+	result := f.DBInsert(&Entity{Uuid: "7d444840-9dc0-11d1-b245-5ffdce74fad2", Animal: long}, NewQueryParams().WithInsert(FieldUuid, FieldAnimal))
+	var ve *ValidationError
+	if !errors.As(result.Error, &ve) || len(ve.Errors) != 1 || ve.Errors[0].Field != FieldAnimal {
+		t.Fatalf("expected Animal to be rejected, got %v", result.Error)
+	}
+	if len(f.Rows()) != 0 {
+		t.Fatal("expected nothing to be inserted")
+	}
+
+	x := &Entity{Uuid: "7d444840-9dc0-11d1-b245-5ffdce74fad2", Animal: "cat", BigNumber: NewNull("12")}
+	if result = f.DBInsert(x, NewQueryParams().WithInsert(FieldUuid, FieldAnimal, FieldBigNumber)); result.Error != nil {
+		t.Fatal(result.Error)
+	}
+	x.BigNumber = NewNull("twelve")
+	if result = f.DBUpdateByPK(x, NewQueryParams().WithUpdate(FieldBigNumber)); !errors.As(result.Error, &ve) {
+		t.Fatalf("expected BigNumber to be rejected, got %v", result.Error)
+	}
+}
+
+func TestFakeStaleEntity(t *testing.T) {
+	f := NewFake(&Entity{Uuid: "a", Animal: "cat", LastUpdate: "2025-01-01 00:00:00.000000"})
+	first, second := f.DBGetByPK("a").Entity, f.DBGetByPK("a").Entity
+	first.Track()
+	second.Track()
+
+	first.Animal = "lion"
+	if result := f.DBUpdateByPK(first, nil); result.Error != nil {
+		t.Fatal(result.Error)
+	}
+	if first.LastUpdate == "2025-01-01 00:00:00.000000" || f.Rows()[0].LastUpdate != first.LastUpdate {
+		t.Fatalf("expected the token to be refreshed, got %q", first.LastUpdate)
+	}
+
+	second.Animal = "tiger"
+	if result := f.DBUpdateByPK(second, nil); !errors.Is(result.Error, ErrStaleEntity) {
+		t.Fatalf("expected ErrStaleEntity, got %v", result.Error)
+	}
+	if f.Rows()[0].Animal != "lion" {
+		t.Fatal("expected the stale update to be rejected")
+	}
+	if result := f.DBUpdate(second, NewQueryParams().WithUpdate(FieldAnimal).WithWhere(FieldUuid)); result.Error != nil || f.Rows()[0].Animal != "tiger" {
+		t.Fatalf("expected DBUpdate to ignore the token, got %v", result.Error)
+	}
+}
+
+func TestFakeUpdateChanged(t *testing.T) {
+	f := NewFake(&Entity{Uuid: "a", Animal: "cat", BigNumber: NewNull("1")})
+	if result := f.DBUpdateChanged(&Entity{Uuid: "a"}, nil); result.Error == nil {
+		t.Fatal("expected an error for an entity that was not loaded")
+	}
+	untracked := f.DBSelect(&Entity{}, nil).Entities[0]
+	untracked.Animal = "puma"
+	if result := f.DBUpdateChanged(untracked, nil); result.Error == nil || untracked.ChangedFields() != nil {
+		t.Fatal("expected an entity read without tracking to have no snapshot")
+	}
+	partial := f.DBSelect(&Entity{}, NewQueryParams().WithSelect(FieldAnimal).WithTracking()).Entities[0]
+	partial.Animal = "lynx"
+	if result := f.DBUpdateChanged(partial, nil); result.Error == nil {
+		t.Fatal("expected an error for an entity loaded without its primary key")
+	}
+
+	first, second := f.DBGetByPK("a").Entity, f.DBGetByPK("a").Entity
+	first.Track()
+	second.Track()
+	if changed := first.ChangedFields(); len(changed) != 0 {
+		t.Fatalf("expected no changed fields, got %v", changed)
+	}
+	result := f.DBUpdateChanged(first, nil)
+	if n, _ := result.Result.RowsAffected(); result.Error != nil || n != 0 {
+		t.Fatalf("expected a no-op, got %d, %v", n, result.Error)
+	}
+
+	first.Animal = "lion"
+	second.BigNumber = NewNull("2")
+	if changed := first.ChangedFields(); len(changed) != 1 || changed[0] != FieldAnimal {
+		t.Fatalf("expected only Animal to change, got %v", changed)
+	}
+	for _, x := range []*Entity{first, second} {
+		if result := f.DBUpdateChanged(x, nil); result.Error != nil {
+			t.Fatal(result.Error)
+		}
+	}
+	if row := f.Rows()[0]; row.Animal != "lion" || row.BigNumber.V != "2" {
+		t.Fatalf("expected both updates to be kept, got %+v", row)
+	}
+	if changed := first.ChangedFields(); len(changed) != 0 {
+		t.Fatalf("expected the snapshot to be refreshed, got %v", changed)
+	}
+
+	first.Uuid = "b"
+	if result := f.DBUpdateChanged(first, nil); !errors.Is(result.Error, ErrStaleEntity) {
+		t.Fatalf("expected the stamped LastUpdate to act as a token, got %v", result.Error)
+	}
+	second.Uuid = "b"
+	if result := f.DBUpdateChanged(second, nil); result.Error != nil {
+		t.Fatal(result.Error)
+	}
+	if rows := f.Rows(); len(rows) != 1 || rows[0].Uuid != "b" {
+		t.Fatalf("expected the loaded row to be renamed, got %+v", rows)
+	}
+}
+
+func TestFakeTimestamps(t *testing.T) {
+	defer func(clock func() time.Time) { Clock = clock }(Clock)
+	Clock = func() time.Time { return time.Date(2025, 1, 2, 3, 4, 5, 6000, time.UTC) }
+	f := NewFake()
+
+	x := &Entity{Uuid: "a", Animal: "cat", FirstInsert: "2000-01-01 00:00:00.000000"}
+	if result := f.DBInsert(x, NewQueryParams().WithInsert(FieldUuid, FieldAnimal)); result.Error != nil {
+		t.Fatal(result.Error)
+	}
+	const stamp = "2025-01-02 03:04:05.000006"
+	if row := f.Rows()[0]; row.FirstInsert != stamp || row.LastUpdate != stamp || x.FirstInsert != stamp {
+		t.Fatalf("expected both timestamps from the clock, got %+v", row)
+	}
+
+	Clock = func() time.Time { return time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC) }
+	x.LastUpdate = ""
+	x.Animal = "dog"
+	if result := f.DBUpdateByPK(x, nil); result.Error != nil {
+		t.Fatal(result.Error)
+	}
+	if row := f.Rows()[0]; row.FirstInsert != stamp || row.LastUpdate != "2025-02-01 00:00:00.000000" {
+		t.Fatalf("expected only LastUpdate to be refreshed, got %+v", row)
+	}
+
+	x.LastUpdate = "2001-01-01 00:00:00.000000"
+	if result := f.DBUpdateByPK(x, NewQueryParams().WithUpdate(FieldLastUpdate)); result.Error != nil {
+		t.Fatal(result.Error)
+	}
+	if row := f.Rows()[0]; row.LastUpdate != x.LastUpdate {
+		t.Fatalf("expected the explicit LastUpdate to be kept, got %+v", row)
+	}
+
+	Clock = func() time.Time { return time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC) }
+	upsert := NewQueryParams().WithInsert(FieldUuid, FieldAnimal)
+	if result := f.DBUpsert(&Entity{Uuid: "a", Animal: "dog"}, upsert); result.Outcome != UpsertUnchanged || f.Rows()[0].LastUpdate != x.LastUpdate {
+		t.Fatalf("expected an unchanged upsert to keep LastUpdate, got %s and %+v", result.Outcome, f.Rows()[0])
+	}
+	if result := f.DBUpsert(&Entity{Uuid: "a", Animal: "eel"}, upsert); result.Outcome != UpsertUpdated || f.Rows()[0].LastUpdate != "2025-03-01 00:00:00.000000" {
+		t.Fatalf("expected a changing upsert to refresh LastUpdate, got %s and %+v", result.Outcome, f.Rows()[0])
+	}
+
+	Clock = nil
+	y := &Entity{Uuid: "b", LastUpdate: "2001-01-01 00:00:00.000000"}
+	if result := f.DBInsert(y, nil); result.Error != nil {
+		t.Fatal(result.Error)
+	}
+	if row := f.Rows()[1]; y.FirstInsert != "" || row.LastUpdate == "" || row.LastUpdate == "2001-01-01 00:00:00.000000" {
+		t.Fatalf("expected NOW(6) to fill the row only, got %+v and %+v", y, row)
+	}
+	if y.LastUpdate != f.Rows()[1].LastUpdate {
+		t.Fatalf("expected the concurrency token to be read back, got %q", y.LastUpdate)
+	}
+	y.Animal = "owl"
+	if result := f.DBUpdateByPK(y, nil); result.Error != nil || y.LastUpdate != f.Rows()[1].LastUpdate {
+		t.Fatalf("expected the refreshed token to be read back, got %q and %v", y.LastUpdate, result.Error)
+	}
+	stale := *y
+	stale.LastUpdate = "2001-01-01 00:00:00.000000"
+	if result := f.DBUpdateByPK(&stale, nil); !errors.Is(result.Error, ErrStaleEntity) {
+		t.Fatalf("expected ErrStaleEntity in NOW(6) mode, got %v", result.Error)
+	}
+}
@@ -26,6 +26,9 @@ func TestMain(m *testing.M) {
 			if err != nil {
 				return err
 			}
+			if err = util.Migrate(c, "../migrations"); err != nil {
+				return err
+			}
 
 			SetDB(c)
 			return err
@@ -77,3 +80,99 @@ func TestEntityLastUpdateManualOverride(t *testing.T) {
 		t.Fatalf("expected last_update %s, got %s", expected, check.LastUpdate)
 	}
 }
+
+func TestEntitySoftDelete(t *testing.T) {
+	kept := Entity{Uuid: uuid.New().String(), Name: "soft-delete-kept"}
+	gone := Entity{Uuid: uuid.New().String(), Name: "soft-delete-gone"}
+	for _, e := range []*Entity{&kept, &gone} {
+		if result := e.DBInsert(NewQueryParams().WithInsert(FieldUuid, FieldName)); result.Error != nil {
+			t.Fatal("insert failed:", result.Error)
+		}
+	}
+	stored := func(u string) (deleted bool, ok bool) {
+		var deletedAt sql.NullString
+		err := c.QueryRow("SELECT deleted_at FROM "+FQTN+" WHERE uuid = ?", u).Scan(&deletedAt)
+		if err == sql.ErrNoRows {
+			return false, false
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		return deletedAt.Valid, true
+	}
+	names := func(params *QueryParams) map[string]bool {
+		result := DBSelectAll(params)
+		if result.Error != nil {
+			t.Fatal(result.Error)
+		}
+		m := map[string]bool{}
+		for _, e := range result.Entities {
+			m[e.Uuid] = true
+		}
+		return m
+	}
+
+	before := DBGetByPK(gone.Uuid).Entity.LastUpdate
+	result := gone.DBDelete(NewQueryParams().WithWhere(FieldUuid))
+	if result.Error != nil {
+		t.Fatal("delete failed:", result.Error)
+	}
+	probe := Entity{Uuid: gone.Uuid}
+	if after := probe.DBSelect(NewQueryParams().WithWhere(FieldUuid).WithDeleted()); len(after.Entities) != 1 || after.Entities[0].LastUpdate == before {
+		t.Fatalf("expected DBDelete to refresh LastUpdate, got %+v", after.Entities)
+	}
+	if n, _ := result.Result.RowsAffected(); n != 1 || !gone.DeletedAt.Valid {
+		t.Fatalf("expected one soft-deleted row, got %d, %+v", n, gone.DeletedAt)
+	}
+	if deleted, ok := stored(gone.Uuid); !ok || !deleted {
+		t.Fatal("expected DBDelete to keep the row and set deleted_at")
+	}
+	if n, _ := gone.DBDelete(NewQueryParams().WithWhere(FieldUuid)).Result.RowsAffected(); n != 0 {
+		t.Fatal("expected a deleted row not to be deleted again")
+	}
+
+	if all := names(nil); !all[kept.Uuid] || all[gone.Uuid] {
+		t.Fatalf("expected DBSelectAll to skip the deleted row, got %v", all)
+	}
+	if all := names(NewQueryParams().WithDeleted()); !all[kept.Uuid] || !all[gone.Uuid] {
+		t.Fatalf("expected WithDeleted to include the deleted row, got %v", all)
+	}
+	if all := names(NewQueryParams().OnlyDeleted()); all[kept.Uuid] || !all[gone.Uuid] {
+		t.Fatalf("expected OnlyDeleted to return only the deleted row, got %v", all)
+	}
+	if result = DBGetByPK(gone.Uuid); result.Error != nil || result.Exists {
+		t.Fatalf("expected DBGetByPK to skip the deleted row, got %+v", result)
+	}
+	check := Entity{Uuid: gone.Uuid}
+	if result = check.DBExists(NewQueryParams().WithWhere(FieldUuid)); result.Error != nil || result.Exists {
+		t.Fatalf("expected DBExists to skip the deleted row, got %+v", result)
+	}
+
+	if result = gone.DBRestore(NewQueryParams().WithWhere(FieldUuid)); result.Error != nil || gone.DeletedAt.Valid {
+		t.Fatalf("unexpected restore result %v, %+v", result.Error, gone.DeletedAt)
+	}
+	if deleted, ok := stored(gone.Uuid); !ok || deleted {
+		t.Fatal("expected DBRestore to clear deleted_at")
+	}
+	if result = DBGetByPK(gone.Uuid); !result.Exists {
+		t.Fatal("expected the restored row to be visible")
+	}
+
+	result = kept.DBDeleteReturning(NewQueryParams().WithWhere(FieldUuid))
+	if result.Error != nil || len(result.Entities) != 1 || result.Entities[0].Uuid != kept.Uuid || !result.Entities[0].DeletedAt.Valid {
+		t.Fatalf("expected DBDeleteReturning to soft-delete and return the row, got %+v, %v", result.Entities, result.Error)
+	}
+	if deleted, ok := stored(kept.Uuid); !ok || !deleted {
+		t.Fatal("expected DBDeleteReturning to keep the row and set deleted_at")
+	}
+	if result = kept.DBDeleteReturning(NewQueryParams().WithWhere(FieldUuid)); result.Error != nil || len(result.Entities) != 0 {
+		t.Fatalf("expected a deleted row not to be returned again, got %+v", result.Entities)
+	}
+
+	if result = kept.DBHardDelete(NewQueryParams().WithWhere(FieldUuid)); result.Error != nil {
+		t.Fatal("hard delete failed:", result.Error)
+	}
+	if _, ok := stored(kept.Uuid); ok {
+		t.Fatal("expected DBHardDelete to remove the row")
+	}
+}
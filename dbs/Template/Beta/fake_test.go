@@ -0,0 +1,57 @@
+package Beta
+
+import "testing"
+
+func TestFakeSoftDelete(t *testing.T) {
+	f := NewFake(&Entity{Uuid: "a", Name: "kept"}, &Entity{Uuid: "b", Name: "gone"})
+
+	x := &Entity{Uuid: "b"}
+	result := f.DBDelete(x, nil)
+	if n, _ := result.Result.RowsAffected(); result.Error != nil || n != 1 || !x.DeletedAt.Valid {
+		t.Fatalf("expected one soft-deleted row, got %d, %v", n, result.Error)
+	}
+	if rows := f.Rows(); len(rows) != 2 || rows[1].LastUpdate == "" {
+		t.Fatalf("expected the row to be kept with a fresh LastUpdate, got %+v", rows)
+	}
+	if n, _ := f.DBDelete(&Entity{Uuid: "b"}, nil).Result.RowsAffected(); n != 0 {
+		t.Fatal("expected a deleted row not to be deleted again")
+	}
+
+	if result = f.DBSelectAll(nil); len(result.Entities) != 1 || result.Entities[0].Uuid != "a" {
+		t.Fatalf("expected the deleted row to be hidden, got %+v", result.Entities)
+	}
+	if result = f.DBGetByPK("b"); result.Exists {
+		t.Fatal("expected DBGetByPK to skip the deleted row")
+	}
+	if result = f.DBExists(&Entity{Uuid: "b"}, NewQueryParams().WithWhere(FieldUuid)); result.Exists {
+		t.Fatal("expected DBExists to skip the deleted row")
+	}
+	if result = f.DBSelectAll(NewQueryParams().WithDeleted()); len(result.Entities) != 2 {
+		t.Fatalf("expected both rows with WithDeleted, got %d", len(result.Entities))
+	}
+	result = f.DBSelect(&Entity{}, NewQueryParams().OnlyDeleted())
+	if len(result.Entities) != 1 || result.Entities[0].Uuid != "b" || !result.Entities[0].DeletedAt.Valid {
+		t.Fatalf("expected only the deleted row, got %+v", result.Entities)
+	}
+	result = f.DBDeleteReturning(&Entity{Uuid: "a"}, nil)
+	if result.Error != nil || len(result.Entities) != 1 || !result.Entities[0].DeletedAt.Valid || f.Rows()[0].DeletedAt != result.Entities[0].DeletedAt {
+		t.Fatalf("expected DBDeleteReturning to soft-delete and return the row, got %+v, %v", result.Entities, result.Error)
+	}
+	if result = f.DBDeleteReturning(&Entity{Uuid: "a"}, nil); result.Error != nil || len(result.Entities) != 0 {
+		t.Fatalf("expected a deleted row not to be returned again, got %+v", result.Entities)
+	}
+
+	if result = f.DBRestore(x, nil); result.Error != nil || x.DeletedAt.Valid {
+		t.Fatalf("unexpected restore result %v, %+v", result.Error, x.DeletedAt)
+	}
+	if result = f.DBGetByPK("b"); !result.Exists {
+		t.Fatal("expected the restored row to be visible")
+	}
+
+	if result = f.DBHardDelete(&Entity{Uuid: "a"}, nil); result.Error != nil {
+		t.Fatal(result.Error)
+	}
+	if rows := f.Rows(); len(rows) != 1 || rows[0].Uuid != "b" {
+		t.Fatalf("expected the row to be removed, got %+v", rows)
+	}
+}
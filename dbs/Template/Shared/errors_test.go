@@ -0,0 +1,43 @@
+package Shared
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+func TestWrapError(t *testing.T) {
+	err := WrapError(errors.New("Error 1452 (23000): Cannot add or update a child row: a foreign key constraint fails (`template`.`beta`, CONSTRAINT `fk_beta_alpha` FOREIGN KEY (`alpha_uuid`) REFERENCES `alpha` (`Uuid`))"))
+	var dbErr *DBError
+	if !errors.Is(err, ErrForeignKeyViolation) || !errors.As(err, &dbErr) || dbErr.Key != "fk_beta_alpha" || dbErr.Column != "alpha_uuid" {
+		t.Fatalf("unexpected foreign key classification %#v", err)
+	}
+	if err = WrapError(errors.New("Error 1451 (23000): Cannot delete or update a parent row")); !errors.Is(err, ErrForeignKeyViolation) {
+		t.Fatalf("expected 1451 to be a foreign key violation, got %v", err)
+	}
+	if err = WrapError(errors.New("Error 1406 (22001): Data too long for column 'Animal' at row 1")); !errors.As(err, &dbErr) || !errors.Is(err, ErrDataTooLong) || dbErr.Column != "Animal" {
+		t.Fatalf("unexpected data too long classification %#v", err)
+	}
+	if err = WrapError(errors.New("Error 1062 (23000): Duplicate entry 'x' for key 'alpha.PRIMARY'")); !errors.As(err, &dbErr) || dbErr.Key != "PRIMARY" {
+		t.Fatalf("expected the table prefix to be dropped, got %#v", err)
+	}
+	if err = WrapError(errors.New("Error 1213 (40001): Deadlock found")); !errors.Is(err, ErrDeadlock) || errors.Is(err, ErrLockTimeout) {
+		t.Fatalf("unexpected deadlock classification %v", err)
+	}
+	plain := errors.New("invalid connection")
+	if err = WrapError(plain); err != plain {
+		t.Fatalf("expected errors without a code to pass through, got %#v", err)
+	}
+}
+
+func TestIsConnError(t *testing.T) {
+	if !IsConnError(fmt.Errorf("query: %w", mysql.ErrInvalidConn)) || !IsConnError(driver.ErrBadConn) {
+		t.Fatal("expected broken connections to be recognised")
+	}
+	if IsConnError(errors.New("invalid connection")) || IsConnError(WrapError(errors.New("Error 1062 (23000): Duplicate entry"))) || IsConnError(nil) {
+		t.Fatal("expected other errors not to be connection errors")
+	}
+}
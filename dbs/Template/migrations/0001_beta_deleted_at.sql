@@ -0,0 +1,2 @@
+-- Soft delete for beta: DBDelete sets deleted_at instead of removing the row.
+ALTER TABLE `template`.`beta` ADD COLUMN IF NOT EXISTS `deleted_at` DATETIME(6) NULL DEFAULT NULL;
@@ -0,0 +1,3 @@
+-- beta rows optionally belong to an alpha row, read back as Template.ForeignKeys.
+ALTER TABLE `template`.`beta` ADD COLUMN IF NOT EXISTS `alpha_uuid` UUID NULL DEFAULT NULL;
+ALTER TABLE `template`.`beta` ADD CONSTRAINT `fk_beta_alpha` FOREIGN KEY IF NOT EXISTS `fk_beta_alpha` (`alpha_uuid`) REFERENCES `template`.`alpha` (`Uuid`);
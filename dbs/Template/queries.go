@@ -7,122 +7,689 @@ package Template
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
 	"encoding/base64"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"strconv"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/rah-0/margo-test/dbs/Template/AllTypes"
 	"github.com/rah-0/margo-test/dbs/Template/Alpha"
 	"github.com/rah-0/margo-test/dbs/Template/Beta"
+	"github.com/rah-0/margo-test/dbs/Template/Shared"
 )
 
 var (
-	db        *sql.DB
-	stmtMu    sync.RWMutex
-	stmtCache = make(map[string]*sql.Stmt)
-	queries   = map[string]*NamedQuery{
-		"CountBigNumbers":  {QueryEncoded: "U0VMRUNUIENPVU5UKCopIEFTIGBjb3VudGAKRlJPTSBgYWxwaGFgCldIRVJFIGBCaWdOdW1iZXJgIElTIE5VTEw="},
-		"DeleteByUuid":     {QueryEncoded: "REVMRVRFIEZST00gYGFscGhhYCBXSEVSRSBgVXVpZGAgPSA/"},
-		"DeleteOldRows":    {QueryEncoded: "REVMRVRFIEZST00gYGFscGhhYCBXSEVSRSBgTGFzdFVwZGF0ZWAgPCAnMjAyMy0wMS0wMSAwMDowMDowMC4wMDAwMDAn"},
-		"GetByUuid":        {QueryEncoded: "U0VMRUNUIGBBbmltYWxgLCBgdGVzdF9maWVsZGAKRlJPTSBgYWxwaGFgCldIRVJFIGBVdWlkYCA9ID8="},
-		"GetRecentCats":    {QueryEncoded: "U0VMRUNUIGBVdWlkYCwgYExhc3RVcGRhdGVgCkZST00gYGFscGhhYApXSEVSRSBgQW5pbWFsYCA9ICdjYXQnIEFORCBgTGFzdFVwZGF0ZWAgPiAnMjAyNC0wMS0wMSAwMDowMDowMC4wMDAwMDAn"},
-		"InsertHardcoded":  {QueryEncoded: "SU5TRVJUIElOVE8gYGFscGhhYCAoYFV1aWRgLCBgQW5pbWFsYCkKVkFMVUVTICgnMTExMTExMTEtMTExMS00MTExLTgxMTEtMTExMTExMTExMTExJywgJ2RvZycp"},
-		"InsertOne":        {QueryEncoded: "SU5TRVJUIElOVE8gYGFscGhhYCAoYFV1aWRgLCBgQW5pbWFsYCwgYHRlc3RfZmllbGRgKQpWQUxVRVMgKD8sID8sID8p"},
-		"SampleTest":       {QueryEncoded: "V0lUSCBzZWxlY3RlZF91c2VyX3BsYW4gQVMgKAogICAgU0VMRUNUIHVwLnVzZXJfaWQsIHVwLnBsYW5faWQKICAgIEZST00gdXNlcl9wbGFuIHVwCiAgICAgICAgICAgICBKT0lOIHBsYW4gcCBPTiBwLmlkID0gdXAucGxhbl9pZAogICAgV0hFUkUgdXAudXNlcl9pZCA9ID8KICAgIE9SREVSIEJZIHAucHJpY2UgREVTQwogICAgTElNSVQgMQopClNFTEVDVAogICAgQ09BTEVTQ0UoU1VNKGYuc2l6ZV9ieXRlcyksIDApIEFTIHRvdGFsX3N0b3JhZ2VfdXNlZCwKICAgIChDT1VOVChmLmlkKSA+PSBwLm1heF9maWxlX2NvdW50KSBBUyByZWFjaGVkX2ZpbGVfbGltaXQsCiAgICAoQ09BTEVTQ0UoU1VNKGYuc2l6ZV9ieXRlcyksIDApID49IHAubWF4X3N0b3JhZ2VfYnl0ZXMpIEFTIGV4Y2VlZGVkX3N0b3JhZ2VfbGltaXQKRlJPTSBzZWxlY3RlZF91c2VyX3BsYW4gc3AKICAgICAgICAgSk9JTiBwbGFuIHAgT04gcC5pZCA9IHNwLnBsYW5faWQKICAgICAgICAgTEVGVCBKT0lOIGZpbGUgZiBPTiBmLnVzZXJfaWQgPSBzcC51c2VyX2lkCkdST1VQIEJZIHNwLnVzZXJfaWQsIHAubWF4X2ZpbGVfY291bnQsIHAubWF4X3N0b3JhZ2VfYnl0ZXM="},
-		"UpdateAnimalName": {QueryEncoded: "VVBEQVRFIGBhbHBoYWAKU0VUIGBBbmltYWxgID0gPwpXSEVSRSBgVXVpZGAgPSA/"},
-		"UpdateTestField":  {QueryEncoded: "VVBEQVRFIGBhbHBoYWAKU0VUIGB0ZXN0X2ZpZWxkYCA9ICd1cGRhdGVkJwpXSEVSRSBgQW5pbWFsYCA9ICdmb3gn"},
+	defaultClient = &Client{primary: newConn(nil, false)}
+	queries       = map[string]*NamedQuery{
+		"CountBigNumbers":  {Cardinality: CardinalityOne, QueryEncoded: "U0VMRUNUIENPVU5UKCopIEFTIGBjb3VudGAKRlJPTSBgYWxwaGFgCldIRVJFIGBCaWdOdW1iZXJgIElTIE5VTEw="},
+		"DeleteByUuid":     {Cardinality: CardinalityExecRows, QueryEncoded: "REVMRVRFIEZST00gYGFscGhhYCBXSEVSRSBgVXVpZGAgPSA/"},
+		"DeleteOldRows":    {Cardinality: CardinalityExecRows, QueryEncoded: "REVMRVRFIEZST00gYGFscGhhYCBXSEVSRSBgTGFzdFVwZGF0ZWAgPCAnMjAyMy0wMS0wMSAwMDowMDowMC4wMDAwMDAn"},
+		"GetByUuid":        {Cardinality: CardinalityOne, QueryEncoded: "U0VMRUNUIGBBbmltYWxgLCBgdGVzdF9maWVsZGAKRlJPTSBgYWxwaGFgCldIRVJFIGBVdWlkYCA9ID8="},
+		"GetRecentCats":    {Cardinality: CardinalityMany, QueryEncoded: "U0VMRUNUIGBVdWlkYCwgYExhc3RVcGRhdGVgCkZST00gYGFscGhhYApXSEVSRSBgQW5pbWFsYCA9ICdjYXQnIEFORCBgTGFzdFVwZGF0ZWAgPiAnMjAyNC0wMS0wMSAwMDowMDowMC4wMDAwMDAn"},
+		"InsertHardcoded":  {Cardinality: CardinalityExec, QueryEncoded: "SU5TRVJUIElOVE8gYGFscGhhYCAoYFV1aWRgLCBgQW5pbWFsYCkKVkFMVUVTICgnMTExMTExMTEtMTExMS00MTExLTgxMTEtMTExMTExMTExMTExJywgJ2RvZycp"},
+		"InsertOne":        {Cardinality: CardinalityExec, QueryEncoded: "SU5TRVJUIElOVE8gYGFscGhhYCAoYFV1aWRgLCBgQW5pbWFsYCwgYHRlc3RfZmllbGRgKQpWQUxVRVMgKD8sID8sID8p"},
+		"SampleTest":       {Cardinality: CardinalityOne, QueryEncoded: "V0lUSCBzZWxlY3RlZF91c2VyX3BsYW4gQVMgKAogICAgU0VMRUNUIHVwLnVzZXJfaWQsIHVwLnBsYW5faWQKICAgIEZST00gdXNlcl9wbGFuIHVwCiAgICAgICAgICAgICBKT0lOIHBsYW4gcCBPTiBwLmlkID0gdXAucGxhbl9pZAogICAgV0hFUkUgdXAudXNlcl9pZCA9ID8KICAgIE9SREVSIEJZIHAucHJpY2UgREVTQwogICAgTElNSVQgMQopClNFTEVDVAogICAgQ09BTEVTQ0UoU1VNKGYuc2l6ZV9ieXRlcyksIDApIEFTIHRvdGFsX3N0b3JhZ2VfdXNlZCwKICAgIChDT1VOVChmLmlkKSA+PSBwLm1heF9maWxlX2NvdW50KSBBUyByZWFjaGVkX2ZpbGVfbGltaXQsCiAgICAoQ09BTEVTQ0UoU1VNKGYuc2l6ZV9ieXRlcyksIDApID49IHAubWF4X3N0b3JhZ2VfYnl0ZXMpIEFTIGV4Y2VlZGVkX3N0b3JhZ2VfbGltaXQKRlJPTSBzZWxlY3RlZF91c2VyX3BsYW4gc3AKICAgICAgICAgSk9JTiBwbGFuIHAgT04gcC5pZCA9IHNwLnBsYW5faWQKICAgICAgICAgTEVGVCBKT0lOIGZpbGUgZiBPTiBmLnVzZXJfaWQgPSBzcC51c2VyX2lkCkdST1VQIEJZIHNwLnVzZXJfaWQsIHAubWF4X2ZpbGVfY291bnQsIHAubWF4X3N0b3JhZ2VfYnl0ZXM="},
+		"UpdateAnimalName": {Cardinality: CardinalityExecRows, QueryEncoded: "VVBEQVRFIGBhbHBoYWAKU0VUIGBBbmltYWxgID0gPwpXSEVSRSBgVXVpZGAgPSA/"},
+		"UpdateTestField":  {Cardinality: CardinalityExecRows, QueryEncoded: "VVBEQVRFIGBhbHBoYWAKU0VUIGB0ZXN0X2ZpZWxkYCA9ICd1cGRhdGVkJwpXSEVSRSBgQW5pbWFsYCA9ICdmb3gn"},
 	}
+	queriesOnce sync.Once
+	queriesErr  error
+	// ReplicaCooldown is how long a replica with a broken connection is skipped.
+	ReplicaCooldown = 30 * time.Second
 )
 
+const defaultTxMaxAttempts = 3
+
+func defaultTxBackoff(retry int) time.Duration {
+	return time.Duration(10<<retry) * time.Millisecond
+}
+
 type NamedQuery struct {
 	Name         string
 	Query        string
 	QueryEncoded string
+	Cardinality  Cardinality
+}
+
+// Cardinality is the result shape a named query is annotated with in its SQL
+// file, as in "-- name: GetByUuid :one". It decides the generated result type.
+type Cardinality string
+
+const (
+	// CardinalityOne reads a single row into Entity. No row leaves Exists
+	// false, and more than one row fails with ErrCardinality.
+	CardinalityOne Cardinality = ":one"
+	// CardinalityMany reads every row into Entities.
+	CardinalityMany Cardinality = ":many"
+	// CardinalityExec runs a statement and returns its sql.Result.
+	CardinalityExec Cardinality = ":exec"
+	// CardinalityExecRows runs a statement and also returns RowsAffected.
+	CardinalityExecRows Cardinality = ":execrows"
+)
+
+// Null holds the value of a nullable column, keeping SQL NULL distinct from the zero value.
+type Null[T any] struct {
+	V     T
+	Valid bool
+}
+
+func NewNull[T any](v T) Null[T] {
+	return Null[T]{V: v, Valid: true}
+}
+
+func (n *Null[T]) Scan(value any) error {
+	return (*sql.Null[T])(n).Scan(value)
 }
 
-type QueryParams struct {
-	Params []any
+func (n Null[T]) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	if v, ok := any(n.V).(uint64); ok {
+		return v, nil
+	}
+	return driver.DefaultParameterConverter.ConvertValue(n.V)
 }
 
-func NewQueryParams() *QueryParams {
-	return &QueryParams{}
+func (n Null[T]) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(n.V)
 }
 
-func (qp *QueryParams) WithParams(params ...any) *QueryParams {
-	qp.Params = params
-	return qp
+func (n *Null[T]) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		*n = Null[T]{}
+		return nil
+	}
+	if err := json.Unmarshal(b, &n.V); err != nil {
+		return err
+	}
+	n.Valid = true
+	return nil
 }
 
-func SetDB(x *sql.DB) error {
-	db = x
+// DBError is a MariaDB error classified by its error number, see Shared.DBError.
+type DBError = Shared.DBError
 
-	for _, q := range queries {
-		b, err := base64.StdEncoding.DecodeString(q.QueryEncoded)
-		if err != nil {
-			return err
+var (
+	ErrDuplicateKey        = Shared.ErrDuplicateKey
+	ErrForeignKeyViolation = Shared.ErrForeignKeyViolation
+	ErrDataTooLong         = Shared.ErrDataTooLong
+	ErrDeadlock            = Shared.ErrDeadlock
+	ErrLockTimeout         = Shared.ErrLockTimeout
+)
+
+// ErrCardinality is returned when a named query's result does not match its
+// Cardinality, such as a :one query that returns more than one row.
+var ErrCardinality = errors.New("named query result does not match its cardinality")
+
+// QueryEvent describes one statement run by a Client.
+type QueryEvent struct {
+	Op           string // "exec" or "query"
+	Table        string // FQTN of the entity, empty for the named queries of this package
+	Name         string // generated operation such as "Insert" or "SelectAll", or the named query
+	Query        string
+	Args         int
+	Replica      bool
+	Duration     time.Duration
+	RowsAffected int64 // rows changed by an exec or returned by a query, -1 when unknown
+	Err          error
+}
+
+// QueryHook observes every statement a Client runs, for logging, metrics or
+// tracing. BeforeQuery may return a derived context, for example one carrying
+// a span; it is used for the statement and passed on to AfterQuery.
+type QueryHook interface {
+	BeforeQuery(ctx context.Context, ev *QueryEvent) context.Context
+	AfterQuery(ctx context.Context, ev *QueryEvent)
+}
+
+// conn is one *sql.DB together with the statements prepared on it.
+type conn struct {
+	db        *sql.DB
+	replica   bool
+	downUntil atomic.Int64
+	stmtMu    sync.RWMutex
+	stmtCache map[string]*sql.Stmt
+	hook      atomic.Pointer[QueryHook]
+}
+
+func newConn(x *sql.DB, replica bool) *conn {
+	return &conn{db: x, replica: replica, stmtCache: make(map[string]*sql.Stmt)}
+}
+
+// report takes a replica out of rotation for ReplicaCooldown when err shows
+// that its connection is broken.
+func (n *conn) report(err error) {
+	if n.replica && Shared.IsConnError(err) {
+		n.downUntil.Store(time.Now().Add(ReplicaCooldown).UnixNano())
+	}
+}
+
+// trace hands a statement to the hook, it returns the context to run the
+// statement with and the func that completes the event.
+func (n *conn) trace(ctx context.Context, op, name, query string, args int) (context.Context, func(rows int64, err error)) {
+	hp := n.hook.Load()
+	if hp == nil {
+		return ctx, noTrace
+	}
+	hook := *hp
+	ev := &QueryEvent{Op: op, Table: "", Name: name, Query: query, Args: args, Replica: n.replica, RowsAffected: -1}
+	hookCtx := ctx
+	if hookCtx == nil {
+		hookCtx = context.Background()
+	}
+	hookCtx = hook.BeforeQuery(hookCtx, ev)
+	if ctx != nil {
+		ctx = hookCtx
+	}
+	start := time.Now()
+	return ctx, func(rows int64, err error) {
+		ev.Duration = time.Since(start)
+		ev.RowsAffected = rows
+		ev.Err = err
+		hook.AfterQuery(hookCtx, ev)
+	}
+}
+
+func noTrace(int64, error) {}
+
+func resultRows(res sql.Result) int64 {
+	if res == nil {
+		return -1
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return -1
+	}
+	return n
+}
+
+// Client bundles the clients of every entity package together with the
+// named queries, all sharing one primary and the same read replicas.
+type Client struct {
+	primary  *conn
+	replicas []*conn
+	next     atomic.Uint64
+	AllTypes *AllTypes.Client
+	Alpha    *Alpha.Client
+	Beta     *Beta.Client
+	// TxMaxAttempts is how often WithTx runs its function in total when it
+	// keeps failing with a deadlock or a lock wait timeout, 3 when zero.
+	TxMaxAttempts int
+	// TxBackoff returns the pause before the given retry of WithTx, starting
+	// at 1. When nil the pause doubles from 20ms. Set both fields before the
+	// client is shared between goroutines.
+	TxBackoff func(retry int) time.Duration
+}
+
+func NewClient(primary *sql.DB, replicas ...*sql.DB) (*Client, error) {
+	if err := decodeQueries(); err != nil {
+		return nil, err
+	}
+	c := &Client{primary: newConn(primary, false)}
+	for _, r := range replicas {
+		c.replicas = append(c.replicas, newConn(r, true))
+	}
+	var err error
+	if c.AllTypes, err = AllTypes.NewClient(primary, replicas...); err != nil {
+		return nil, err
+	}
+	if c.Alpha, err = Alpha.NewClient(primary, replicas...); err != nil {
+		return nil, err
+	}
+	if c.Beta, err = Beta.NewClient(primary, replicas...); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *Client) DB() *sql.DB {
+	return c.primary.db
+}
+
+// CheckReplicas pings every replica, taking the unreachable ones out of
+// rotation and putting the recovered ones back, in every entity client too.
+func (c *Client) CheckReplicas(ctx context.Context) {
+	for _, n := range c.replicas {
+		if err := n.db.PingContext(ctx); err != nil {
+			n.downUntil.Store(time.Now().Add(ReplicaCooldown).UnixNano())
+		} else {
+			n.downUntil.Store(0)
 		}
-		q.Query = string(b)
 	}
+	c.AllTypes.CheckReplicas(ctx)
+	c.Alpha.CheckReplicas(ctx)
+	c.Beta.CheckReplicas(ctx)
+}
 
-	if err := AllTypes.SetDB(x); err != nil {
-		return err
+// SetHook makes h observe every statement the client and its entity clients run, nil removes it.
+// It is safe to call while the client is in use.
+func (c *Client) SetHook(h QueryHook) {
+	var hp *QueryHook
+	if h != nil {
+		hp = &h
 	}
-	if err := Alpha.SetDB(x); err != nil {
-		return err
+	c.primary.hook.Store(hp)
+	for _, n := range c.replicas {
+		n.hook.Store(hp)
 	}
-	if err := Beta.SetDB(x); err != nil {
-		return err
+	if h == nil {
+		c.AllTypes.SetHook(nil)
+		c.Alpha.SetHook(nil)
+		c.Beta.SetHook(nil)
+		return
 	}
+	c.AllTypes.SetHook(allTypesHook{h})
+	c.Alpha.SetHook(alphaHook{h})
+	c.Beta.SetHook(betaHook{h})
+}
+
+// allTypesHook lets a QueryHook observe the statements of the AllTypes client.
+type allTypesHook struct {
+	h QueryHook
+}
 
+func (a allTypesHook) BeforeQuery(ctx context.Context, ev *AllTypes.QueryEvent) context.Context {
+	return a.h.BeforeQuery(ctx, (*QueryEvent)(ev))
+}
+
+func (a allTypesHook) AfterQuery(ctx context.Context, ev *AllTypes.QueryEvent) {
+	a.h.AfterQuery(ctx, (*QueryEvent)(ev))
+}
+
+// alphaHook lets a QueryHook observe the statements of the Alpha client.
+type alphaHook struct {
+	h QueryHook
+}
+
+func (a alphaHook) BeforeQuery(ctx context.Context, ev *Alpha.QueryEvent) context.Context {
+	return a.h.BeforeQuery(ctx, (*QueryEvent)(ev))
+}
+
+func (a alphaHook) AfterQuery(ctx context.Context, ev *Alpha.QueryEvent) {
+	a.h.AfterQuery(ctx, (*QueryEvent)(ev))
+}
+
+// betaHook lets a QueryHook observe the statements of the Beta client.
+type betaHook struct {
+	h QueryHook
+}
+
+func (a betaHook) BeforeQuery(ctx context.Context, ev *Beta.QueryEvent) context.Context {
+	return a.h.BeforeQuery(ctx, (*QueryEvent)(ev))
+}
+
+func (a betaHook) AfterQuery(ctx context.Context, ev *Beta.QueryEvent) {
+	a.h.AfterQuery(ctx, (*QueryEvent)(ev))
+}
+
+// Querier is implemented by *Client and by the in-memory *Fake, so code that
+// depends on the named queries can be tested without a database.
+type Querier interface {
+	QueryCountBigNumbers() *QueryCountBigNumbersResult
+	QueryCountBigNumbersCtx(ctx context.Context) *QueryCountBigNumbersResult
+	QueryCountBigNumbersTx(tx *sql.Tx) *QueryCountBigNumbersResult
+	QueryCountBigNumbersCtxTx(ctx context.Context, tx *sql.Tx) *QueryCountBigNumbersResult
+
+	ExecDeleteByUuid(uuid string) *QueryDeleteByUuidResult
+	ExecDeleteByUuidCtx(ctx context.Context, uuid string) *QueryDeleteByUuidResult
+	ExecDeleteByUuidTx(tx *sql.Tx, uuid string) *QueryDeleteByUuidResult
+	ExecDeleteByUuidCtxTx(ctx context.Context, tx *sql.Tx, uuid string) *QueryDeleteByUuidResult
+
+	ExecDeleteOldRows() *QueryDeleteOldRowsResult
+	ExecDeleteOldRowsCtx(ctx context.Context) *QueryDeleteOldRowsResult
+	ExecDeleteOldRowsTx(tx *sql.Tx) *QueryDeleteOldRowsResult
+	ExecDeleteOldRowsCtxTx(ctx context.Context, tx *sql.Tx) *QueryDeleteOldRowsResult
+
+	QueryGetByUuid(uuid string) *QueryGetByUuidResult
+	QueryGetByUuidCtx(ctx context.Context, uuid string) *QueryGetByUuidResult
+	QueryGetByUuidTx(tx *sql.Tx, uuid string) *QueryGetByUuidResult
+	QueryGetByUuidCtxTx(ctx context.Context, tx *sql.Tx, uuid string) *QueryGetByUuidResult
+
+	QueryGetRecentCats() *QueryGetRecentCatsResult
+	QueryGetRecentCatsCtx(ctx context.Context) *QueryGetRecentCatsResult
+	QueryGetRecentCatsTx(tx *sql.Tx) *QueryGetRecentCatsResult
+	QueryGetRecentCatsCtxTx(ctx context.Context, tx *sql.Tx) *QueryGetRecentCatsResult
+
+	ExecInsertHardcoded() *QueryInsertHardcodedResult
+	ExecInsertHardcodedCtx(ctx context.Context) *QueryInsertHardcodedResult
+	ExecInsertHardcodedTx(tx *sql.Tx) *QueryInsertHardcodedResult
+	ExecInsertHardcodedCtxTx(ctx context.Context, tx *sql.Tx) *QueryInsertHardcodedResult
+
+	ExecInsertOne(uuid, animal string, testField Null[string]) *QueryInsertOneResult
+	ExecInsertOneCtx(ctx context.Context, uuid, animal string, testField Null[string]) *QueryInsertOneResult
+	ExecInsertOneTx(tx *sql.Tx, uuid, animal string, testField Null[string]) *QueryInsertOneResult
+	ExecInsertOneCtxTx(ctx context.Context, tx *sql.Tx, uuid, animal string, testField Null[string]) *QueryInsertOneResult
+
+	QuerySampleTest(userId string) *QuerySampleTestResult
+	QuerySampleTestCtx(ctx context.Context, userId string) *QuerySampleTestResult
+	QuerySampleTestTx(tx *sql.Tx, userId string) *QuerySampleTestResult
+	QuerySampleTestCtxTx(ctx context.Context, tx *sql.Tx, userId string) *QuerySampleTestResult
+
+	ExecUpdateAnimalName(animal, uuid string) *QueryUpdateAnimalNameResult
+	ExecUpdateAnimalNameCtx(ctx context.Context, animal, uuid string) *QueryUpdateAnimalNameResult
+	ExecUpdateAnimalNameTx(tx *sql.Tx, animal, uuid string) *QueryUpdateAnimalNameResult
+	ExecUpdateAnimalNameCtxTx(ctx context.Context, tx *sql.Tx, animal, uuid string) *QueryUpdateAnimalNameResult
+
+	ExecUpdateTestField() *QueryUpdateTestFieldResult
+	ExecUpdateTestFieldCtx(ctx context.Context) *QueryUpdateTestFieldResult
+	ExecUpdateTestFieldTx(tx *sql.Tx) *QueryUpdateTestFieldResult
+	ExecUpdateTestFieldCtxTx(ctx context.Context, tx *sql.Tx) *QueryUpdateTestFieldResult
+}
+
+var (
+	_ Querier = (*Client)(nil)
+	_ Querier = (*Fake)(nil)
+)
+
+// reader picks the connection for a read: the primary inside a transaction
+// or under UsePrimary, otherwise the next healthy replica.
+func (c *Client) reader(ctx context.Context, tx *sql.Tx) *conn {
+	if tx != nil || len(c.replicas) == 0 || (ctx != nil && ctx.Value(primaryCtxKey{}) != nil) {
+		return c.primary
+	}
+	now := time.Now().UnixNano()
+	start := c.next.Add(1)
+	for i := range c.replicas {
+		n := c.replicas[(start+uint64(i))%uint64(len(c.replicas))]
+		if n.downUntil.Load() <= now {
+			return n
+		}
+	}
+	return c.primary
+}
+
+type primaryCtxKey struct{}
+
+// UsePrimary returns a context whose reads are sent to the primary, so they
+// observe the caller's own writes. It applies to every entity package too.
+func UsePrimary(ctx context.Context) context.Context {
+	ctx = AllTypes.UsePrimary(ctx)
+	ctx = Alpha.UsePrimary(ctx)
+	ctx = Beta.UsePrimary(ctx)
+	return context.WithValue(ctx, primaryCtxKey{}, true)
+}
+
+// SetDB points the package-level functions of this package and of every
+// entity package at primary and, for reads, at replicas.
+func SetDB(primary *sql.DB, replicas ...*sql.DB) error {
+	c, err := NewClient(primary, replicas...)
+	if err != nil {
+		return err
+	}
+	SetClient(c)
 	return nil
 }
 
-func NewTx() (*sql.Tx, error) {
-	if db == nil {
+// SetClient makes c the client behind the package-level functions of this
+// package and of every entity package.
+func SetClient(c *Client) {
+	defaultClient = c
+	AllTypes.SetClient(c.AllTypes)
+	Alpha.SetClient(c.Alpha)
+	Beta.SetClient(c.Beta)
+}
+
+// SetHook sets the hook of the client behind the package-level functions of
+// this package and of every entity package.
+func SetHook(h QueryHook) {
+	defaultClient.SetHook(h)
+}
+
+func decodeQueries() error {
+	queriesOnce.Do(func() {
+		for _, q := range queries {
+			b, err := base64.StdEncoding.DecodeString(q.QueryEncoded)
+			if err != nil {
+				queriesErr = err
+				return
+			}
+			q.Query = string(b)
+		}
+	})
+	return queriesErr
+}
+
+func (c *Client) NewTx() (*sql.Tx, error) {
+	if c.primary.db == nil {
 		return nil, errors.New("db not initialized")
 	}
-	return db.Begin()
+	return c.primary.db.Begin()
 }
 
-func NewCtxTx(ctx context.Context) (*sql.Tx, error) {
-	if db == nil {
+func (c *Client) NewCtxTx(ctx context.Context) (*sql.Tx, error) {
+	if c.primary.db == nil {
 		return nil, errors.New("db not initialized")
 	}
-	return db.BeginTx(ctx, nil)
+	return c.primary.db.BeginTx(ctx, nil)
 }
 
-func NewTxOpts(opts *sql.TxOptions) (*sql.Tx, error) {
-	if db == nil {
+func (c *Client) NewTxOpts(opts *sql.TxOptions) (*sql.Tx, error) {
+	if c.primary.db == nil {
 		return nil, errors.New("db not initialized")
 	}
-	return db.BeginTx(context.Background(), opts)
+	return c.primary.db.BeginTx(context.Background(), opts)
 }
 
-func NewCtxTxOpts(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
-	if db == nil {
+func (c *Client) NewCtxTxOpts(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
+	if c.primary.db == nil {
 		return nil, errors.New("db not initialized")
 	}
-	return db.BeginTx(ctx, opts)
+	return c.primary.db.BeginTx(ctx, opts)
+}
+
+func NewTx() (*sql.Tx, error) {
+	return defaultClient.NewTx()
+}
+
+func NewCtxTx(ctx context.Context) (*sql.Tx, error) {
+	return defaultClient.NewCtxTx(ctx)
+}
+
+func NewTxOpts(opts *sql.TxOptions) (*sql.Tx, error) {
+	return defaultClient.NewTxOpts(opts)
+}
+
+func NewCtxTxOpts(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
+	return defaultClient.NewCtxTxOpts(ctx, opts)
+}
+
+// isRetryableTxError reports a deadlock (1213) or a lock wait timeout (1205),
+// after which MariaDB has rolled the transaction back and it can be rerun.
+func isRetryableTxError(err error) bool {
+	switch Shared.ErrorCode(err) {
+	case 1213, 1205:
+		return true
+	}
+	return false
+}
+
+// Tx is a transaction whose nested begins are savepoints, so helpers that
+// need transactional semantics compose inside an outer transaction. Pass
+// Tx.Tx to the generated *Tx and *CtxTx methods. A Tx is not safe for
+// concurrent use.
+type Tx struct {
+	*sql.Tx
+	ctx       context.Context
+	root      *Tx
+	savepoint string
+	seq       int
+	done      bool
+}
+
+// BeginTx starts a transaction on the primary that can be nested with Begin.
+func (c *Client) BeginTx(ctx context.Context, opts *sql.TxOptions) (*Tx, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	tx, err := c.NewCtxTxOpts(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	t := &Tx{Tx: tx, ctx: ctx}
+	t.root = t
+	return t, nil
+}
+
+func BeginTx(ctx context.Context, opts *sql.TxOptions) (*Tx, error) {
+	return defaultClient.BeginTx(ctx, opts)
+}
+
+// Begin starts a nested transaction of t by setting a savepoint.
+func (t *Tx) Begin(ctx context.Context) (*Tx, error) {
+	if t.done {
+		return nil, sql.ErrTxDone
+	}
+	if ctx == nil {
+		ctx = t.ctx
+	}
+	t.root.seq++
+	name := "margo_sp_" + strconv.Itoa(t.root.seq)
+	if _, err := t.Tx.ExecContext(ctx, "SAVEPOINT "+name); err != nil {
+		return nil, err
+	}
+	return &Tx{Tx: t.Tx, ctx: ctx, root: t.root, savepoint: name}, nil
+}
+
+// Nested reports whether t is a savepoint inside another transaction.
+func (t *Tx) Nested() bool {
+	return t.savepoint != ""
+}
+
+// Commit commits the outermost transaction, or releases the savepoint of a
+// nested one, leaving its changes to the enclosing transaction.
+func (t *Tx) Commit() error {
+	if t.done {
+		return sql.ErrTxDone
+	}
+	t.done = true
+	if t.savepoint == "" {
+		return t.Tx.Commit()
+	}
+	_, err := t.Tx.ExecContext(t.ctx, "RELEASE SAVEPOINT "+t.savepoint)
+	return err
+}
+
+// Rollback rolls back the outermost transaction, or undoes everything since
+// the savepoint of a nested one, leaving the enclosing transaction usable.
+func (t *Tx) Rollback() error {
+	if t.done {
+		return sql.ErrTxDone
+	}
+	t.done = true
+	if t.savepoint == "" {
+		return t.Tx.Rollback()
+	}
+	_, err := t.Tx.ExecContext(t.ctx, "ROLLBACK TO SAVEPOINT "+t.savepoint)
+	if err == nil {
+		_, err = t.Tx.ExecContext(t.ctx, "RELEASE SAVEPOINT "+t.savepoint)
+	}
+	return err
+}
+
+// WithTx runs fn inside a transaction on the primary, committing when fn
+// returns nil and rolling back otherwise. A panic in fn is rolled back and
+// returned as an error. When fn or the commit fails with a deadlock or lock
+// wait timeout the whole transaction is retried, up to c.TxMaxAttempts runs in
+// total with c.TxBackoff between them, so fn must be safe to run again.
+func (c *Client) WithTx(ctx context.Context, opts *sql.TxOptions, fn func(tx *Tx) error) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	maxAttempts, backoff := c.TxMaxAttempts, c.TxBackoff
+	if maxAttempts <= 0 {
+		maxAttempts = defaultTxMaxAttempts
+	}
+	if backoff == nil {
+		backoff = defaultTxBackoff
+	}
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = runTx(func() (*Tx, error) { return c.BeginTx(ctx, opts) }, fn)
+		if err == nil || !isRetryableTxError(err) || attempt >= maxAttempts {
+			return err
+		}
+		timer := time.NewTimer(backoff(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return errors.Join(err, ctx.Err())
+		case <-timer.C:
+		}
+	}
 }
 
-func getPreparedStmt(query string) (*sql.Stmt, error) {
-	stmtMu.RLock()
-	if stmt, ok := stmtCache[query]; ok {
-		stmtMu.RUnlock()
+func WithTx(ctx context.Context, opts *sql.TxOptions, fn func(tx *Tx) error) error {
+	return defaultClient.WithTx(ctx, opts, fn)
+}
+
+// WithTx runs fn in a savepoint of t, releasing it when fn returns nil and
+// rolling back to it otherwise. It is not retried on its own: a deadlock
+// aborts the whole transaction, which the outermost WithTx retries.
+func (t *Tx) WithTx(ctx context.Context, fn func(tx *Tx) error) error {
+	return runTx(func() (*Tx, error) { return t.Begin(ctx) }, fn)
+}
+
+func runTx(begin func() (*Tx, error), fn func(tx *Tx) error) (err error) {
+	tx, err := begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			if perr, ok := r.(error); ok {
+				err = fmt.Errorf("panic in transaction: %w", perr)
+			} else {
+				err = fmt.Errorf("panic in transaction: %v", r)
+			}
+		}
+		if err != nil {
+			rerr := tx.Rollback()
+			// A deadlock rolls back the whole transaction, taking the savepoint
+			// of a nested one with it (1305); the outermost WithTx retries.
+			if Shared.ErrorCode(err) == 1213 && Shared.ErrorCode(rerr) == 1305 {
+				rerr = nil
+			}
+			if rerr != nil && !errors.Is(rerr, sql.ErrTxDone) {
+				err = errors.Join(err, rerr)
+			}
+		}
+	}()
+	if err = fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (n *conn) getPreparedStmt(query string) (*sql.Stmt, error) {
+	if n.db == nil {
+		return nil, errors.New("db not initialized")
+	}
+	n.stmtMu.RLock()
+	if stmt, ok := n.stmtCache[query]; ok {
+		n.stmtMu.RUnlock()
 		return stmt, nil
 	}
-	stmtMu.RUnlock()
+	n.stmtMu.RUnlock()
 
-	stmtMu.Lock()
-	defer stmtMu.Unlock()
-	if stmt, ok := stmtCache[query]; ok {
+	n.stmtMu.Lock()
+	defer n.stmtMu.Unlock()
+	if stmt, ok := n.stmtCache[query]; ok {
 		return stmt, nil
 	}
-	stmt, err := db.Prepare(query)
+	stmt, err := n.db.Prepare(query)
 	if err != nil {
 		return nil, err
 	}
-	stmtCache[query] = stmt
+	n.stmtCache[query] = stmt
 	return stmt, nil
 }
 
@@ -136,6 +703,22 @@ func bindStmtCtxTx(base *sql.Stmt, ctx context.Context, tx *sql.Tx) (*sql.Stmt,
 	return tx.Stmt(base), true
 }
 
+// scanOne scans the only row of the :one query name into dest. It reports
+// false when there is no row and fails with ErrCardinality when there is
+// more than one.
+func scanOne(rows *sql.Rows, name string, dest ...any) (bool, error) {
+	if !rows.Next() {
+		return false, rows.Err()
+	}
+	if err := rows.Scan(dest...); err != nil {
+		return false, err
+	}
+	if rows.Next() {
+		return false, fmt.Errorf("%w: %s is %s but returned more than one row", ErrCardinality, name, CardinalityOne)
+	}
+	return true, rows.Err()
+}
+
 type QueryCountBigNumbersResultInner struct {
 	Count string
 }
@@ -147,10 +730,21 @@ type QueryCountBigNumbersResult struct {
 	Exists bool
 }
 
-func queryCountBigNumbers(ctx context.Context, tx *sql.Tx, params *QueryParams) (qr *QueryCountBigNumbersResult) {
+func (c *Client) queryCountBigNumbers(ctx context.Context, tx *sql.Tx) (qr *QueryCountBigNumbersResult) {
 	qr = &QueryCountBigNumbersResult{}
 	q := queries["CountBigNumbers"]
-	base, err := getPreparedStmt(q.Query)
+	n := c.reader(ctx, tx)
+	ctx, done := n.trace(ctx, "query", "CountBigNumbers", q.Query, 0)
+	defer func() {
+		n.report(qr.Error)
+		qr.Error = Shared.WrapError(qr.Error)
+		rows := int64(0)
+		if qr.Exists {
+			rows = 1
+		}
+		done(rows, qr.Error)
+	}()
+	base, err := n.getPreparedStmt(q.Query)
 	if err != nil {
 		qr.Error = err
 		return
@@ -165,19 +759,31 @@ func queryCountBigNumbers(ctx context.Context, tx *sql.Tx, params *QueryParams)
 		}()
 	}
 
-	var ptrCount *string
+	var rows *sql.Rows
 	if ctx != nil {
-		err = stmt.QueryRowContext(ctx).Scan(&ptrCount)
+		rows, err = stmt.QueryContext(ctx)
 	} else {
-		err = stmt.QueryRow().Scan(&ptrCount)
+		rows, err = stmt.Query()
 	}
-	if errors.Is(err, sql.ErrNoRows) {
+	if err != nil {
+		qr.Error = err
 		return
 	}
+	defer func() {
+		if cerr := rows.Close(); cerr != nil && qr.Error == nil {
+			qr.Error = cerr
+		}
+	}()
+
+	var ptrCount *string
+	found, err := scanOne(rows, "CountBigNumbers", &ptrCount)
 	if err != nil {
 		qr.Error = err
 		return
 	}
+	if !found {
+		return
+	}
 
 	x := &QueryCountBigNumbersResultInner{}
 	if ptrCount != nil {
@@ -190,26 +796,48 @@ func queryCountBigNumbers(ctx context.Context, tx *sql.Tx, params *QueryParams)
 	return
 }
 
-func QueryCountBigNumbers() *QueryCountBigNumbersResult { return queryCountBigNumbers(nil, nil, nil) }
+func QueryCountBigNumbers() *QueryCountBigNumbersResult {
+	return defaultClient.queryCountBigNumbers(nil, nil)
+}
 func QueryCountBigNumbersCtx(ctx context.Context) *QueryCountBigNumbersResult {
-	return queryCountBigNumbers(ctx, nil, nil)
+	return defaultClient.queryCountBigNumbers(ctx, nil)
 }
 func QueryCountBigNumbersTx(tx *sql.Tx) *QueryCountBigNumbersResult {
-	return queryCountBigNumbers(nil, tx, nil)
+	return defaultClient.queryCountBigNumbers(nil, tx)
 }
 func QueryCountBigNumbersCtxTx(ctx context.Context, tx *sql.Tx) *QueryCountBigNumbersResult {
-	return queryCountBigNumbers(ctx, tx, nil)
+	return defaultClient.queryCountBigNumbers(ctx, tx)
+}
+func (c *Client) QueryCountBigNumbers() *QueryCountBigNumbersResult {
+	return c.queryCountBigNumbers(nil, nil)
+}
+func (c *Client) QueryCountBigNumbersCtx(ctx context.Context) *QueryCountBigNumbersResult {
+	return c.queryCountBigNumbers(ctx, nil)
+}
+func (c *Client) QueryCountBigNumbersTx(tx *sql.Tx) *QueryCountBigNumbersResult {
+	return c.queryCountBigNumbers(nil, tx)
+}
+func (c *Client) QueryCountBigNumbersCtxTx(ctx context.Context, tx *sql.Tx) *QueryCountBigNumbersResult {
+	return c.queryCountBigNumbers(ctx, tx)
 }
 
 type QueryDeleteByUuidResult struct {
-	Error  error
-	Result sql.Result
+	Error        error
+	Result       sql.Result
+	RowsAffected int64
 }
 
-func queryDeleteByUuid(ctx context.Context, tx *sql.Tx, params *QueryParams) (qr *QueryDeleteByUuidResult) {
+func (c *Client) queryDeleteByUuid(ctx context.Context, tx *sql.Tx, uuid string) (qr *QueryDeleteByUuidResult) {
+	args := []any{uuid}
 	qr = &QueryDeleteByUuidResult{}
 	q := queries["DeleteByUuid"]
-	base, err := getPreparedStmt(q.Query)
+	n := c.primary
+	ctx, done := n.trace(ctx, "exec", "DeleteByUuid", q.Query, len(args))
+	defer func() {
+		qr.Error = Shared.WrapError(qr.Error)
+		done(resultRows(qr.Result), qr.Error)
+	}()
+	base, err := n.getPreparedStmt(q.Query)
 	if err != nil {
 		qr.Error = err
 		return
@@ -226,37 +854,60 @@ func queryDeleteByUuid(ctx context.Context, tx *sql.Tx, params *QueryParams) (qr
 
 	var res sql.Result
 	if ctx != nil {
-		res, err = stmt.ExecContext(ctx, params.Params...)
+		res, err = stmt.ExecContext(ctx, args...)
 	} else {
-		res, err = stmt.Exec(params.Params...)
+		res, err = stmt.Exec(args...)
 	}
 	qr.Result = res
-	qr.Error = err
+	if err != nil {
+		qr.Error = err
+		return
+	}
+	qr.RowsAffected, qr.Error = res.RowsAffected()
 	return
 }
 
-func ExecDeleteByUuid(params *QueryParams) *QueryDeleteByUuidResult {
-	return queryDeleteByUuid(nil, nil, params)
+func ExecDeleteByUuid(uuid string) *QueryDeleteByUuidResult {
+	return defaultClient.queryDeleteByUuid(nil, nil, uuid)
+}
+func ExecDeleteByUuidCtx(ctx context.Context, uuid string) *QueryDeleteByUuidResult {
+	return defaultClient.queryDeleteByUuid(ctx, nil, uuid)
 }
-func ExecDeleteByUuidCtx(ctx context.Context, params *QueryParams) *QueryDeleteByUuidResult {
-	return queryDeleteByUuid(ctx, nil, params)
+func ExecDeleteByUuidTx(tx *sql.Tx, uuid string) *QueryDeleteByUuidResult {
+	return defaultClient.queryDeleteByUuid(nil, tx, uuid)
 }
-func ExecDeleteByUuidTx(tx *sql.Tx, params *QueryParams) *QueryDeleteByUuidResult {
-	return queryDeleteByUuid(nil, tx, params)
+func ExecDeleteByUuidCtxTx(ctx context.Context, tx *sql.Tx, uuid string) *QueryDeleteByUuidResult {
+	return defaultClient.queryDeleteByUuid(ctx, tx, uuid)
 }
-func ExecDeleteByUuidCtxTx(ctx context.Context, tx *sql.Tx, params *QueryParams) *QueryDeleteByUuidResult {
-	return queryDeleteByUuid(ctx, tx, params)
+func (c *Client) ExecDeleteByUuid(uuid string) *QueryDeleteByUuidResult {
+	return c.queryDeleteByUuid(nil, nil, uuid)
+}
+func (c *Client) ExecDeleteByUuidCtx(ctx context.Context, uuid string) *QueryDeleteByUuidResult {
+	return c.queryDeleteByUuid(ctx, nil, uuid)
+}
+func (c *Client) ExecDeleteByUuidTx(tx *sql.Tx, uuid string) *QueryDeleteByUuidResult {
+	return c.queryDeleteByUuid(nil, tx, uuid)
+}
+func (c *Client) ExecDeleteByUuidCtxTx(ctx context.Context, tx *sql.Tx, uuid string) *QueryDeleteByUuidResult {
+	return c.queryDeleteByUuid(ctx, tx, uuid)
 }
 
 type QueryDeleteOldRowsResult struct {
-	Error  error
-	Result sql.Result
+	Error        error
+	Result       sql.Result
+	RowsAffected int64
 }
 
-func queryDeleteOldRows(ctx context.Context, tx *sql.Tx, params *QueryParams) (qr *QueryDeleteOldRowsResult) {
+func (c *Client) queryDeleteOldRows(ctx context.Context, tx *sql.Tx) (qr *QueryDeleteOldRowsResult) {
 	qr = &QueryDeleteOldRowsResult{}
 	q := queries["DeleteOldRows"]
-	base, err := getPreparedStmt(q.Query)
+	n := c.primary
+	ctx, done := n.trace(ctx, "exec", "DeleteOldRows", q.Query, 0)
+	defer func() {
+		qr.Error = Shared.WrapError(qr.Error)
+		done(resultRows(qr.Result), qr.Error)
+	}()
+	base, err := n.getPreparedStmt(q.Query)
 	if err != nil {
 		qr.Error = err
 		return
@@ -278,24 +929,42 @@ func queryDeleteOldRows(ctx context.Context, tx *sql.Tx, params *QueryParams) (q
 		res, err = stmt.Exec()
 	}
 	qr.Result = res
-	qr.Error = err
+	if err != nil {
+		qr.Error = err
+		return
+	}
+	qr.RowsAffected, qr.Error = res.RowsAffected()
 	return
 }
 
-func ExecDeleteOldRows() *QueryDeleteOldRowsResult { return queryDeleteOldRows(nil, nil, nil) }
+func ExecDeleteOldRows() *QueryDeleteOldRowsResult {
+	return defaultClient.queryDeleteOldRows(nil, nil)
+}
 func ExecDeleteOldRowsCtx(ctx context.Context) *QueryDeleteOldRowsResult {
-	return queryDeleteOldRows(ctx, nil, nil)
+	return defaultClient.queryDeleteOldRows(ctx, nil)
 }
 func ExecDeleteOldRowsTx(tx *sql.Tx) *QueryDeleteOldRowsResult {
-	return queryDeleteOldRows(nil, tx, nil)
+	return defaultClient.queryDeleteOldRows(nil, tx)
 }
 func ExecDeleteOldRowsCtxTx(ctx context.Context, tx *sql.Tx) *QueryDeleteOldRowsResult {
-	return queryDeleteOldRows(ctx, tx, nil)
+	return defaultClient.queryDeleteOldRows(ctx, tx)
+}
+func (c *Client) ExecDeleteOldRows() *QueryDeleteOldRowsResult {
+	return c.queryDeleteOldRows(nil, nil)
+}
+func (c *Client) ExecDeleteOldRowsCtx(ctx context.Context) *QueryDeleteOldRowsResult {
+	return c.queryDeleteOldRows(ctx, nil)
+}
+func (c *Client) ExecDeleteOldRowsTx(tx *sql.Tx) *QueryDeleteOldRowsResult {
+	return c.queryDeleteOldRows(nil, tx)
+}
+func (c *Client) ExecDeleteOldRowsCtxTx(ctx context.Context, tx *sql.Tx) *QueryDeleteOldRowsResult {
+	return c.queryDeleteOldRows(ctx, tx)
 }
 
 type QueryGetByUuidResultInner struct {
 	Animal    string
-	TestField string
+	TestField Null[string]
 }
 
 type QueryGetByUuidResult struct {
@@ -305,10 +974,22 @@ type QueryGetByUuidResult struct {
 	Exists bool
 }
 
-func queryGetByUuid(ctx context.Context, tx *sql.Tx, params *QueryParams) (qr *QueryGetByUuidResult) {
+func (c *Client) queryGetByUuid(ctx context.Context, tx *sql.Tx, uuid string) (qr *QueryGetByUuidResult) {
+	args := []any{uuid}
 	qr = &QueryGetByUuidResult{}
 	q := queries["GetByUuid"]
-	base, err := getPreparedStmt(q.Query)
+	n := c.reader(ctx, tx)
+	ctx, done := n.trace(ctx, "query", "GetByUuid", q.Query, len(args))
+	defer func() {
+		n.report(qr.Error)
+		qr.Error = Shared.WrapError(qr.Error)
+		rows := int64(0)
+		if qr.Exists {
+			rows = 1
+		}
+		done(rows, qr.Error)
+	}()
+	base, err := n.getPreparedStmt(q.Query)
 	if err != nil {
 		qr.Error = err
 		return
@@ -323,20 +1004,32 @@ func queryGetByUuid(ctx context.Context, tx *sql.Tx, params *QueryParams) (qr *Q
 		}()
 	}
 
-	var ptrAnimal *string
-	var ptrTestField *string
+	var rows *sql.Rows
 	if ctx != nil {
-		err = stmt.QueryRowContext(ctx, params.Params...).Scan(&ptrAnimal, &ptrTestField)
+		rows, err = stmt.QueryContext(ctx, args...)
 	} else {
-		err = stmt.QueryRow(params.Params...).Scan(&ptrAnimal, &ptrTestField)
+		rows, err = stmt.Query(args...)
 	}
-	if errors.Is(err, sql.ErrNoRows) {
+	if err != nil {
+		qr.Error = err
 		return
 	}
+	defer func() {
+		if cerr := rows.Close(); cerr != nil && qr.Error == nil {
+			qr.Error = cerr
+		}
+	}()
+
+	var ptrAnimal *string
+	var ptrTestField *string
+	found, err := scanOne(rows, "GetByUuid", &ptrAnimal, &ptrTestField)
 	if err != nil {
 		qr.Error = err
 		return
 	}
+	if !found {
+		return
+	}
 
 	x := &QueryGetByUuidResultInner{}
 	if ptrAnimal != nil {
@@ -345,26 +1038,36 @@ func queryGetByUuid(ctx context.Context, tx *sql.Tx, params *QueryParams) (qr *Q
 		x.Animal = ""
 	}
 	if ptrTestField != nil {
-		x.TestField = *ptrTestField
-	} else {
-		x.TestField = ""
+		x.TestField = NewNull(*ptrTestField)
 	}
 	qr.Entity = x
 	qr.Exists = true
 	return
 }
 
-func QueryGetByUuid(params *QueryParams) *QueryGetByUuidResult {
-	return queryGetByUuid(nil, nil, params)
+func QueryGetByUuid(uuid string) *QueryGetByUuidResult {
+	return defaultClient.queryGetByUuid(nil, nil, uuid)
+}
+func QueryGetByUuidCtx(ctx context.Context, uuid string) *QueryGetByUuidResult {
+	return defaultClient.queryGetByUuid(ctx, nil, uuid)
 }
-func QueryGetByUuidCtx(ctx context.Context, params *QueryParams) *QueryGetByUuidResult {
-	return queryGetByUuid(ctx, nil, params)
+func QueryGetByUuidTx(tx *sql.Tx, uuid string) *QueryGetByUuidResult {
+	return defaultClient.queryGetByUuid(nil, tx, uuid)
 }
-func QueryGetByUuidTx(tx *sql.Tx, params *QueryParams) *QueryGetByUuidResult {
-	return queryGetByUuid(nil, tx, params)
+func QueryGetByUuidCtxTx(ctx context.Context, tx *sql.Tx, uuid string) *QueryGetByUuidResult {
+	return defaultClient.queryGetByUuid(ctx, tx, uuid)
 }
-func QueryGetByUuidCtxTx(ctx context.Context, tx *sql.Tx, params *QueryParams) *QueryGetByUuidResult {
-	return queryGetByUuid(ctx, tx, params)
+func (c *Client) QueryGetByUuid(uuid string) *QueryGetByUuidResult {
+	return c.queryGetByUuid(nil, nil, uuid)
+}
+func (c *Client) QueryGetByUuidCtx(ctx context.Context, uuid string) *QueryGetByUuidResult {
+	return c.queryGetByUuid(ctx, nil, uuid)
+}
+func (c *Client) QueryGetByUuidTx(tx *sql.Tx, uuid string) *QueryGetByUuidResult {
+	return c.queryGetByUuid(nil, tx, uuid)
+}
+func (c *Client) QueryGetByUuidCtxTx(ctx context.Context, tx *sql.Tx, uuid string) *QueryGetByUuidResult {
+	return c.queryGetByUuid(ctx, tx, uuid)
 }
 
 type QueryGetRecentCatsResultInner struct {
@@ -378,10 +1081,17 @@ type QueryGetRecentCatsResult struct {
 	Result   sql.Result
 }
 
-func queryGetRecentCats(ctx context.Context, tx *sql.Tx, params *QueryParams) (qr *QueryGetRecentCatsResult) {
+func (c *Client) queryGetRecentCats(ctx context.Context, tx *sql.Tx) (qr *QueryGetRecentCatsResult) {
 	qr = &QueryGetRecentCatsResult{}
 	q := queries["GetRecentCats"]
-	base, err := getPreparedStmt(q.Query)
+	n := c.reader(ctx, tx)
+	ctx, done := n.trace(ctx, "query", "GetRecentCats", q.Query, 0)
+	defer func() {
+		n.report(qr.Error)
+		qr.Error = Shared.WrapError(qr.Error)
+		done(int64(len(qr.Entities)), qr.Error)
+	}()
+	base, err := n.getPreparedStmt(q.Query)
 	if err != nil {
 		qr.Error = err
 		return
@@ -439,15 +1149,29 @@ func queryGetRecentCats(ctx context.Context, tx *sql.Tx, params *QueryParams) (q
 	return
 }
 
-func QueryGetRecentCats() *QueryGetRecentCatsResult { return queryGetRecentCats(nil, nil, nil) }
+func QueryGetRecentCats() *QueryGetRecentCatsResult {
+	return defaultClient.queryGetRecentCats(nil, nil)
+}
 func QueryGetRecentCatsCtx(ctx context.Context) *QueryGetRecentCatsResult {
-	return queryGetRecentCats(ctx, nil, nil)
+	return defaultClient.queryGetRecentCats(ctx, nil)
 }
 func QueryGetRecentCatsTx(tx *sql.Tx) *QueryGetRecentCatsResult {
-	return queryGetRecentCats(nil, tx, nil)
+	return defaultClient.queryGetRecentCats(nil, tx)
 }
 func QueryGetRecentCatsCtxTx(ctx context.Context, tx *sql.Tx) *QueryGetRecentCatsResult {
-	return queryGetRecentCats(ctx, tx, nil)
+	return defaultClient.queryGetRecentCats(ctx, tx)
+}
+func (c *Client) QueryGetRecentCats() *QueryGetRecentCatsResult {
+	return c.queryGetRecentCats(nil, nil)
+}
+func (c *Client) QueryGetRecentCatsCtx(ctx context.Context) *QueryGetRecentCatsResult {
+	return c.queryGetRecentCats(ctx, nil)
+}
+func (c *Client) QueryGetRecentCatsTx(tx *sql.Tx) *QueryGetRecentCatsResult {
+	return c.queryGetRecentCats(nil, tx)
+}
+func (c *Client) QueryGetRecentCatsCtxTx(ctx context.Context, tx *sql.Tx) *QueryGetRecentCatsResult {
+	return c.queryGetRecentCats(ctx, tx)
 }
 
 type QueryInsertHardcodedResult struct {
@@ -455,10 +1179,16 @@ type QueryInsertHardcodedResult struct {
 	Result sql.Result
 }
 
-func queryInsertHardcoded(ctx context.Context, tx *sql.Tx, params *QueryParams) (qr *QueryInsertHardcodedResult) {
+func (c *Client) queryInsertHardcoded(ctx context.Context, tx *sql.Tx) (qr *QueryInsertHardcodedResult) {
 	qr = &QueryInsertHardcodedResult{}
 	q := queries["InsertHardcoded"]
-	base, err := getPreparedStmt(q.Query)
+	n := c.primary
+	ctx, done := n.trace(ctx, "exec", "InsertHardcoded", q.Query, 0)
+	defer func() {
+		qr.Error = Shared.WrapError(qr.Error)
+		done(resultRows(qr.Result), qr.Error)
+	}()
+	base, err := n.getPreparedStmt(q.Query)
 	if err != nil {
 		qr.Error = err
 		return
@@ -484,15 +1214,29 @@ func queryInsertHardcoded(ctx context.Context, tx *sql.Tx, params *QueryParams)
 	return
 }
 
-func ExecInsertHardcoded() *QueryInsertHardcodedResult { return queryInsertHardcoded(nil, nil, nil) }
+func ExecInsertHardcoded() *QueryInsertHardcodedResult {
+	return defaultClient.queryInsertHardcoded(nil, nil)
+}
 func ExecInsertHardcodedCtx(ctx context.Context) *QueryInsertHardcodedResult {
-	return queryInsertHardcoded(ctx, nil, nil)
+	return defaultClient.queryInsertHardcoded(ctx, nil)
 }
 func ExecInsertHardcodedTx(tx *sql.Tx) *QueryInsertHardcodedResult {
-	return queryInsertHardcoded(nil, tx, nil)
+	return defaultClient.queryInsertHardcoded(nil, tx)
 }
 func ExecInsertHardcodedCtxTx(ctx context.Context, tx *sql.Tx) *QueryInsertHardcodedResult {
-	return queryInsertHardcoded(ctx, tx, nil)
+	return defaultClient.queryInsertHardcoded(ctx, tx)
+}
+func (c *Client) ExecInsertHardcoded() *QueryInsertHardcodedResult {
+	return c.queryInsertHardcoded(nil, nil)
+}
+func (c *Client) ExecInsertHardcodedCtx(ctx context.Context) *QueryInsertHardcodedResult {
+	return c.queryInsertHardcoded(ctx, nil)
+}
+func (c *Client) ExecInsertHardcodedTx(tx *sql.Tx) *QueryInsertHardcodedResult {
+	return c.queryInsertHardcoded(nil, tx)
+}
+func (c *Client) ExecInsertHardcodedCtxTx(ctx context.Context, tx *sql.Tx) *QueryInsertHardcodedResult {
+	return c.queryInsertHardcoded(ctx, tx)
 }
 
 type QueryInsertOneResult struct {
@@ -500,10 +1244,17 @@ type QueryInsertOneResult struct {
 	Result sql.Result
 }
 
-func queryInsertOne(ctx context.Context, tx *sql.Tx, params *QueryParams) (qr *QueryInsertOneResult) {
+func (c *Client) queryInsertOne(ctx context.Context, tx *sql.Tx, uuid, animal string, testField Null[string]) (qr *QueryInsertOneResult) {
+	args := []any{uuid, animal, testField}
 	qr = &QueryInsertOneResult{}
 	q := queries["InsertOne"]
-	base, err := getPreparedStmt(q.Query)
+	n := c.primary
+	ctx, done := n.trace(ctx, "exec", "InsertOne", q.Query, len(args))
+	defer func() {
+		qr.Error = Shared.WrapError(qr.Error)
+		done(resultRows(qr.Result), qr.Error)
+	}()
+	base, err := n.getPreparedStmt(q.Query)
 	if err != nil {
 		qr.Error = err
 		return
@@ -520,26 +1271,38 @@ func queryInsertOne(ctx context.Context, tx *sql.Tx, params *QueryParams) (qr *Q
 
 	var res sql.Result
 	if ctx != nil {
-		res, err = stmt.ExecContext(ctx, params.Params...)
+		res, err = stmt.ExecContext(ctx, args...)
 	} else {
-		res, err = stmt.Exec(params.Params...)
+		res, err = stmt.Exec(args...)
 	}
 	qr.Result = res
 	qr.Error = err
 	return
 }
 
-func ExecInsertOne(params *QueryParams) *QueryInsertOneResult {
-	return queryInsertOne(nil, nil, params)
+func ExecInsertOne(uuid, animal string, testField Null[string]) *QueryInsertOneResult {
+	return defaultClient.queryInsertOne(nil, nil, uuid, animal, testField)
+}
+func ExecInsertOneCtx(ctx context.Context, uuid, animal string, testField Null[string]) *QueryInsertOneResult {
+	return defaultClient.queryInsertOne(ctx, nil, uuid, animal, testField)
+}
+func ExecInsertOneTx(tx *sql.Tx, uuid, animal string, testField Null[string]) *QueryInsertOneResult {
+	return defaultClient.queryInsertOne(nil, tx, uuid, animal, testField)
+}
+func ExecInsertOneCtxTx(ctx context.Context, tx *sql.Tx, uuid, animal string, testField Null[string]) *QueryInsertOneResult {
+	return defaultClient.queryInsertOne(ctx, tx, uuid, animal, testField)
 }
-func ExecInsertOneCtx(ctx context.Context, params *QueryParams) *QueryInsertOneResult {
-	return queryInsertOne(ctx, nil, params)
+func (c *Client) ExecInsertOne(uuid, animal string, testField Null[string]) *QueryInsertOneResult {
+	return c.queryInsertOne(nil, nil, uuid, animal, testField)
 }
-func ExecInsertOneTx(tx *sql.Tx, params *QueryParams) *QueryInsertOneResult {
-	return queryInsertOne(nil, tx, params)
+func (c *Client) ExecInsertOneCtx(ctx context.Context, uuid, animal string, testField Null[string]) *QueryInsertOneResult {
+	return c.queryInsertOne(ctx, nil, uuid, animal, testField)
 }
-func ExecInsertOneCtxTx(ctx context.Context, tx *sql.Tx, params *QueryParams) *QueryInsertOneResult {
-	return queryInsertOne(ctx, tx, params)
+func (c *Client) ExecInsertOneTx(tx *sql.Tx, uuid, animal string, testField Null[string]) *QueryInsertOneResult {
+	return c.queryInsertOne(nil, tx, uuid, animal, testField)
+}
+func (c *Client) ExecInsertOneCtxTx(ctx context.Context, tx *sql.Tx, uuid, animal string, testField Null[string]) *QueryInsertOneResult {
+	return c.queryInsertOne(ctx, tx, uuid, animal, testField)
 }
 
 type QuerySampleTestResultInner struct {
@@ -555,10 +1318,22 @@ type QuerySampleTestResult struct {
 	Exists bool
 }
 
-func querySampleTest(ctx context.Context, tx *sql.Tx, params *QueryParams) (qr *QuerySampleTestResult) {
+func (c *Client) querySampleTest(ctx context.Context, tx *sql.Tx, userId string) (qr *QuerySampleTestResult) {
+	args := []any{userId}
 	qr = &QuerySampleTestResult{}
 	q := queries["SampleTest"]
-	base, err := getPreparedStmt(q.Query)
+	n := c.reader(ctx, tx)
+	ctx, done := n.trace(ctx, "query", "SampleTest", q.Query, len(args))
+	defer func() {
+		n.report(qr.Error)
+		qr.Error = Shared.WrapError(qr.Error)
+		rows := int64(0)
+		if qr.Exists {
+			rows = 1
+		}
+		done(rows, qr.Error)
+	}()
+	base, err := n.getPreparedStmt(q.Query)
 	if err != nil {
 		qr.Error = err
 		return
@@ -573,21 +1348,33 @@ func querySampleTest(ctx context.Context, tx *sql.Tx, params *QueryParams) (qr *
 		}()
 	}
 
-	var ptrTotalStorageUsed *string
-	var ptrReachedFileLimit *string
-	var ptrExceededStorageLimit *string
+	var rows *sql.Rows
 	if ctx != nil {
-		err = stmt.QueryRowContext(ctx, params.Params...).Scan(&ptrTotalStorageUsed, &ptrReachedFileLimit, &ptrExceededStorageLimit)
+		rows, err = stmt.QueryContext(ctx, args...)
 	} else {
-		err = stmt.QueryRow(params.Params...).Scan(&ptrTotalStorageUsed, &ptrReachedFileLimit, &ptrExceededStorageLimit)
+		rows, err = stmt.Query(args...)
 	}
-	if errors.Is(err, sql.ErrNoRows) {
+	if err != nil {
+		qr.Error = err
 		return
 	}
+	defer func() {
+		if cerr := rows.Close(); cerr != nil && qr.Error == nil {
+			qr.Error = cerr
+		}
+	}()
+
+	var ptrTotalStorageUsed *string
+	var ptrReachedFileLimit *string
+	var ptrExceededStorageLimit *string
+	found, err := scanOne(rows, "SampleTest", &ptrTotalStorageUsed, &ptrReachedFileLimit, &ptrExceededStorageLimit)
 	if err != nil {
 		qr.Error = err
 		return
 	}
+	if !found {
+		return
+	}
 
 	x := &QuerySampleTestResultInner{}
 	if ptrTotalStorageUsed != nil {
@@ -610,28 +1397,48 @@ func querySampleTest(ctx context.Context, tx *sql.Tx, params *QueryParams) (qr *
 	return
 }
 
-func QuerySampleTest(params *QueryParams) *QuerySampleTestResult {
-	return querySampleTest(nil, nil, params)
+func QuerySampleTest(userId string) *QuerySampleTestResult {
+	return defaultClient.querySampleTest(nil, nil, userId)
 }
-func QuerySampleTestCtx(ctx context.Context, params *QueryParams) *QuerySampleTestResult {
-	return querySampleTest(ctx, nil, params)
+func QuerySampleTestCtx(ctx context.Context, userId string) *QuerySampleTestResult {
+	return defaultClient.querySampleTest(ctx, nil, userId)
 }
-func QuerySampleTestTx(tx *sql.Tx, params *QueryParams) *QuerySampleTestResult {
-	return querySampleTest(nil, tx, params)
+func QuerySampleTestTx(tx *sql.Tx, userId string) *QuerySampleTestResult {
+	return defaultClient.querySampleTest(nil, tx, userId)
 }
-func QuerySampleTestCtxTx(ctx context.Context, tx *sql.Tx, params *QueryParams) *QuerySampleTestResult {
-	return querySampleTest(ctx, tx, params)
+func QuerySampleTestCtxTx(ctx context.Context, tx *sql.Tx, userId string) *QuerySampleTestResult {
+	return defaultClient.querySampleTest(ctx, tx, userId)
+}
+func (c *Client) QuerySampleTest(userId string) *QuerySampleTestResult {
+	return c.querySampleTest(nil, nil, userId)
+}
+func (c *Client) QuerySampleTestCtx(ctx context.Context, userId string) *QuerySampleTestResult {
+	return c.querySampleTest(ctx, nil, userId)
+}
+func (c *Client) QuerySampleTestTx(tx *sql.Tx, userId string) *QuerySampleTestResult {
+	return c.querySampleTest(nil, tx, userId)
+}
+func (c *Client) QuerySampleTestCtxTx(ctx context.Context, tx *sql.Tx, userId string) *QuerySampleTestResult {
+	return c.querySampleTest(ctx, tx, userId)
 }
 
 type QueryUpdateAnimalNameResult struct {
-	Error  error
-	Result sql.Result
+	Error        error
+	Result       sql.Result
+	RowsAffected int64
 }
 
-func queryUpdateAnimalName(ctx context.Context, tx *sql.Tx, params *QueryParams) (qr *QueryUpdateAnimalNameResult) {
+func (c *Client) queryUpdateAnimalName(ctx context.Context, tx *sql.Tx, animal, uuid string) (qr *QueryUpdateAnimalNameResult) {
+	args := []any{animal, uuid}
 	qr = &QueryUpdateAnimalNameResult{}
 	q := queries["UpdateAnimalName"]
-	base, err := getPreparedStmt(q.Query)
+	n := c.primary
+	ctx, done := n.trace(ctx, "exec", "UpdateAnimalName", q.Query, len(args))
+	defer func() {
+		qr.Error = Shared.WrapError(qr.Error)
+		done(resultRows(qr.Result), qr.Error)
+	}()
+	base, err := n.getPreparedStmt(q.Query)
 	if err != nil {
 		qr.Error = err
 		return
@@ -648,37 +1455,60 @@ func queryUpdateAnimalName(ctx context.Context, tx *sql.Tx, params *QueryParams)
 
 	var res sql.Result
 	if ctx != nil {
-		res, err = stmt.ExecContext(ctx, params.Params...)
+		res, err = stmt.ExecContext(ctx, args...)
 	} else {
-		res, err = stmt.Exec(params.Params...)
+		res, err = stmt.Exec(args...)
 	}
 	qr.Result = res
-	qr.Error = err
+	if err != nil {
+		qr.Error = err
+		return
+	}
+	qr.RowsAffected, qr.Error = res.RowsAffected()
 	return
 }
 
-func ExecUpdateAnimalName(params *QueryParams) *QueryUpdateAnimalNameResult {
-	return queryUpdateAnimalName(nil, nil, params)
+func ExecUpdateAnimalName(animal, uuid string) *QueryUpdateAnimalNameResult {
+	return defaultClient.queryUpdateAnimalName(nil, nil, animal, uuid)
+}
+func ExecUpdateAnimalNameCtx(ctx context.Context, animal, uuid string) *QueryUpdateAnimalNameResult {
+	return defaultClient.queryUpdateAnimalName(ctx, nil, animal, uuid)
+}
+func ExecUpdateAnimalNameTx(tx *sql.Tx, animal, uuid string) *QueryUpdateAnimalNameResult {
+	return defaultClient.queryUpdateAnimalName(nil, tx, animal, uuid)
+}
+func ExecUpdateAnimalNameCtxTx(ctx context.Context, tx *sql.Tx, animal, uuid string) *QueryUpdateAnimalNameResult {
+	return defaultClient.queryUpdateAnimalName(ctx, tx, animal, uuid)
 }
-func ExecUpdateAnimalNameCtx(ctx context.Context, params *QueryParams) *QueryUpdateAnimalNameResult {
-	return queryUpdateAnimalName(ctx, nil, params)
+func (c *Client) ExecUpdateAnimalName(animal, uuid string) *QueryUpdateAnimalNameResult {
+	return c.queryUpdateAnimalName(nil, nil, animal, uuid)
 }
-func ExecUpdateAnimalNameTx(tx *sql.Tx, params *QueryParams) *QueryUpdateAnimalNameResult {
-	return queryUpdateAnimalName(nil, tx, params)
+func (c *Client) ExecUpdateAnimalNameCtx(ctx context.Context, animal, uuid string) *QueryUpdateAnimalNameResult {
+	return c.queryUpdateAnimalName(ctx, nil, animal, uuid)
 }
-func ExecUpdateAnimalNameCtxTx(ctx context.Context, tx *sql.Tx, params *QueryParams) *QueryUpdateAnimalNameResult {
-	return queryUpdateAnimalName(ctx, tx, params)
+func (c *Client) ExecUpdateAnimalNameTx(tx *sql.Tx, animal, uuid string) *QueryUpdateAnimalNameResult {
+	return c.queryUpdateAnimalName(nil, tx, animal, uuid)
+}
+func (c *Client) ExecUpdateAnimalNameCtxTx(ctx context.Context, tx *sql.Tx, animal, uuid string) *QueryUpdateAnimalNameResult {
+	return c.queryUpdateAnimalName(ctx, tx, animal, uuid)
 }
 
 type QueryUpdateTestFieldResult struct {
-	Error  error
-	Result sql.Result
+	Error        error
+	Result       sql.Result
+	RowsAffected int64
 }
 
-func queryUpdateTestField(ctx context.Context, tx *sql.Tx, params *QueryParams) (qr *QueryUpdateTestFieldResult) {
+func (c *Client) queryUpdateTestField(ctx context.Context, tx *sql.Tx) (qr *QueryUpdateTestFieldResult) {
 	qr = &QueryUpdateTestFieldResult{}
 	q := queries["UpdateTestField"]
-	base, err := getPreparedStmt(q.Query)
+	n := c.primary
+	ctx, done := n.trace(ctx, "exec", "UpdateTestField", q.Query, 0)
+	defer func() {
+		qr.Error = Shared.WrapError(qr.Error)
+		done(resultRows(qr.Result), qr.Error)
+	}()
+	base, err := n.getPreparedStmt(q.Query)
 	if err != nil {
 		qr.Error = err
 		return
@@ -700,17 +1530,35 @@ func queryUpdateTestField(ctx context.Context, tx *sql.Tx, params *QueryParams)
 		res, err = stmt.Exec()
 	}
 	qr.Result = res
-	qr.Error = err
+	if err != nil {
+		qr.Error = err
+		return
+	}
+	qr.RowsAffected, qr.Error = res.RowsAffected()
 	return
 }
 
-func ExecUpdateTestField() *QueryUpdateTestFieldResult { return queryUpdateTestField(nil, nil, nil) }
+func ExecUpdateTestField() *QueryUpdateTestFieldResult {
+	return defaultClient.queryUpdateTestField(nil, nil)
+}
 func ExecUpdateTestFieldCtx(ctx context.Context) *QueryUpdateTestFieldResult {
-	return queryUpdateTestField(ctx, nil, nil)
+	return defaultClient.queryUpdateTestField(ctx, nil)
 }
 func ExecUpdateTestFieldTx(tx *sql.Tx) *QueryUpdateTestFieldResult {
-	return queryUpdateTestField(nil, tx, nil)
+	return defaultClient.queryUpdateTestField(nil, tx)
 }
 func ExecUpdateTestFieldCtxTx(ctx context.Context, tx *sql.Tx) *QueryUpdateTestFieldResult {
-	return queryUpdateTestField(ctx, tx, nil)
+	return defaultClient.queryUpdateTestField(ctx, tx)
+}
+func (c *Client) ExecUpdateTestField() *QueryUpdateTestFieldResult {
+	return c.queryUpdateTestField(nil, nil)
+}
+func (c *Client) ExecUpdateTestFieldCtx(ctx context.Context) *QueryUpdateTestFieldResult {
+	return c.queryUpdateTestField(ctx, nil)
+}
+func (c *Client) ExecUpdateTestFieldTx(tx *sql.Tx) *QueryUpdateTestFieldResult {
+	return c.queryUpdateTestField(nil, tx)
+}
+func (c *Client) ExecUpdateTestFieldCtxTx(ctx context.Context, tx *sql.Tx) *QueryUpdateTestFieldResult {
+	return c.queryUpdateTestField(ctx, tx)
 }
@@ -1,9 +1,12 @@
 package Template
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"strconv"
 	"testing"
+	"time"
 
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/google/uuid"
@@ -11,6 +14,7 @@ import (
 
 	"github.com/rah-0/margo-test/dbs/Template/AllTypes"
 	"github.com/rah-0/margo-test/dbs/Template/Alpha"
+	"github.com/rah-0/margo-test/dbs/Template/Beta"
 	"github.com/rah-0/margo-test/util"
 )
 
@@ -29,9 +33,13 @@ func TestMain(m *testing.M) {
 			if err != nil {
 				return err
 			}
+			if err = util.Migrate(c, "migrations"); err != nil {
+				return err
+			}
 
 			AllTypes.SetDB(c)
 			Alpha.SetDB(c)
+			Beta.SetDB(c)
 
 			return SetDB(c)
 		},
@@ -41,11 +49,16 @@ func TestMain(m *testing.M) {
 				return result1.Error
 			}
 
-			result2 := Alpha.DBTruncate()
+			result2 := Beta.DBTruncate()
 			if result2.Error != nil {
 				return result2.Error
 			}
 
+			result3 := Alpha.DBTruncate()
+			if result3.Error != nil {
+				return result3.Error
+			}
+
 			return c.Close()
 		},
 	})
@@ -60,8 +73,8 @@ func TestQueryGetAllAnimals(t *testing.T) {
 		FirstInsert: "2025-06-30 12:00:00",
 		LastUpdate:  "2025-06-30 12:00:00",
 		Animal:      "cat",
-		BigNumber:   "9000",
-		TestField:   "test",
+		BigNumber:   Alpha.NewNull("9000"),
+		TestField:   Alpha.NewNull("test"),
 	}
 	result := row.DBInsert(Alpha.NewQueryParams().WithInsert(Alpha.Fields...))
 	if result.Error != nil {
@@ -75,7 +88,7 @@ func TestQueryGetAllAnimals(t *testing.T) {
 
 	found := false
 	for _, r := range qr.Entities {
-		if r.Animal == "cat" && r.BigNumber == "9000" {
+		if r.Animal == "cat" && r.BigNumber.V == "9000" {
 			found = true
 			break
 		}
@@ -94,8 +107,8 @@ func TestQueryGetRecentCats(t *testing.T) {
 		FirstInsert: "2025-06-30 12:00:00",
 		LastUpdate:  "2025-06-30 13:00:00",
 		Animal:      "cat",
-		BigNumber:   "12345",
-		TestField:   "recent",
+		BigNumber:   Alpha.NewNull("12345"),
+		TestField:   Alpha.NewNull("recent"),
 	}
 	result := row.DBInsert(Alpha.NewQueryParams().WithInsert(Alpha.Fields...))
 	if result.Error != nil {
@@ -129,8 +142,8 @@ func TestQueryGetByUuid(t *testing.T) {
 		FirstInsert: "2025-06-30 15:00:00",
 		LastUpdate:  "2025-06-30 15:00:00",
 		Animal:      "dog",
-		BigNumber:   "5555",
-		TestField:   "unique",
+		BigNumber:   Alpha.NewNull("5555"),
+		TestField:   Alpha.NewNull("unique"),
 	}
 	result := row.DBInsert(Alpha.NewQueryParams().WithInsert(Alpha.Fields...))
 	if result.Error != nil {
@@ -138,7 +151,7 @@ func TestQueryGetByUuid(t *testing.T) {
 	}
 
 	// Run the query using uuid as argument
-	qr := QueryGetByUuid(NewQueryParams().WithParams(u))
+	qr := QueryGetByUuid(u)
 	if qr.Error != nil {
 		t.Fatal("query failed:", qr.Error)
 	}
@@ -146,7 +159,7 @@ func TestQueryGetByUuid(t *testing.T) {
 	found := false
 	if qr.Entity != nil {
 		r := qr.Entity
-		if r.Animal == "dog" && r.TestField == "unique" {
+		if r.Animal == "dog" && r.TestField == NewNull("unique") {
 			found = true
 		}
 	}
@@ -156,6 +169,27 @@ func TestQueryGetByUuid(t *testing.T) {
 	}
 }
 
+func TestQueryOneCardinality(t *testing.T) {
+	qr := QueryGetByUuid(uuid.NewString())
+	if qr.Error != nil || qr.Exists || qr.Entity != nil {
+		t.Fatalf("expected no row to leave Exists false without an error, got %+v", qr)
+	}
+
+	for _, animal := range []string{"ant", "bee"} {
+		row := &Alpha.Entity{Uuid: uuid.NewString(), Animal: animal}
+		if result := row.DBInsert(Alpha.NewQueryParams().WithInsert(Alpha.FieldUuid, Alpha.FieldAnimal)); result.Error != nil {
+			t.Fatal(result.Error)
+		}
+	}
+	q := queries["GetByUuid"]
+	defer func(query string) { q.Query = query }(q.Query)
+	q.Query = "SELECT `Animal`, `test_field` FROM `alpha` WHERE `Uuid` <> ?"
+	qr = QueryGetByUuid(uuid.NewString())
+	if !errors.Is(qr.Error, ErrCardinality) || qr.Exists || qr.Entity != nil {
+		t.Fatalf("expected ErrCardinality for more than one row, got %+v", qr)
+	}
+}
+
 func TestQueryCountBigNumbers(t *testing.T) {
 	u := uuid.NewString()
 
@@ -165,7 +199,7 @@ func TestQueryCountBigNumbers(t *testing.T) {
 		FirstInsert: "2025-06-30 16:00:00",
 		LastUpdate:  "2025-06-30 16:00:00",
 		Animal:      "nulltest",
-		TestField:   "checknull",
+		TestField:   Alpha.NewNull("checknull"),
 	}
 	result := row.DBInsert(Alpha.NewQueryParams().WithInsert(
 		Alpha.FieldUuid,
@@ -199,32 +233,43 @@ func TestQueryCountBigNumbers(t *testing.T) {
 
 func TestExecInsertOne(t *testing.T) {
 	u := uuid.NewString()
-	qr := ExecInsertOne(NewQueryParams().WithParams(u, "hedgehog", "tf"))
+	qr := ExecInsertOne(u, "hedgehog", NewNull("tf"))
 	if qr.Error != nil {
 		t.Fatal("insert failed:", qr.Error)
 	}
 
-	r := QueryGetByUuid(NewQueryParams().WithParams(u))
+	r := QueryGetByUuid(u)
 	if r.Error != nil {
 		t.Fatal("query failed:", r.Error)
 	}
-	if r.Entity == nil || r.Entity.Animal != "hedgehog" || r.Entity.TestField != "tf" {
+	if r.Entity == nil || r.Entity.Animal != "hedgehog" || r.Entity.TestField != NewNull("tf") {
 		t.Fatalf("row not inserted as expected: %+v", r.Entity)
 	}
+
+	n := uuid.NewString()
+	if qr = ExecInsertOne(n, "hedgehog", Null[string]{}); qr.Error != nil {
+		t.Fatal("insert failed:", qr.Error)
+	}
+	if row := Alpha.DBGetByPK(n); row.Error != nil || !row.Exists || row.Entity.TestField.Valid {
+		t.Fatalf("expected test_field to be NULL, got %+v", row)
+	}
+	if r = QueryGetByUuid(n); r.Error != nil || r.Entity == nil || r.Entity.TestField.Valid {
+		t.Fatalf("expected GetByUuid to keep NULL apart from \"\", got %+v", r.Entity)
+	}
 }
 
 func TestExecInsertHardcoded(t *testing.T) {
 	const hard = "11111111-1111-4111-8111-111111111111"
 
 	// ensure a clean slate for this uuid
-	_ = ExecDeleteByUuid(NewQueryParams().WithParams(hard))
+	_ = ExecDeleteByUuid(hard)
 
 	qr := ExecInsertHardcoded()
 	if qr.Error != nil {
 		t.Fatal("insert hardcoded failed:", qr.Error)
 	}
 
-	r := QueryGetByUuid(NewQueryParams().WithParams(hard))
+	r := QueryGetByUuid(hard)
 	if r.Error != nil {
 		t.Fatal("query failed:", r.Error)
 	}
@@ -240,7 +285,7 @@ func TestExecUpdateAnimalName(t *testing.T) {
 		FirstInsert: "2025-06-30 10:00:00",
 		LastUpdate:  "2025-06-30 10:00:00",
 		Animal:      "cat",
-		TestField:   "x",
+		TestField:   Alpha.NewNull("x"),
 	}
 	result := row.DBInsert(Alpha.NewQueryParams().WithInsert(
 		Alpha.FieldUuid, Alpha.FieldFirstInsert, Alpha.FieldLastUpdate, Alpha.FieldAnimal, Alpha.FieldTestField,
@@ -249,12 +294,15 @@ func TestExecUpdateAnimalName(t *testing.T) {
 		t.Fatal("seed insert failed:", result.Error)
 	}
 
-	qr := ExecUpdateAnimalName(NewQueryParams().WithParams("otter", u))
+	qr := ExecUpdateAnimalName("otter", u)
 	if qr.Error != nil {
 		t.Fatal("update failed:", qr.Error)
 	}
+	if qr.RowsAffected != 1 {
+		t.Fatalf("expected 1 affected row, got %d", qr.RowsAffected)
+	}
 
-	r := QueryGetByUuid(NewQueryParams().WithParams(u))
+	r := QueryGetByUuid(u)
 	if r.Error != nil {
 		t.Fatal("query failed:", r.Error)
 	}
@@ -270,7 +318,7 @@ func TestExecUpdateTestField(t *testing.T) {
 		FirstInsert: "2025-06-30 11:00:00",
 		LastUpdate:  "2025-06-30 11:00:00",
 		Animal:      "fox",
-		TestField:   "old",
+		TestField:   Alpha.NewNull("old"),
 	}
 	result := row.DBInsert(Alpha.NewQueryParams().WithInsert(
 		Alpha.FieldUuid, Alpha.FieldFirstInsert, Alpha.FieldLastUpdate, Alpha.FieldAnimal, Alpha.FieldTestField,
@@ -284,11 +332,11 @@ func TestExecUpdateTestField(t *testing.T) {
 		t.Fatal("update failed:", qr.Error)
 	}
 
-	r := QueryGetByUuid(NewQueryParams().WithParams(u))
+	r := QueryGetByUuid(u)
 	if r.Error != nil {
 		t.Fatal("query failed:", r.Error)
 	}
-	if r.Entity == nil || r.Entity.TestField != "updated" {
+	if r.Entity == nil || r.Entity.TestField != NewNull("updated") {
 		t.Fatalf("expected test_field=updated after bulk update, got: %+v", r.Entity)
 	}
 }
@@ -300,7 +348,7 @@ func TestExecDeleteByUuid(t *testing.T) {
 		FirstInsert: "2025-06-30 12:00:00",
 		LastUpdate:  "2025-06-30 12:00:00",
 		Animal:      "toad",
-		TestField:   "y",
+		TestField:   Alpha.NewNull("y"),
 	}
 	result := row.DBInsert(Alpha.NewQueryParams().WithInsert(
 		Alpha.FieldUuid, Alpha.FieldFirstInsert, Alpha.FieldLastUpdate, Alpha.FieldAnimal, Alpha.FieldTestField,
@@ -309,12 +357,15 @@ func TestExecDeleteByUuid(t *testing.T) {
 		t.Fatal("seed insert failed:", result.Error)
 	}
 
-	qr := ExecDeleteByUuid(NewQueryParams().WithParams(u))
+	qr := ExecDeleteByUuid(u)
 	if qr.Error != nil {
 		t.Fatal("delete failed:", qr.Error)
 	}
+	if qr.RowsAffected != 1 {
+		t.Fatalf("expected 1 affected row, got %d", qr.RowsAffected)
+	}
 
-	r := QueryGetByUuid(NewQueryParams().WithParams(u))
+	r := QueryGetByUuid(u)
 	if r.Error != nil {
 		t.Fatal("query failed:", r.Error)
 	}
@@ -332,14 +383,14 @@ func TestExecDeleteOldRows(t *testing.T) {
 		FirstInsert: "2022-12-31 23:59:59",
 		LastUpdate:  "2022-12-31 23:59:59",
 		Animal:      "ant",
-		TestField:   "old",
+		TestField:   Alpha.NewNull("old"),
 	}
 	newRow := &Alpha.Entity{
 		Uuid:        newU,
 		FirstInsert: "2025-01-01 00:00:01",
 		LastUpdate:  "2025-01-01 00:00:01",
 		Animal:      "bee",
-		TestField:   "new",
+		TestField:   Alpha.NewNull("new"),
 	}
 	result := oldRow.DBInsert(Alpha.NewQueryParams().WithInsert(
 		Alpha.FieldUuid, Alpha.FieldFirstInsert, Alpha.FieldLastUpdate, Alpha.FieldAnimal, Alpha.FieldTestField,
@@ -359,7 +410,7 @@ func TestExecDeleteOldRows(t *testing.T) {
 		t.Fatal("delete old rows failed:", qr.Error)
 	}
 
-	ro := QueryGetByUuid(NewQueryParams().WithParams(oldU))
+	ro := QueryGetByUuid(oldU)
 	if ro.Error != nil {
 		t.Fatal("query old failed:", ro.Error)
 	}
@@ -367,7 +418,7 @@ func TestExecDeleteOldRows(t *testing.T) {
 		t.Fatalf("expected old row to be deleted, got: %+v", ro.Entity)
 	}
 
-	rn := QueryGetByUuid(NewQueryParams().WithParams(newU))
+	rn := QueryGetByUuid(newU)
 	if rn.Error != nil {
 		t.Fatal("query new failed:", rn.Error)
 	}
@@ -375,3 +426,212 @@ func TestExecDeleteOldRows(t *testing.T) {
 		t.Fatalf("expected new row to remain, got: %+v", rn.Entity)
 	}
 }
+
+func TestClientNamedQueries(t *testing.T) {
+	client, err := NewClient(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	u := uuid.NewString()
+	row := &Alpha.Entity{Uuid: u, Animal: "otter", TestField: Alpha.NewNull("client")}
+	result := client.Alpha.DBInsert(row, Alpha.NewQueryParams().WithInsert(Alpha.FieldUuid, Alpha.FieldAnimal, Alpha.FieldTestField))
+	if result.Error != nil {
+		t.Fatal("insert failed:", result.Error)
+	}
+
+	qr := client.QueryGetByUuid(u)
+	if qr.Error != nil {
+		t.Fatal("query failed:", qr.Error)
+	}
+	if qr.Entity == nil || qr.Entity.Animal != "otter" || qr.Entity.TestField != NewNull("client") {
+		t.Errorf("expected the row inserted through the client, got: %+v", qr.Entity)
+	}
+}
+
+type recordingHook struct {
+	before []string
+	after  []QueryEvent
+}
+
+type hookKey struct{}
+
+func (h *recordingHook) BeforeQuery(ctx context.Context, ev *QueryEvent) context.Context {
+	h.before = append(h.before, ev.Name)
+	return context.WithValue(ctx, hookKey{}, ev.Name)
+}
+
+func (h *recordingHook) AfterQuery(ctx context.Context, ev *QueryEvent) {
+	if ctx.Value(hookKey{}) != ev.Name {
+		panic("AfterQuery did not receive the context returned by BeforeQuery")
+	}
+	h.after = append(h.after, *ev)
+}
+
+func TestQueryHook(t *testing.T) {
+	client, err := NewClient(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hook := &recordingHook{}
+	client.SetHook(hook)
+
+	u := uuid.NewString()
+	row := &Alpha.Entity{Uuid: u, Animal: "lynx"}
+	if result := client.Alpha.DBInsert(row, Alpha.NewQueryParams().WithInsert(Alpha.FieldUuid, Alpha.FieldAnimal)); result.Error != nil {
+		t.Fatal(result.Error)
+	}
+	if qr := client.QueryGetByUuidCtx(context.Background(), u); qr.Error != nil || !qr.Exists {
+		t.Fatalf("expected the row, got %+v", qr)
+	}
+
+	if len(hook.before) != 2 || len(hook.after) != 2 {
+		t.Fatalf("expected two statements, got %v", hook.before)
+	}
+	insert, query := hook.after[0], hook.after[1]
+	if insert.Op != "exec" || insert.Name != "Insert" || insert.Table != Alpha.FQTN || insert.Args != 4 || insert.RowsAffected != 1 || insert.Err != nil {
+		t.Errorf("unexpected insert event %+v", insert)
+	}
+	if query.Op != "query" || query.Name != "GetByUuid" || query.Table != "" || query.Args != 1 || query.RowsAffected != 1 || query.Query == "" {
+		t.Errorf("unexpected query event %+v", query)
+	}
+
+	client.SetHook(nil)
+	client.Alpha.DBGetByPK(u)
+	if len(hook.after) != 2 {
+		t.Error("expected no events after removing the hook")
+	}
+}
+
+func TestWithTx(t *testing.T) {
+	client, err := NewClient(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.TxBackoff = func(int) time.Duration { return 0 }
+
+	committed := uuid.NewString()
+	attempts := 0
+	err = client.WithTx(context.Background(), nil, func(tx *Tx) error {
+		attempts++
+		row := &Alpha.Entity{Uuid: committed, Animal: "yak"}
+		if result := row.DBInsertTx(tx.Tx, Alpha.NewQueryParams().WithInsert(Alpha.FieldUuid, Alpha.FieldAnimal)); result.Error != nil {
+			return result.Error
+		}
+		if attempts == 1 {
+			return errors.New("Error 1213 (40001): Deadlock found when trying to get lock; try restarting transaction")
+		}
+		return nil
+	})
+	if err != nil || attempts != 2 {
+		t.Fatalf("expected a retried commit, got attempts=%d err=%v", attempts, err)
+	}
+	if result := Alpha.DBGetByPK(committed); !result.Exists {
+		t.Fatal("expected the retried transaction to be committed")
+	}
+
+	rolledBack := uuid.NewString()
+	err = client.WithTx(context.Background(), nil, func(tx *Tx) error {
+		row := &Alpha.Entity{Uuid: rolledBack, Animal: "yak"}
+		if result := row.DBInsertTx(tx.Tx, Alpha.NewQueryParams().WithInsert(Alpha.FieldUuid, Alpha.FieldAnimal)); result.Error != nil {
+			return result.Error
+		}
+		panic("boom")
+	})
+	if err == nil {
+		t.Fatal("expected the panic to be returned as an error")
+	}
+	if result := Alpha.DBGetByPK(rolledBack); result.Exists {
+		t.Fatal("expected the panicking transaction to be rolled back")
+	}
+
+	client.TxMaxAttempts = 4
+	attempts = 0
+	err = client.WithTx(context.Background(), nil, func(tx *Tx) error {
+		attempts++
+		return errors.New("Error 1205 (HY000): Lock wait timeout exceeded; try restarting transaction")
+	})
+	if err == nil || attempts != client.TxMaxAttempts {
+		t.Fatalf("expected %d attempts, got %d (err=%v)", client.TxMaxAttempts, attempts, err)
+	}
+}
+
+func TestNestedTx(t *testing.T) {
+	kept, discarded := uuid.NewString(), uuid.NewString()
+	insert := func(u string) func(tx *Tx) error {
+		return func(tx *Tx) error {
+			row := &Alpha.Entity{Uuid: u, Animal: "emu"}
+			return row.DBInsertTx(tx.Tx, Alpha.NewQueryParams().WithInsert(Alpha.FieldUuid, Alpha.FieldAnimal)).Error
+		}
+	}
+
+	err := WithTx(context.Background(), nil, func(tx *Tx) error {
+		if err := tx.WithTx(context.Background(), insert(kept)); err != nil {
+			return err
+		}
+		err := tx.WithTx(context.Background(), func(inner *Tx) error {
+			if !inner.Nested() {
+				t.Error("expected a nested transaction")
+			}
+			if err := insert(discarded)(inner); err != nil {
+				return err
+			}
+			return errors.New("discard")
+		})
+		if err == nil {
+			t.Error("expected the inner error to be returned")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result := Alpha.DBGetByPK(kept); !result.Exists {
+		t.Error("expected the released savepoint to be committed")
+	}
+	if result := Alpha.DBGetByPK(discarded); result.Exists {
+		t.Error("expected the savepoint to be rolled back")
+	}
+
+	client, err := NewClient(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.TxBackoff = func(int) time.Duration { return 0 }
+	deadlock := "Error 1213 (40001): Deadlock found when trying to get lock; try restarting transaction"
+	attempts := 0
+	err = client.WithTx(context.Background(), nil, func(tx *Tx) error {
+		attempts++
+		return tx.WithTx(context.Background(), func(inner *Tx) error {
+			if attempts == 1 {
+				return errors.New(deadlock)
+			}
+			return nil
+		})
+	})
+	if err != nil || attempts != 2 {
+		t.Fatalf("expected a nested deadlock to retry the outer transaction, got attempts=%d err=%v", attempts, err)
+	}
+
+	timedOut := uuid.NewString()
+	lockTimeout := "Error 1205 (HY000): Lock wait timeout exceeded; try restarting transaction"
+	err = WithTx(context.Background(), nil, func(tx *Tx) error {
+		err := tx.WithTx(context.Background(), func(inner *Tx) error {
+			if err := insert(timedOut)(inner); err != nil {
+				return err
+			}
+			return errors.New(lockTimeout)
+		})
+		if err == nil || err.Error() != lockTimeout {
+			t.Errorf("expected the lock wait timeout to be returned, got %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result := Alpha.DBGetByPK(timedOut); result.Exists {
+		t.Error("expected the nested writes to be rolled back after a lock wait timeout")
+	}
+}
@@ -0,0 +1,226 @@
+package Template
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/rah-0/margo-test/dbs/Template/Alpha"
+	"github.com/rah-0/margo-test/dbs/Template/Beta"
+)
+
+// countingBetas and countingAlphas count the selects the loaders send.
+type countingBetas struct {
+	Beta.Querier
+	selects int
+}
+
+func (q *countingBetas) DBSelectCtxTx(ctx context.Context, tx *sql.Tx, x *Beta.Entity, params *Beta.QueryParams) *Beta.QueryResult {
+	q.selects++
+	return q.Querier.DBSelectCtxTx(ctx, tx, x, params)
+}
+
+type countingAlphas struct {
+	Alpha.Querier
+	selects int
+}
+
+func (q *countingAlphas) DBSelectCtxTx(ctx context.Context, tx *sql.Tx, x *Alpha.Entity, params *Alpha.QueryParams) *Alpha.QueryResult {
+	q.selects++
+	return q.Querier.DBSelectCtxTx(ctx, tx, x, params)
+}
+
+func TestLoadRelations(t *testing.T) {
+	cat, dog := &Alpha.Entity{Uuid: "a1", Animal: "cat"}, &Alpha.Entity{Uuid: "a2", Animal: "dog"}
+	betas := Beta.NewFake(
+		&Beta.Entity{Uuid: "b2", AlphaUuid: Beta.NewNull("a1")},
+		&Beta.Entity{Uuid: "b1", AlphaUuid: Beta.NewNull("a1")},
+		&Beta.Entity{Uuid: "b3", AlphaUuid: Beta.NewNull("a3")},
+		&Beta.Entity{Uuid: "b4"},
+	)
+
+	loaded, err := LoadBetasFrom(nil, nil, betas, []*Alpha.Entity{cat, dog})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded) != 2 || loaded[0].Entity != cat || len(loaded[0].Betas) != 2 || loaded[0].Betas[0].Uuid != "b1" || len(loaded[1].Betas) != 0 {
+		t.Fatalf("unexpected one-to-many result %+v", loaded)
+	}
+
+	alphas := Alpha.NewFake(cat, dog)
+	owners, err := LoadAlphasFrom(nil, nil, alphas, betas.Rows())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(owners) != 4 || owners[0].Alpha == nil || owners[0].Alpha.Animal != "cat" || owners[0].Alpha != owners[1].Alpha {
+		t.Fatalf("expected the first two betas to share their alpha, got %+v", owners)
+	}
+	if owners[2].Alpha != nil || owners[3].Alpha != nil {
+		t.Fatal("expected no alpha for a missing row or a NULL key")
+	}
+}
+
+func TestLoadRelationsDuplicatesAndNil(t *testing.T) {
+	cat := &Alpha.Entity{Uuid: "a1", Animal: "cat"}
+	betas := &countingBetas{Querier: Beta.NewFake(&Beta.Entity{Uuid: "b1", AlphaUuid: Beta.NewNull("a1")})}
+
+	loaded, err := LoadBetasFrom(nil, nil, betas, []*Alpha.Entity{cat, cat})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if betas.selects != 1 || len(loaded) != 2 || len(loaded[0].Betas) != 1 || len(loaded[1].Betas) != 1 {
+		t.Fatalf("expected one select and the row on both duplicates, got %d selects, %+v", betas.selects, loaded)
+	}
+	if _, err = LoadBetasFrom(nil, nil, betas, []*Alpha.Entity{cat, nil}); err == nil {
+		t.Fatal("expected a nil alpha to be rejected")
+	}
+	if _, err = LoadAlphasFrom(nil, nil, Alpha.NewFake(cat), []*Beta.Entity{nil}); err == nil {
+		t.Fatal("expected a nil beta to be rejected")
+	}
+	if loaded, err = LoadBetasFrom(nil, nil, betas, nil); err != nil || len(loaded) != 0 || betas.selects != 1 {
+		t.Fatalf("expected no select for no alphas, got %d selects, %v", betas.selects, err)
+	}
+}
+
+func TestLoadRelationsChunks(t *testing.T) {
+	n := 2*relationChunk + 1
+	alphaRows := make([]*Alpha.Entity, n)
+	betaRows := make([]*Beta.Entity, n)
+	for i := range n {
+		u := "a" + strconv.Itoa(i)
+		alphaRows[i] = &Alpha.Entity{Uuid: u}
+		betaRows[i] = &Beta.Entity{Uuid: "b" + strconv.Itoa(i), AlphaUuid: Beta.NewNull(u)}
+	}
+
+	betas := &countingBetas{Querier: Beta.NewFake(betaRows...)}
+	loaded, err := LoadBetasFrom(nil, nil, betas, alphaRows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if betas.selects != 3 {
+		t.Fatalf("expected 3 selects for %d keys, got %d", n, betas.selects)
+	}
+	for i, w := range loaded {
+		if len(w.Betas) != 1 || w.Betas[0].AlphaUuid.V != w.Uuid {
+			t.Fatalf("alpha %d got %+v", i, w.Betas)
+		}
+	}
+
+	alphas := &countingAlphas{Querier: Alpha.NewFake(alphaRows...)}
+	owners, err := LoadAlphasFrom(nil, nil, alphas, betaRows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if alphas.selects != 3 {
+		t.Fatalf("expected 3 selects for %d keys, got %d", n, alphas.selects)
+	}
+	for i, w := range owners {
+		if w.Alpha == nil || w.Alpha.Uuid != w.AlphaUuid.V {
+			t.Fatalf("beta %d got %+v", i, w.Alpha)
+		}
+	}
+}
+
+func TestForeignKeysMatchSchema(t *testing.T) {
+	rows, err := c.Query("SELECT CONSTRAINT_NAME, TABLE_SCHEMA, TABLE_NAME, COLUMN_NAME, REFERENCED_TABLE_SCHEMA, REFERENCED_TABLE_NAME, REFERENCED_COLUMN_NAME" +
+		" FROM information_schema.KEY_COLUMN_USAGE WHERE TABLE_SCHEMA = 'template' AND REFERENCED_TABLE_NAME IS NOT NULL ORDER BY CONSTRAINT_NAME")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	fqtn := func(schema, table string) string {
+		return "`" + schema + "`.`" + table + "`"
+	}
+	var got []ForeignKey
+	for rows.Next() {
+		var fk ForeignKey
+		var schema, table, refSchema, refTable string
+		if err = rows.Scan(&fk.Name, &schema, &table, &fk.Column, &refSchema, &refTable, &fk.RefColumn); err != nil {
+			t.Fatal(err)
+		}
+		fk.Table, fk.RefTable = fqtn(schema, table), fqtn(refSchema, refTable)
+		got = append(got, fk)
+	}
+	if err = rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != len(ForeignKeys) {
+		t.Fatalf("expected %+v, got %+v", ForeignKeys, got)
+	}
+	for i := range got {
+		if got[i] != ForeignKeys[i] {
+			t.Fatalf("expected %+v, got %+v", ForeignKeys[i], got[i])
+		}
+	}
+}
+
+func TestLoadRelationsDatabase(t *testing.T) {
+	client, err := NewClient(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	n := relationChunk + 1
+	alphaRows := make([]*Alpha.Entity, n)
+	for i := range alphaRows {
+		alphaRows[i] = &Alpha.Entity{Uuid: uuid.NewString(), Animal: "relation-" + strconv.Itoa(i)}
+	}
+	if result := client.Alpha.DBInsertMany(alphaRows, Alpha.NewQueryParams().WithInsert(Alpha.FieldUuid, Alpha.FieldAnimal)); result.Error != nil {
+		t.Fatal(result.Error)
+	}
+	first, last := alphaRows[0], alphaRows[n-1]
+	betaRows := []*Beta.Entity{
+		{Uuid: uuid.NewString(), Name: "first-1", AlphaUuid: Beta.NewNull(first.Uuid)},
+		{Uuid: uuid.NewString(), Name: "first-2", AlphaUuid: Beta.NewNull(first.Uuid)},
+		{Uuid: uuid.NewString(), Name: "last", AlphaUuid: Beta.NewNull(last.Uuid)},
+		{Uuid: uuid.NewString(), Name: "orphan"},
+	}
+	params := Beta.NewQueryParams().WithInsert(Beta.FieldUuid, Beta.FieldName, Beta.FieldAlphaUuid)
+	for _, b := range betaRows {
+		if result := client.Beta.DBInsert(b, params); result.Error != nil {
+			t.Fatal(result.Error)
+		}
+	}
+	dangling := &Beta.Entity{Uuid: uuid.NewString(), Name: "dangling", AlphaUuid: Beta.NewNull(uuid.NewString())}
+	if result := client.Beta.DBInsert(dangling, params); !errors.Is(result.Error, Beta.ErrForeignKeyViolation) {
+		t.Fatalf("expected fk_beta_alpha to reject an unknown alpha, got %v", result.Error)
+	}
+
+	betas := &countingBetas{Querier: client.Beta}
+	loaded, err := LoadBetasFrom(context.Background(), nil, betas, append(alphaRows, first))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if betas.selects != 2 {
+		t.Fatalf("expected 2 selects for %d keys, got %d", n, betas.selects)
+	}
+	if len(loaded) != n+1 || len(loaded[0].Betas) != 2 || len(loaded[n].Betas) != 2 || len(loaded[n-1].Betas) != 1 || len(loaded[1].Betas) != 0 {
+		t.Fatalf("unexpected one-to-many result for %d alphas", len(loaded))
+	}
+	if !strings.HasPrefix(loaded[0].Betas[0].Name, "first-") || loaded[n-1].Betas[0].Name != "last" {
+		t.Fatalf("unexpected betas %+v, %+v", loaded[0].Betas, loaded[n-1].Betas)
+	}
+
+	if result := betaRows[1].DBDelete(Beta.NewQueryParams().WithWhere(Beta.FieldUuid)); result.Error != nil {
+		t.Fatal(result.Error)
+	}
+	if loaded, err = client.LoadBetas(context.Background(), []*Alpha.Entity{first}); err != nil || len(loaded[0].Betas) != 1 {
+		t.Fatalf("expected the soft-deleted beta to be skipped, got %v", err)
+	}
+
+	owners, err := client.LoadAlphas(context.Background(), betaRows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if owners[0].Alpha == nil || owners[0].Alpha.Uuid != first.Uuid || owners[0].Alpha != owners[1].Alpha {
+		t.Fatalf("expected the first two betas to share their alpha, got %+v", owners[:2])
+	}
+	if owners[2].Alpha == nil || owners[2].Alpha.Uuid != last.Uuid || owners[3].Alpha != nil {
+		t.Fatalf("unexpected many-to-one result %+v", owners[2:])
+	}
+}
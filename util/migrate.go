@@ -0,0 +1,46 @@
+package util
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Migrate runs the .sql files in dir in name order, one statement per ";".
+// The migrations are written to be idempotent, so it is safe to run them on
+// every test start.
+func Migrate(db *sql.DB, dir string) error {
+	files, err := filepath.Glob(filepath.Join(dir, "*.sql"))
+	if err != nil {
+		return err
+	}
+	sort.Strings(files)
+	for _, file := range files {
+		b, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		for _, stmt := range strings.Split(string(b), ";") {
+			if stmt = strings.TrimSpace(stripComments(stmt)); stmt == "" {
+				continue
+			}
+			if _, err = db.Exec(stmt); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func stripComments(stmt string) string {
+	lines := strings.Split(stmt, "\n")
+	kept := lines[:0]
+	for _, line := range lines {
+		if !strings.HasPrefix(strings.TrimSpace(line), "--") {
+			kept = append(kept, line)
+		}
+	}
+	return strings.Join(kept, "\n")
+}